@@ -40,7 +40,7 @@ func run(config *cli.Config) error {
 	}
 	defer func() { _ = logCloser.Close() }()
 
-	conn, err := bootstrap(config)
+	srv, conn, err := bootstrap(config)
 	if err != nil {
 		slog.Error("failed to create server", slog.Any("error", err))
 		return errors.Wrap(err, "failed to create server")
@@ -55,22 +55,28 @@ func run(config *cli.Config) error {
 		slog.Info("received signal", slog.Any("signal", sig))
 	case <-conn.DisconnectNotify():
 		slog.Info("connection lost")
+	case err = <-srv.ExitNotify():
+		if err != nil {
+			slog.Error("server exited abnormally", slog.Any("error", err))
+			return err
+		}
+		slog.Info("server exited")
 	}
 
 	slog.Info("server shutdown")
 	return nil
 }
 
-func bootstrap(config *cli.Config) (*jsonrpc2.Conn, error) {
+func bootstrap(config *cli.Config) (*server.Server, *jsonrpc2.Conn, error) {
 	ctx := context.Background()
 	span, _ := logging.StartSpan(ctx, "bootstrap")
 	defer span.Finish()
 
-	srv, err := server.New(ctx, version.GetVersion(), config.FilePatterns)
+	srv, err := server.New(ctx, version.GetVersion(), config.FilePatterns, config.WarmUpCache)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	stream := stdio.New(os.Stdin, os.Stdout)
-	handler := mux.New(srv.GetHandlers()).Handle
-	return connection.NewJSONRPC2(ctx, stream, handler), nil
+	m := mux.New(srv.GetHandlers())
+	return srv, connection.NewJSONRPC2(ctx, stream, m.Handle, m.TrackRequest), nil
 }
@@ -0,0 +1,33 @@
+// Package capabilities stores the client capabilities negotiated during initialization,
+// so that the handlers can adapt their responses to what the client supports.
+package capabilities
+
+import (
+	"sync/atomic"
+
+	"github.com/nobl9/nobl9-language-server/internal/messages"
+)
+
+// NewStore creates a new [Store].
+// Until [Store.Set] is called, the client is assumed to not support any optional capability.
+func NewStore() *Store {
+	return &Store{}
+}
+
+// Store holds the [messages.ClientCapabilities] received with [messages.InitializeMethod].
+type Store struct {
+	capabilities atomic.Pointer[messages.ClientCapabilities]
+}
+
+// Set stores the client capabilities.
+func (s *Store) Set(capabilities messages.ClientCapabilities) {
+	s.capabilities.Store(&capabilities)
+}
+
+// Get returns the client capabilities.
+func (s *Store) Get() messages.ClientCapabilities {
+	if capabilities := s.capabilities.Load(); capabilities != nil {
+		return *capabilities
+	}
+	return messages.ClientCapabilities{}
+}
@@ -7,11 +7,11 @@ import (
 	"path/filepath"
 	"strings"
 
-	"github.com/bmatcuk/doublestar/v4"
 	"github.com/pkg/errors"
 	"github.com/urfave/cli/v3"
 
 	"github.com/nobl9/nobl9-language-server/internal/config"
+	"github.com/nobl9/nobl9-language-server/internal/files"
 	"github.com/nobl9/nobl9-language-server/internal/logging"
 	"github.com/nobl9/nobl9-language-server/internal/version"
 )
@@ -27,6 +27,7 @@ type Config struct {
 	LogLevel     logging.Level
 	LogFilePath  string
 	FilePatterns []string
+	WarmUpCache  bool
 }
 
 func New(mainFunc func(*Config) error) *Command {
@@ -60,6 +61,13 @@ To learn more about Nobl9 configuration schema, visit: https://docs.nobl9.com/ya
 				Usage:  "Comma separated list of file patterns to process",
 				Action: parseStringWithEnvDefault("FILE_PATTERNS", cmd.parseFilePatterns),
 			},
+			&cli.BoolFlag{
+				Name: "warmUpCache",
+				Usage: "Fetch the most commonly referenced Nobl9 resources of every workspace folder " +
+					"once the client is initialized",
+				Sources:     cli.EnvVars(envPrefix + "WARM_UP_CACHE"),
+				Destination: &cmd.config.WarmUpCache,
+			},
 		},
 		Commands: []*cli.Command{
 			{
@@ -105,14 +113,9 @@ func (c *Command) parseFilePatterns(s string) error {
 	if strings.TrimSpace(s) == "" {
 		return nil
 	}
-	split := strings.Split(s, ",")
-	filePatterns := make([]string, 0, len(split))
-	for _, p := range split {
-		p = filepath.ToSlash(p)
-		if ok := doublestar.ValidatePattern(p); !ok {
-			return fmt.Errorf("invalid file pattern: %s", p)
-		}
-		filePatterns = append(filePatterns, p)
+	filePatterns, err := files.NormalizeFilePatterns(strings.Split(s, ","))
+	if err != nil {
+		return err
 	}
 	c.config.FilePatterns = filePatterns
 	return nil
@@ -17,23 +17,27 @@ var codeActionCommandNames = []string{
 }
 
 var codeActionCommands = map[string]struct {
-	Title          string
-	FailedMessage  string
-	SuccessMessage string
+	Title            string
+	FailedMessage    string
+	SuccessMessage   string
+	CancelledMessage string
 }{
 	commandApply: {
-		Title:          "Apply objects defined in this file",
-		FailedMessage:  "Failed to apply objects",
-		SuccessMessage: "Objects applied successfully",
+		Title:            "Apply objects defined in this file",
+		FailedMessage:    "Failed to apply objects",
+		SuccessMessage:   "Objects applied successfully",
+		CancelledMessage: "Applying objects was cancelled, some of them might have been applied already",
 	},
 	commandApplyDryRun: {
-		Title:          "Apply objects defined in this file (dry-run)",
-		FailedMessage:  "Failed to apply objects (dry-run)",
-		SuccessMessage: "Objects applied successfully (dry-run)",
+		Title:            "Apply objects defined in this file (dry-run)",
+		FailedMessage:    "Failed to apply objects (dry-run)",
+		SuccessMessage:   "Objects applied successfully (dry-run)",
+		CancelledMessage: "Applying objects was cancelled (dry-run)",
 	},
 	commandDelete: {
-		Title:          "Delete objects defined in this file",
-		FailedMessage:  "Failed to delete objects",
-		SuccessMessage: "Objects deleted successfully",
+		Title:            "Delete objects defined in this file",
+		FailedMessage:    "Failed to delete objects",
+		SuccessMessage:   "Objects deleted successfully",
+		CancelledMessage: "Deleting objects was cancelled, some of them might have been deleted already",
 	},
 }
@@ -1,30 +1,44 @@
 package codeactions
 
 import (
+	"cmp"
 	"context"
+	"fmt"
 	"log/slog"
+	"slices"
 
 	"github.com/nobl9/nobl9-go/manifest"
 	"github.com/pkg/errors"
 
 	"github.com/nobl9/nobl9-language-server/internal/files"
 	"github.com/nobl9/nobl9-language-server/internal/messages"
+	"github.com/nobl9/nobl9-language-server/internal/progress"
 )
 
 type clientNotifier interface {
 	Notify(ctx context.Context, method string, params any) error
 }
 
+type progressReporter interface {
+	Begin(ctx context.Context, token messages.ProgressToken, title string, total int) (context.Context, *progress.Progress)
+}
+
 type objectsRepo interface {
 	Apply(ctx context.Context, objects []manifest.Object) error
 	Delete(ctx context.Context, objects []manifest.Object) error
 }
 
-func NewHandler(files *files.FS, repo objectsRepo, notifier clientNotifier) *Handler {
+func NewHandler(
+	files *files.FS,
+	repo objectsRepo,
+	notifier clientNotifier,
+	progressReporter progressReporter,
+) *Handler {
 	return &Handler{
 		files:       files,
 		objectsRepo: repo,
 		notifier:    notifier,
+		progress:    progressReporter,
 	}
 }
 
@@ -32,6 +46,7 @@ type Handler struct {
 	files       *files.FS
 	objectsRepo objectsRepo
 	notifier    clientNotifier
+	progress    progressReporter
 }
 
 func (h *Handler) HandleCodeAction(_ context.Context, params messages.CodeActionParams) (any, error) {
@@ -71,20 +86,30 @@ func (h *Handler) HandleExecuteCommand(ctx context.Context, params messages.Exec
 	switch params.Command {
 	case commandApplyDryRun:
 	case commandApply:
-		err = h.objectsRepo.Apply(ctx, objects)
+		err = h.processObjects(ctx, params.WorkDoneToken, "Applying", objects, h.objectsRepo.Apply, false)
 	case commandDelete:
-		err = h.objectsRepo.Delete(ctx, objects)
+		err = h.processObjects(ctx, params.WorkDoneToken, "Deleting", objects, h.objectsRepo.Delete, true)
 	default:
 		return nil, errors.New("unknown command: " + params.Command)
 	}
+	// Don't report the outcome to the user if the request was cancelled.
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, ctxErr
+	}
 
 	var message messages.ShowMessageParams
-	if err != nil {
+	switch {
+	case errors.Is(err, progress.ErrCancelled):
+		message = messages.ShowMessageParams{
+			Type:    messages.MessageTypeWarning,
+			Message: codeActionCommands[params.Command].CancelledMessage,
+		}
+	case err != nil:
 		message = messages.ShowMessageParams{
 			Type:    messages.MessageTypeError,
 			Message: codeActionCommands[params.Command].FailedMessage,
 		}
-	} else {
+	default:
 		message = messages.ShowMessageParams{
 			Type:    messages.MessageTypeInfo,
 			Message: codeActionCommands[params.Command].SuccessMessage,
@@ -92,3 +117,97 @@ func (h *Handler) HandleExecuteCommand(ctx context.Context, params messages.Exec
 	}
 	return nil, h.notifier.Notify(ctx, messages.ShowMessageMethod, message)
 }
+
+// processObjects sends the objects to the Nobl9 API in batches of the same kind, see [batchObjects],
+// and reports the progress to the client after each batch.
+// It stops at the first error or once the client cancels the operation,
+// in which case the returned error is [progress.ErrCancelled].
+func (h *Handler) processObjects(
+	ctx context.Context,
+	token messages.ProgressToken,
+	verb string,
+	objects []manifest.Object,
+	process func(ctx context.Context, objects []manifest.Object) error,
+	reverse bool,
+) error {
+	ctx, p := h.progress.Begin(ctx, token, verb+" objects", len(objects))
+	message := "Finished"
+	defer func() { p.End(ctx, message) }()
+
+	batches := batchObjects(objects)
+	if reverse {
+		slices.Reverse(batches)
+	}
+	done := 0
+	for _, batch := range batches {
+		if err := context.Cause(ctx); err != nil {
+			message = "Cancelled"
+			return err
+		}
+		p.Report(ctx, done, fmt.Sprintf("%s %d/%d objects", verb, done+len(batch), len(objects)))
+		if err := process(ctx, batch); err != nil {
+			if cause := context.Cause(ctx); cause != nil {
+				message = "Cancelled"
+				return cause
+			}
+			message = "Failed"
+			slog.ErrorContext(ctx, "failed to process objects",
+				slog.String("kind", batch[0].GetKind().String()),
+				slog.Any("error", err))
+			return err
+		}
+		done += len(batch)
+	}
+	return nil
+}
+
+// kindsApplyOrder lists the kinds in the order their objects have to be applied in,
+// each kind follows the kinds its objects can reference.
+var kindsApplyOrder = []manifest.Kind{
+	manifest.KindProject,
+	manifest.KindUserGroup,
+	manifest.KindRoleBinding,
+	manifest.KindAgent,
+	manifest.KindDirect,
+	manifest.KindDataExport,
+	manifest.KindAlertMethod,
+	manifest.KindAlertPolicy,
+	manifest.KindService,
+	manifest.KindSLO,
+	manifest.KindAlertSilence,
+	manifest.KindAnnotation,
+	manifest.KindBudgetAdjustment,
+	manifest.KindReport,
+}
+
+// batchObjects groups the objects by their kind and orders the groups with [kindsApplyOrder].
+// Objects of the same kind are sent in a single batch, so that the Nobl9 API can order them,
+// e.g. composite SLOs and their components.
+// The order is reversed for deletion, the dependent objects have to go before their dependencies.
+// Kinds which are not listed go last.
+func batchObjects(objects []manifest.Object) [][]manifest.Object {
+	byKind := make(map[manifest.Kind][]manifest.Object)
+	var kinds []manifest.Kind
+	for _, object := range objects {
+		kind := object.GetKind()
+		if _, ok := byKind[kind]; !ok {
+			kinds = append(kinds, kind)
+		}
+		byKind[kind] = append(byKind[kind], object)
+	}
+	slices.SortStableFunc(kinds, func(k1, k2 manifest.Kind) int {
+		return cmp.Compare(getApplyOrder(k1), getApplyOrder(k2))
+	})
+	batches := make([][]manifest.Object, 0, len(kinds))
+	for _, kind := range kinds {
+		batches = append(batches, byKind[kind])
+	}
+	return batches
+}
+
+func getApplyOrder(kind manifest.Kind) int {
+	if i := slices.Index(kindsApplyOrder, kind); i >= 0 {
+		return i
+	}
+	return len(kindsApplyOrder)
+}
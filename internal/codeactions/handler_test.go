@@ -0,0 +1,24 @@
+package codeactions
+
+import (
+	"testing"
+
+	"github.com/nobl9/nobl9-go/manifest"
+	v1alphaProject "github.com/nobl9/nobl9-go/manifest/v1alpha/project"
+	v1alphaService "github.com/nobl9/nobl9-go/manifest/v1alpha/service"
+	v1alphaSLO "github.com/nobl9/nobl9-go/manifest/v1alpha/slo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchObjects(t *testing.T) {
+	slo := v1alphaSLO.New(v1alphaSLO.Metadata{Name: "slo"}, v1alphaSLO.Spec{})
+	composite := v1alphaSLO.New(v1alphaSLO.Metadata{Name: "composite"}, v1alphaSLO.Spec{})
+	service := v1alphaService.New(v1alphaService.Metadata{Name: "service"}, v1alphaService.Spec{})
+	project := v1alphaProject.New(v1alphaProject.Metadata{Name: "project"}, v1alphaProject.Spec{})
+
+	assert.Equal(t, [][]manifest.Object{
+		{project},
+		{service},
+		{composite, slo},
+	}, batchObjects([]manifest.Object{composite, service, slo, project}))
+}
@@ -49,6 +49,7 @@ func (h *Handler) Handle(ctx context.Context, params messages.CompletionParams)
 		slog.DebugContext(ctx, "skipping file")
 		return nil, nil
 	}
+	params.Position = file.GetPositionMapper().FromClientPosition(params.Position)
 
 	var (
 		node *files.SimpleObjectNode
@@ -67,6 +68,10 @@ func (h *Handler) Handle(ctx context.Context, params messages.CompletionParams)
 
 	var items []messages.CompletionItem
 	for _, provider := range h.providers {
+		// Stop early if the request was cancelled, e.g. when the user keeps typing.
+		if err = ctx.Err(); err != nil {
+			return nil, err
+		}
 		switch provider.getType() {
 		case keysCompletionType:
 			isEmptyList := !line.IsType(yamlastsimple.LineTypeMapping) && line.IsType(yamlastsimple.LineTypeList)
@@ -85,5 +90,8 @@ func (h *Handler) Handle(ctx context.Context, params messages.CompletionParams)
 		}
 		items = append(items, provider.Complete(ctx, params, file.SimpleAST, node, line)...)
 	}
+	if err = ctx.Err(); err != nil {
+		return nil, err
+	}
 	return items, nil
 }
@@ -12,12 +12,14 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/nobl9/nobl9-language-server/internal/capabilities"
 	"github.com/nobl9/nobl9-language-server/internal/files"
 	"github.com/nobl9/nobl9-language-server/internal/messages"
 	"github.com/nobl9/nobl9-language-server/internal/nobl9repo"
 	"github.com/nobl9/nobl9-language-server/internal/objectref"
 	"github.com/nobl9/nobl9-language-server/internal/sdkdocs"
 	"github.com/nobl9/nobl9-language-server/internal/testutils"
+	"github.com/nobl9/nobl9-language-server/internal/workspace"
 )
 
 var testDir = filepath.Join(testutils.FindModuleRoot(), "internal", "completion", "testdata")
@@ -44,10 +46,10 @@ func TestHandler_Handle(t *testing.T) {
 	handler := &Handler{
 		files: fileSystem,
 		providers: []providerInterface{
-			NewReferencesCompletionProvider(repo),
+			NewReferencesCompletionProvider(repo, workspace.NewObjectsResolver(files.NewFS(nil), repo, noFileScopes{})),
 			NewKeysCompletionProvider(docs),
 			NewValuesCompletionProvider(docs),
-			NewSnippetsProvider(),
+			NewSnippetsProvider(newCapabilitiesStore(true)),
 		},
 	}
 
@@ -459,6 +461,38 @@ spec:
 	}
 }
 
+func TestSnippetsProvider_NoSnippetSupport(t *testing.T) {
+	t.Parallel()
+
+	fileSystem := files.NewFS(nil)
+	testutils.RegisterTestFiles(t, fileSystem, testDir)
+	handler := &Handler{
+		files:     fileSystem,
+		providers: []providerInterface{NewSnippetsProvider(newCapabilitiesStore(false))},
+	}
+
+	result, err := handler.Handle(context.Background(), messages.CompletionParams{
+		TextDocumentPositionParams: messages.TextDocumentPositionParams{
+			TextDocument: getTestFileURI("snippet.yaml"),
+			Position:     messages.Position{Line: 1, Character: 0},
+		},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, result)
+}
+
+func newCapabilitiesStore(snippetSupport bool) *capabilities.Store {
+	store := capabilities.NewStore()
+	store.Set(messages.ClientCapabilities{
+		TextDocument: &messages.TextDocumentClientCapabilities{
+			Completion: &messages.CompletionClientCapabilities{
+				CompletionItem: &messages.CompletionItemClientCapabilities{SnippetSupport: snippetSupport},
+			},
+		},
+	})
+	return store
+}
+
 func getReferenceCompletionTestCases() map[string]handlerTestCase {
 	tests := map[string]messages.Position{
 		"service project names - value start":                                   {Line: 4, Character: 11},
@@ -686,6 +720,13 @@ var (
 	}()
 )
 
+// noFileScopes binds no [nobl9repo.Scope], all the workspace files share the same one.
+type noFileScopes struct{}
+
+func (noFileScopes) WithFileScope(ctx context.Context, _ files.URI) context.Context { return ctx }
+
+func (noFileScopes) Generation() uint64 { return 0 }
+
 type mockObjectsRepo struct {
 	names []string
 }
@@ -719,7 +760,7 @@ func (m mockObjectsRepo) GetObject(
 	return nil, nil
 }
 
-func (m mockObjectsRepo) GetDefaultProject() string {
+func (m mockObjectsRepo) GetDefaultProject(context.Context) string {
 	return "default"
 }
 
@@ -17,22 +17,27 @@ import (
 )
 
 type objectsRepo interface {
-	GetAllNames(ctx context.Context, kind manifest.Kind, project string) ([]string, error)
-	GetObject(ctx context.Context, kind manifest.Kind, name, project string) (manifest.Object, error)
 	GetUsers(ctx context.Context, phrase string) ([]*nobl9repo.User, error)
 	GetRoles(ctx context.Context) (*nobl9repo.Roles, error)
 }
 
+// objectsResolver looks up the objects in the workspace first and then in the Nobl9 API.
+type objectsResolver interface {
+	GetAllNames(ctx context.Context, kind manifest.Kind, project string) ([]string, error)
+	GetObject(ctx context.Context, kind manifest.Kind, name, project string) (manifest.Object, error)
+}
+
 type docsProvider interface {
 	GetProperty(kind manifest.Kind, path string) *sdkdocs.PropertyDoc
 }
 
-func NewReferencesCompletionProvider(repo objectsRepo) *ReferencesCompletionProvider {
-	return &ReferencesCompletionProvider{repo: repo}
+func NewReferencesCompletionProvider(repo objectsRepo, resolver objectsResolver) *ReferencesCompletionProvider {
+	return &ReferencesCompletionProvider{repo: repo, resolver: resolver}
 }
 
 type ReferencesCompletionProvider struct {
-	repo objectsRepo
+	repo     objectsRepo
+	resolver objectsResolver
 }
 
 func (p ReferencesCompletionProvider) getType() completionProviderType {
@@ -84,7 +89,7 @@ func (p ReferencesCompletionProvider) completeObjectNames(
 	if projectName == "" && objectref.IsProjectScoped(ref.Kind) {
 		return nil
 	}
-	names, err := p.repo.GetAllNames(ctx, ref.Kind, projectName)
+	names, err := p.resolver.GetAllNames(ctx, ref.Kind, projectName)
 	if err != nil {
 		slog.ErrorContext(ctx, "failed to get all names",
 			slog.String("kind", ref.Kind.String()),
@@ -178,7 +183,7 @@ func (p ReferencesCompletionProvider) completeObjectiveNames(
 	if projectName == "" {
 		return nil
 	}
-	object, err := p.repo.GetObject(ctx, manifest.KindSLO, sloName, projectName)
+	object, err := p.resolver.GetObject(ctx, manifest.KindSLO, sloName, projectName)
 	if err != nil {
 		slog.ErrorContext(ctx, "failed to get SLO object", slog.String("error", err.Error()))
 		return nil
@@ -16,12 +16,20 @@ import (
 //go:embed snippets.json
 var snippetsJSONData []byte
 
-func NewSnippetsProvider() *SnippetsProvider {
-	return &SnippetsProvider{items: setupSnippetItems()}
+type clientCapabilities interface {
+	Get() messages.ClientCapabilities
+}
+
+func NewSnippetsProvider(capabilities clientCapabilities) *SnippetsProvider {
+	return &SnippetsProvider{
+		items:        setupSnippetItems(),
+		capabilities: capabilities,
+	}
 }
 
 type SnippetsProvider struct {
-	items []messages.CompletionItem
+	items        []messages.CompletionItem
+	capabilities clientCapabilities
 }
 
 func (p SnippetsProvider) getType() completionProviderType {
@@ -35,6 +43,10 @@ func (p SnippetsProvider) Complete(
 	_ *files.SimpleObjectNode,
 	line *yamlastsimple.Line,
 ) []messages.CompletionItem {
+	// Snippet syntax would be inserted verbatim by clients which don't support it.
+	if !p.capabilities.Get().SupportsSnippets() {
+		return nil
+	}
 	path := line.GeneralizedPath
 	// We only support completions for the root of the document.
 	if path != "$" {
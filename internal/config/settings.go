@@ -0,0 +1,54 @@
+package config
+
+// SettingsSection is the name of the configuration section which holds the [Settings].
+const SettingsSection = "nobl9"
+
+// Settings are the server settings which can be changed by the client at runtime,
+// either pulled with workspace/configuration or pushed with workspace/didChangeConfiguration.
+// Empty values fall back to the command line flags (or their environment variables).
+// Only the [Settings.Project] and [Settings.Context] can differ between workspace folders.
+type Settings struct {
+	// LogLevel is one of: TRACE, DEBUG, INFO, WARN, ERROR.
+	LogLevel string `json:"logLevel,omitempty"`
+	// FilePatterns limit the files the server works with, see --filePatterns flag.
+	FilePatterns []string `json:"filePatterns,omitempty"`
+	// CacheTTL is the duration, for example "10m", for which Nobl9 API responses are cached.
+	CacheTTL    string              `json:"cacheTTL,omitempty"`
+	Diagnostics DiagnosticsSettings `json:"diagnostics"`
+	// Project is the default Project, it overrides the one defined by the Nobl9 configuration context.
+	Project string `json:"project,omitempty"`
+	// Context is the name of the Nobl9 configuration file context used to access the Nobl9 API.
+	Context string `json:"context,omitempty"`
+}
+
+// DiagnosticsSettings toggle the diagnostics, all of them except for [DiagnosticsSettings.Unused]
+// and [DiagnosticsSettings.UnusedPlatform] are enabled by default.
+type DiagnosticsSettings struct {
+	// Enabled turns all the diagnostics on or off.
+	Enabled *bool `json:"enabled,omitempty"`
+	// Deprecated turns the warnings about deprecated properties on or off.
+	Deprecated *bool `json:"deprecated,omitempty"`
+	// References turns the validation of the referenced Nobl9 resources against the API on or off.
+	References *bool `json:"references,omitempty"`
+	// Unused turns the hints about the objects which are not referenced by any other workspace object on or off.
+	Unused *bool `json:"unused,omitempty"`
+	// UnusedPlatform makes the [DiagnosticsSettings.Unused] hints consider the references
+	// from the objects on the Nobl9 platform as well.
+	// The objects are fetched only when the whole workspace is diagnosed (workspace/diagnostic request),
+	// the setting has no effect on the published diagnostics nor on the ones pulled for a single document.
+	UnusedPlatform *bool `json:"unusedPlatform,omitempty"`
+}
+
+func (d DiagnosticsSettings) IsEnabled() bool { return isEnabled(d.Enabled) }
+
+func (d DiagnosticsSettings) IsDeprecatedEnabled() bool { return isEnabled(d.Deprecated) }
+
+func (d DiagnosticsSettings) IsReferencesEnabled() bool { return isEnabled(d.References) }
+
+func (d DiagnosticsSettings) IsUnusedEnabled() bool { return d.Unused != nil && *d.Unused }
+
+func (d DiagnosticsSettings) IsUnusedPlatformEnabled() bool {
+	return d.UnusedPlatform != nil && *d.UnusedPlatform
+}
+
+func isEnabled(v *bool) bool { return v == nil || *v }
@@ -9,12 +9,43 @@ import (
 
 type JSONRPC2Handler func(context.Context, *jsonrpc2.Conn, *jsonrpc2.Request) (result interface{}, err error)
 
+// RequestTracker registers the request as in-flight until the returned [context.CancelFunc] is called.
+type RequestTracker func(ctx context.Context, id jsonrpc2.ID) (context.Context, context.CancelFunc)
+
 // NewJSONRPC2 creates a new [JSON RPC 2.0] connection.
 //
 // [JSON RPC 2.0]: https://www.jsonrpc.org/specification
-func NewJSONRPC2(ctx context.Context, stream io.ReadWriteCloser, handler JSONRPC2Handler) *jsonrpc2.Conn {
+func NewJSONRPC2(
+	ctx context.Context,
+	stream io.ReadWriteCloser,
+	handler JSONRPC2Handler,
+	tracker RequestTracker,
+) *jsonrpc2.Conn {
 	return jsonrpc2.NewConn(
 		ctx,
 		jsonrpc2.NewBufferedStream(stream, jsonrpc2.VSCodeObjectCodec{}),
-		jsonrpc2.HandlerWithError(handler))
+		asyncRequestsHandler{handler: jsonrpc2.HandlerWithError(handler), track: tracker})
+}
+
+// asyncRequestsHandler handles notifications synchronously, in the order they were received,
+// and requests concurrently, which allows them to be cancelled while they're still in progress.
+// Since document changes are delivered via notifications, every request observes
+// all the changes which were sent before it.
+// Requests are tracked before they're handed off, this way a cancellation
+// which immediately follows the request is never missed.
+type asyncRequestsHandler struct {
+	handler jsonrpc2.Handler
+	track   RequestTracker
+}
+
+func (a asyncRequestsHandler) Handle(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) {
+	if req.Notif {
+		a.handler.Handle(ctx, conn, req)
+		return
+	}
+	ctx, done := a.track(ctx, req.ID)
+	go func() {
+		defer done()
+		a.handler.Handle(ctx, conn, req)
+	}()
 }
@@ -0,0 +1,65 @@
+package definition
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/nobl9/nobl9-language-server/internal/files"
+	"github.com/nobl9/nobl9-language-server/internal/messages"
+	"github.com/nobl9/nobl9-language-server/internal/workspace"
+)
+
+func NewHandler(files *files.FS, snapshots *workspace.Snapshots) *Handler {
+	return &Handler{files: files, snapshots: snapshots}
+}
+
+type Handler struct {
+	files     *files.FS
+	snapshots *workspace.Snapshots
+}
+
+// Handle resolves the object reference under the cursor and returns
+// the locations of all the objects it points to.
+func (h *Handler) Handle(ctx context.Context, params messages.DocumentDefinitionParams) (any, error) {
+	file, err := h.files.GetFile(params.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+	ctx = file.AddToLogContext(ctx)
+	if file.Skip {
+		slog.DebugContext(ctx, "skipping file")
+		return nil, nil
+	}
+
+	position := file.GetPositionMapper().FromClientPosition(params.Position)
+	snapshot := h.snapshots.Get()
+	object, value := snapshot.FindValue(file.URI, position)
+	if object == nil || value == nil {
+		slog.DebugContext(ctx, "no value found", slog.Any("position", position))
+		return nil, nil
+	}
+	ref := workspace.ResolveReference(object, value)
+	if ref == nil {
+		slog.DebugContext(ctx, "value is not a reference", slog.String("path", value.Path))
+		return nil, nil
+	}
+
+	var locations []messages.Location
+	for _, target := range snapshot.FindObjects(ref.Target) {
+		if ref.Objective == "" {
+			locations = append(locations, snapshot.ToClientLocation(target.Location()))
+			continue
+		}
+		if location, ok := target.ObjectiveLocation(ref.Objective); ok {
+			locations = append(locations, snapshot.ToClientLocation(location))
+		}
+	}
+	if len(locations) == 0 {
+		slog.DebugContext(ctx, "referenced object not found",
+			slog.String("kind", ref.Target.Kind.String()),
+			slog.String("name", ref.Target.Name),
+			slog.String("project", ref.Target.Project))
+		return nil, nil
+	}
+	return locations, nil
+}
@@ -0,0 +1,149 @@
+package definition
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nobl9/nobl9-language-server/internal/files"
+	"github.com/nobl9/nobl9-language-server/internal/messages"
+	"github.com/nobl9/nobl9-language-server/internal/testutils"
+	"github.com/nobl9/nobl9-language-server/internal/workspace"
+)
+
+var testDir = filepath.Join(testutils.FindModuleRoot(), "internal", "definition", "testdata")
+
+func TestHandler_Handle(t *testing.T) {
+	t.Parallel()
+
+	fileSystem := files.NewFS(nil)
+	testutils.RegisterTestFiles(t, fileSystem, testDir)
+	handler := NewHandler(fileSystem, workspace.NewSnapshots(fileSystem))
+
+	tests := map[string]struct {
+		uri      string
+		position messages.Position
+		expected []messages.Location
+	}{
+		"service reference": {
+			uri:      getTestFileURI("slos.yaml"),
+			position: messages.Position{Line: 6, Character: 14},
+			expected: []messages.Location{{
+				URI:   getTestFileURI("services.yaml"),
+				Range: messages.NewLineRange(10, 8, 18),
+			}},
+		},
+		"quoted service reference": {
+			uri:      getTestFileURI("slos.yaml"),
+			position: messages.Position{Line: 33, Character: 14},
+			expected: []messages.Location{{
+				URI:   getTestFileURI("services.yaml"),
+				Range: messages.NewLineRange(10, 8, 18),
+			}},
+		},
+		"project reference": {
+			uri:      getTestFileURI("slos.yaml"),
+			position: messages.Position{Line: 4, Character: 12},
+			expected: []messages.Location{{
+				URI:   getTestFileURI("services.yaml"),
+				Range: messages.NewLineRange(4, 8, 14),
+			}},
+		},
+		"flow sequence alert policy reference": {
+			uri:      getTestFileURI("slos.yaml"),
+			position: messages.Position{Line: 7, Character: 20},
+			expected: []messages.Location{{
+				URI:   getTestFileURI("alerting.yaml"),
+				Range: messages.NewLineRange(12, 10, 19),
+			}},
+		},
+		"block sequence alert policy reference": {
+			uri:      getTestFileURI("slos.yaml"),
+			position: messages.Position{Line: 35, Character: 8},
+			expected: []messages.Location{{
+				URI:   getTestFileURI("alerting.yaml"),
+				Range: messages.NewLineRange(12, 10, 19),
+			}},
+		},
+		"metric source reference": {
+			uri:      getTestFileURI("slos.yaml"),
+			position: messages.Position{Line: 10, Character: 14},
+			expected: []messages.Location{{
+				URI:   getTestFileURI("slos.yaml"),
+				Range: messages.NewLineRange(64, 8, 18),
+			}},
+		},
+		"composite objective SLO reference": {
+			uri:      getTestFileURI("slos.yaml"),
+			position: messages.Position{Line: 50, Character: 22},
+			expected: []messages.Location{{
+				URI:   getTestFileURI("slos.yaml"),
+				Range: messages.NewLineRange(4, 8, 19),
+			}},
+		},
+		"composite objective reference": {
+			uri:      getTestFileURI("slos.yaml"),
+			position: messages.Position{Line: 51, Character: 26},
+			expected: []messages.Location{{
+				URI:   getTestFileURI("slos.yaml"),
+				Range: messages.NewLineRange(19, 12, 16),
+			}},
+		},
+		"alert method reference in list document": {
+			uri:      getTestFileURI("alerting.yaml"),
+			position: messages.Position{Line: 22, Character: 16},
+			expected: []messages.Location{{
+				URI:   getTestFileURI("alerting.yaml"),
+				Range: messages.NewLineRange(4, 10, 15),
+			}},
+		},
+		"missing object": {
+			uri:      getTestFileURI("slos.yaml"),
+			position: messages.Position{Line: 55, Character: 22},
+		},
+		"not a reference": {
+			uri:      getTestFileURI("slos.yaml"),
+			position: messages.Position{Line: 11, Character: 22},
+		},
+		"key instead of value": {
+			uri:      getTestFileURI("slos.yaml"),
+			position: messages.Position{Line: 6, Character: 4},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			result, err := handler.Handle(context.Background(), messages.DocumentDefinitionParams{
+				TextDocumentPositionParams: messages.TextDocumentPositionParams{
+					TextDocument: messages.TextDocumentIdentifier{URI: tc.uri},
+					Position:     tc.position,
+				},
+			})
+			require.NoError(t, err)
+			if tc.expected == nil {
+				assert.Nil(t, result)
+				return
+			}
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestHandler_Handle_FileNotFound(t *testing.T) {
+	fileSystem := files.NewFS(nil)
+	handler := NewHandler(fileSystem, workspace.NewSnapshots(fileSystem))
+	_, err := handler.Handle(context.Background(), messages.DocumentDefinitionParams{
+		TextDocumentPositionParams: messages.TextDocumentPositionParams{
+			TextDocument: messages.TextDocumentIdentifier{URI: "file:///foo.yaml"},
+		},
+	})
+	require.Error(t, err)
+}
+
+func getTestFileURI(name string) string {
+	return filepath.Join(testDir, name)
+}
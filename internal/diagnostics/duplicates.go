@@ -0,0 +1,92 @@
+package diagnostics
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nobl9/nobl9-go/manifest"
+
+	"github.com/nobl9/nobl9-language-server/internal/config"
+	"github.com/nobl9/nobl9-language-server/internal/files"
+	"github.com/nobl9/nobl9-language-server/internal/messages"
+	"github.com/nobl9/nobl9-language-server/internal/nobl9repo"
+	"github.com/nobl9/nobl9-language-server/internal/objectref"
+	"github.com/nobl9/nobl9-language-server/internal/yamlast"
+)
+
+// objectIdentity is what makes two definitions of an object overwrite each other when applied.
+type objectIdentity struct {
+	configContext string
+	project       string
+}
+
+// checkDuplicates reports objects of the same kind, name and project defined more than once in the workspace.
+// Objects without a project are assigned to the default project, just like when they're applied.
+// Only the definitions from the workspace folders which share the file's [nobl9repo.Scope] are checked,
+// as the other ones are applied to different organizations or default projects.
+func (d Provider) checkDuplicates(ctx context.Context, uri files.URI, object *files.ObjectNode) []messages.Diagnostic {
+	if object.Object == nil || object.Node == nil {
+		return nil
+	}
+	name := object.Object.GetName()
+	if name == "" {
+		return nil
+	}
+	identity := d.getObjectIdentity(ctx, object.Object)
+	var related []messages.DiagnosticRelatedInformation
+	for _, duplicate := range d.resolver.FindLocalObjects(ctx, object.Kind, name) {
+		if duplicate.Location.URI == uri && isWithinNode(duplicate.Location.Range, object.Node) {
+			continue
+		}
+		if d.getObjectIdentity(ctx, duplicate.Object) != identity {
+			continue
+		}
+		related = append(related, messages.DiagnosticRelatedInformation{
+			Location: *duplicate.Location,
+			Message:  "duplicate definition",
+		})
+	}
+	if len(related) == 0 {
+		return nil
+	}
+	message := fmt.Sprintf("%s %s is defined more than once", object.Kind, name)
+	if identity.project != "" {
+		message += fmt.Sprintf(" in Project %s", identity.project)
+	}
+	message += ", the definitions will overwrite each other when applied"
+	return []messages.Diagnostic{{
+		Range:              getRangeForNodePath(ctx, object.Node, "$.metadata.name"),
+		Severity:           messages.DiagnosticSeverityError,
+		Source:             ptr(config.ServerName),
+		Message:            message,
+		RelatedInformation: related,
+	}}
+}
+
+func (d Provider) getObjectIdentity(ctx context.Context, object manifest.Object) objectIdentity {
+	var project string
+	if projectScoped, ok := object.(manifest.ProjectScopedObject); ok {
+		project = projectScoped.GetProject()
+	}
+	return d.getIdentity(ctx, object.GetKind(), project)
+}
+
+// getIdentity returns the [objectIdentity] within the [nobl9repo.Scope] bound to the context.
+func (d Provider) getIdentity(ctx context.Context, kind manifest.Kind, project string) objectIdentity {
+	identity := objectIdentity{configContext: nobl9repo.GetScope(ctx).ConfigContext}
+	if !objectref.IsProjectScoped(kind) {
+		return identity
+	}
+	identity.project = project
+	if identity.project == "" {
+		identity.project = d.objects.GetDefaultProject(ctx)
+	}
+	return identity
+}
+
+// isWithinNode checks if the range lies within the object's lines.
+func isWithinNode(rng messages.Range, node *yamlast.Node) bool {
+	// Objects' lines are 1-based.
+	line := rng.Start.Line + 1
+	return line >= node.StartLine && line <= node.EndLine
+}
@@ -5,25 +5,60 @@ import (
 	"context"
 	"log/slog"
 	"slices"
+	"sync/atomic"
 
 	"github.com/nobl9/nobl9-language-server/internal/files"
 	"github.com/nobl9/nobl9-language-server/internal/messages"
+	"github.com/nobl9/nobl9-language-server/internal/progress"
+	"github.com/nobl9/nobl9-language-server/internal/workspace"
 )
 
 type providerInterface interface {
 	DiagnoseFile(ctx context.Context, file *files.File) []messages.Diagnostic
+	// CacheEpoch identifies the Nobl9 API data the diagnostics were computed with.
+	CacheEpoch() uint64
 }
 
-func NewHandler(fs *files.FS, provider providerInterface) *Handler {
+type progressReporter interface {
+	BeginRequested(
+		ctx context.Context,
+		token messages.ProgressToken,
+		title string,
+		total int,
+	) (context.Context, *progress.Progress)
+}
+
+type clientCapabilities interface {
+	Get() messages.ClientCapabilities
+}
+
+func NewHandler(
+	fs *files.FS,
+	provider providerInterface,
+	progressReporter progressReporter,
+	capabilities clientCapabilities,
+) *Handler {
 	return &Handler{
-		fs:          fs,
-		diagnostics: provider,
+		fs:           fs,
+		diagnostics:  provider,
+		progress:     progressReporter,
+		capabilities: capabilities,
 	}
 }
 
 type Handler struct {
-	fs          *files.FS
-	diagnostics providerInterface
+	fs           *files.FS
+	diagnostics  providerInterface
+	progress     progressReporter
+	capabilities clientCapabilities
+	// settingsGeneration is incremented whenever the settings affecting the diagnostics change.
+	settingsGeneration atomic.Uint64
+}
+
+// Invalidate marks all the previously computed pull diagnostics results as outdated.
+// It should be called whenever the settings affecting the diagnostics change.
+func (h *Handler) Invalidate() {
+	h.settingsGeneration.Add(1)
 }
 
 func (h *Handler) Handle(ctx context.Context, item messages.TextDocumentItem) (any, error) {
@@ -37,11 +72,48 @@ func (h *Handler) Handle(ctx context.Context, item messages.TextDocumentItem) (a
 		return nil, nil
 	}
 
-	params := messages.PublishDiagnosticsParams{
-		URI:     item.URI,
-		Version: item.Version,
+	diags := h.diagnose(ctx, file)
+	// If the file has changed, the context is cancelled and we don't want to send diagnostics for the old version.
+	if ctx.Err() != nil {
+		slog.DebugContext(ctx, "diagnostics cancelled, skipping", slog.Any("error", ctx.Err()))
+		return nil, nil
 	}
+	return &messages.PublishDiagnosticsParams{
+		URI:         item.URI,
+		Version:     item.Version,
+		Diagnostics: diags,
+	}, nil
+}
+
+// diagnose returns the file's diagnostics sorted by their position.
+// The ranges, including the related information in other files, are converted to the client's position encoding.
+// Related information is only included if the client supports it.
+func (h *Handler) diagnose(ctx context.Context, file *files.File) []messages.Diagnostic {
 	diags := h.diagnostics.DiagnoseFile(ctx, file)
+	mapper := file.GetPositionMapper()
+	clientCapabilities := h.capabilities.Get()
+	supportsRelatedInformation := clientCapabilities.SupportsRelatedInformation()
+	// The snapshot is only created if any related information points to another file.
+	var snapshot *workspace.Snapshot
+	for i := range diags {
+		diags[i].Range = mapper.ToClientRange(diags[i].Range)
+		diags[i].Tags = filterSupportedTags(diags[i].Tags, clientCapabilities)
+		if !supportsRelatedInformation {
+			diags[i].RelatedInformation = nil
+			continue
+		}
+		for j, info := range diags[i].RelatedInformation {
+			if info.Location.URI == file.URI {
+				diags[i].RelatedInformation[j].Location.Range = mapper.ToClientRange(info.Location.Range)
+				continue
+			}
+			// Other files' ranges have to be converted with their own mappers.
+			if snapshot == nil {
+				snapshot = workspace.NewSnapshot(h.fs.GetFiles())
+			}
+			diags[i].RelatedInformation[j].Location = snapshot.ToClientLocation(info.Location)
+		}
+	}
 	slices.SortFunc(diags, func(d1, d2 messages.Diagnostic) int {
 		return cmp.Or(
 			cmp.Compare(d1.Range.Start.Line, d2.Range.Start.Line),
@@ -53,12 +125,19 @@ func (h *Handler) Handle(ctx context.Context, item messages.TextDocumentItem) (a
 	if len(diags) == 0 {
 		diags = make([]messages.Diagnostic, 0)
 	}
-	// If the file has changed we don't want to send diagnostics for the old version.
-	if file, err = h.fs.GetFile(item.URI); err == nil && file.Version != item.Version {
-		slog.DebugContext(ctx, "file version has changed, skipping diagnostics",
-			slog.Int("newVersion", file.Version))
-		return nil, nil
+	return diags
+}
+
+// filterSupportedTags drops the [messages.DiagnosticTag] which the client does not support.
+func filterSupportedTags(
+	tags []messages.DiagnosticTag,
+	capabilities messages.ClientCapabilities,
+) []messages.DiagnosticTag {
+	var supported []messages.DiagnosticTag
+	for _, tag := range tags {
+		if capabilities.SupportsDiagnosticTag(tag) {
+			supported = append(supported, tag)
+		}
 	}
-	params.Diagnostics = diags
-	return &params, nil
+	return supported
 }
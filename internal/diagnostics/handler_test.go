@@ -2,21 +2,27 @@ package diagnostics
 
 import (
 	"context"
+	"fmt"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/nobl9/nobl9-go/manifest"
 	"github.com/nobl9/nobl9-go/manifest/v1alpha"
+	v1alphaAlertPolicy "github.com/nobl9/nobl9-go/manifest/v1alpha/alertpolicy"
 	v1alphaSLO "github.com/nobl9/nobl9-go/manifest/v1alpha/slo"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/nobl9/nobl9-language-server/internal/capabilities"
 	"github.com/nobl9/nobl9-language-server/internal/config"
 	"github.com/nobl9/nobl9-language-server/internal/files"
 	"github.com/nobl9/nobl9-language-server/internal/messages"
 	"github.com/nobl9/nobl9-language-server/internal/nobl9repo"
+	"github.com/nobl9/nobl9-language-server/internal/progress"
 	"github.com/nobl9/nobl9-language-server/internal/sdkdocs"
 	"github.com/nobl9/nobl9-language-server/internal/testutils"
+	"github.com/nobl9/nobl9-language-server/internal/workspace"
 )
 
 func TestHandler_Handle(t *testing.T) {
@@ -30,12 +36,14 @@ func TestHandler_Handle(t *testing.T) {
 
 	docs, err := sdkdocs.New()
 	require.NoError(t, err)
-	provider := NewProvider(docs, objectsProviderMock{})
+	provider := NewProvider(
+		docs,
+		objectsProviderMock{},
+		workspace.NewObjectsResolver(files.NewFS(nil), objectsProviderMock{}, fileScopesMock{}),
+		fileScopesMock{},
+	)
 
-	handler := Handler{
-		fs:          fileSystem,
-		diagnostics: provider,
-	}
+	handler := NewHandler(fileSystem, provider, progress.NewReporter(nil), newCapabilitiesStore(true))
 
 	tests := map[string]struct {
 		item     messages.TextDocumentItem
@@ -164,7 +172,7 @@ func TestHandler_Handle(t *testing.T) {
 				Version: 1,
 				Diagnostics: []messages.Diagnostic{
 					{
-						Message:  "Agent does not exist in Project default",
+						Message:  "Agent does not exist in Project default (neither in the workspace nor in Nobl9)",
 						Severity: messages.DiagnosticSeverityError,
 						Source:   ptr(config.ServerName),
 						Range: messages.Range{
@@ -173,7 +181,7 @@ func TestHandler_Handle(t *testing.T) {
 						},
 					},
 					{
-						Message:  "Service does not exist in Project default",
+						Message:  "Service does not exist in Project default (neither in the workspace nor in Nobl9)",
 						Severity: messages.DiagnosticSeverityError,
 						Source:   ptr(config.ServerName),
 						Range: messages.Range{
@@ -182,7 +190,7 @@ func TestHandler_Handle(t *testing.T) {
 						},
 					},
 					{
-						Message:  "AlertPolicy does not exist in Project default",
+						Message:  "AlertPolicy does not exist in Project default (neither in the workspace nor in Nobl9)",
 						Severity: messages.DiagnosticSeverityError,
 						Source:   ptr(config.ServerName),
 						Range: messages.Range{
@@ -191,7 +199,7 @@ func TestHandler_Handle(t *testing.T) {
 						},
 					},
 					{
-						Message:  "Project does not exist",
+						Message:  "Project does not exist (neither in the workspace nor in Nobl9)",
 						Severity: messages.DiagnosticSeverityError,
 						Source:   ptr(config.ServerName),
 						Range: messages.Range{
@@ -200,7 +208,7 @@ func TestHandler_Handle(t *testing.T) {
 						},
 					},
 					{
-						Message:  "Project does not exist",
+						Message:  "Project does not exist (neither in the workspace nor in Nobl9)",
 						Severity: messages.DiagnosticSeverityError,
 						Source:   ptr(config.ServerName),
 						Range: messages.Range{
@@ -209,7 +217,7 @@ func TestHandler_Handle(t *testing.T) {
 						},
 					},
 					{
-						Message:  "SLO does not exist in Project default",
+						Message:  "SLO does not exist in Project default (neither in the workspace nor in Nobl9)",
 						Severity: messages.DiagnosticSeverityError,
 						Source:   ptr(config.ServerName),
 						Range: messages.Range{
@@ -218,7 +226,7 @@ func TestHandler_Handle(t *testing.T) {
 						},
 					},
 					{
-						Message:  "AlertMethod does not exist in Project default",
+						Message:  "AlertMethod does not exist in Project default (neither in the workspace nor in Nobl9)",
 						Severity: messages.DiagnosticSeverityError,
 						Source:   ptr(config.ServerName),
 						Range: messages.Range{
@@ -227,7 +235,7 @@ func TestHandler_Handle(t *testing.T) {
 						},
 					},
 					{
-						Message:  "objective does not exist in SLO default and Project default",
+						Message:  "objective does not exist in SLO default and Project default (SLO found in Nobl9)",
 						Severity: messages.DiagnosticSeverityError,
 						Source:   ptr(config.ServerName),
 						Range: messages.Range{
@@ -236,7 +244,7 @@ func TestHandler_Handle(t *testing.T) {
 						},
 					},
 					{
-						Message:  "Project does not exist",
+						Message:  "Project does not exist (neither in the workspace nor in Nobl9)",
 						Severity: messages.DiagnosticSeverityError,
 						Source:   ptr(config.ServerName),
 						Range: messages.Range{
@@ -258,7 +266,7 @@ func TestHandler_Handle(t *testing.T) {
 				Version: 1,
 				Diagnostics: []messages.Diagnostic{
 					{
-						Message:  "Project does not exist",
+						Message:  "Project does not exist (neither in the workspace nor in Nobl9)",
 						Severity: messages.DiagnosticSeverityError,
 						Source:   ptr(config.ServerName),
 						Range: messages.Range{
@@ -280,7 +288,7 @@ func TestHandler_Handle(t *testing.T) {
 				Version: 1,
 				Diagnostics: []messages.Diagnostic{
 					{
-						Message:  "Project does not exist",
+						Message:  "Project does not exist (neither in the workspace nor in Nobl9)",
 						Severity: messages.DiagnosticSeverityError,
 						Source:   ptr(config.ServerName),
 						Range: messages.Range{
@@ -289,7 +297,7 @@ func TestHandler_Handle(t *testing.T) {
 						},
 					},
 					{
-						Message:  "AlertMethod does not exist in Project default",
+						Message:  "AlertMethod does not exist in Project default (neither in the workspace nor in Nobl9)",
 						Severity: messages.DiagnosticSeverityError,
 						Source:   ptr(config.ServerName),
 						Range: messages.Range{
@@ -298,7 +306,7 @@ func TestHandler_Handle(t *testing.T) {
 						},
 					},
 					{
-						Message:  "Project does not exist",
+						Message:  "Project does not exist (neither in the workspace nor in Nobl9)",
 						Severity: messages.DiagnosticSeverityError,
 						Source:   ptr(config.ServerName),
 						Range: messages.Range{
@@ -320,7 +328,7 @@ func TestHandler_Handle(t *testing.T) {
 				Version: 1,
 				Diagnostics: []messages.Diagnostic{
 					{
-						Message:  "Project does not exist",
+						Message:  "Project does not exist (neither in the workspace nor in Nobl9)",
 						Severity: messages.DiagnosticSeverityError,
 						Source:   ptr(config.ServerName),
 						Range: messages.Range{
@@ -329,7 +337,7 @@ func TestHandler_Handle(t *testing.T) {
 						},
 					},
 					{
-						Message:  "SLO does not exist in Project default",
+						Message:  "SLO does not exist in Project default (neither in the workspace nor in Nobl9)",
 						Severity: messages.DiagnosticSeverityError,
 						Source:   ptr(config.ServerName),
 						Range: messages.Range{
@@ -338,7 +346,7 @@ func TestHandler_Handle(t *testing.T) {
 						},
 					},
 					{
-						Message:  "AlertPolicy does not exist in Project default",
+						Message:  "AlertPolicy does not exist in Project default (neither in the workspace nor in Nobl9)",
 						Severity: messages.DiagnosticSeverityError,
 						Source:   ptr(config.ServerName),
 						Range: messages.Range{
@@ -360,7 +368,7 @@ func TestHandler_Handle(t *testing.T) {
 				Version: 1,
 				Diagnostics: []messages.Diagnostic{
 					{
-						Message:  "Project does not exist",
+						Message:  "Project does not exist (neither in the workspace nor in Nobl9)",
 						Severity: messages.DiagnosticSeverityError,
 						Source:   ptr(config.ServerName),
 						Range: messages.Range{
@@ -369,7 +377,7 @@ func TestHandler_Handle(t *testing.T) {
 						},
 					},
 					{
-						Message:  "SLO does not exist in Project default",
+						Message:  "SLO does not exist in Project default (neither in the workspace nor in Nobl9)",
 						Severity: messages.DiagnosticSeverityError,
 						Source:   ptr(config.ServerName),
 						Range: messages.Range{
@@ -378,7 +386,7 @@ func TestHandler_Handle(t *testing.T) {
 						},
 					},
 					{
-						Message:  "objective does not exist in SLO default and Project default",
+						Message:  "objective does not exist in SLO default and Project default (SLO found in Nobl9)",
 						Severity: messages.DiagnosticSeverityError,
 						Source:   ptr(config.ServerName),
 						Range: messages.Range{
@@ -400,7 +408,7 @@ func TestHandler_Handle(t *testing.T) {
 				Version: 1,
 				Diagnostics: []messages.Diagnostic{
 					{
-						Message:  "Project does not exist",
+						Message:  "Project does not exist (neither in the workspace nor in Nobl9)",
 						Severity: messages.DiagnosticSeverityError,
 						Source:   ptr(config.ServerName),
 						Range: messages.Range{
@@ -409,7 +417,7 @@ func TestHandler_Handle(t *testing.T) {
 						},
 					},
 					{
-						Message:  "SLO does not exist in Project default",
+						Message:  "SLO does not exist in Project default (neither in the workspace nor in Nobl9)",
 						Severity: messages.DiagnosticSeverityError,
 						Source:   ptr(config.ServerName),
 						Range: messages.Range{
@@ -431,7 +439,7 @@ func TestHandler_Handle(t *testing.T) {
 				Version: 1,
 				Diagnostics: []messages.Diagnostic{
 					{
-						Message:  "Project does not exist",
+						Message:  "Project does not exist (neither in the workspace nor in Nobl9)",
 						Severity: messages.DiagnosticSeverityError,
 						Source:   ptr(config.ServerName),
 						Range: messages.Range{
@@ -440,7 +448,7 @@ func TestHandler_Handle(t *testing.T) {
 						},
 					},
 					{
-						Message:  "Project does not exist",
+						Message:  "Project does not exist (neither in the workspace nor in Nobl9)",
 						Severity: messages.DiagnosticSeverityError,
 						Source:   ptr(config.ServerName),
 						Range: messages.Range{
@@ -449,7 +457,7 @@ func TestHandler_Handle(t *testing.T) {
 						},
 					},
 					{
-						Message:  "Service does not exist in Project default",
+						Message:  "Service does not exist in Project default (neither in the workspace nor in Nobl9)",
 						Severity: messages.DiagnosticSeverityError,
 						Source:   ptr(config.ServerName),
 						Range: messages.Range{
@@ -458,7 +466,7 @@ func TestHandler_Handle(t *testing.T) {
 						},
 					},
 					{
-						Message:  "Project does not exist",
+						Message:  "Project does not exist (neither in the workspace nor in Nobl9)",
 						Severity: messages.DiagnosticSeverityError,
 						Source:   ptr(config.ServerName),
 						Range: messages.Range{
@@ -467,7 +475,7 @@ func TestHandler_Handle(t *testing.T) {
 						},
 					},
 					{
-						Message:  "SLO does not exist in Project default",
+						Message:  "SLO does not exist in Project default (neither in the workspace nor in Nobl9)",
 						Severity: messages.DiagnosticSeverityError,
 						Source:   ptr(config.ServerName),
 						Range: messages.Range{
@@ -507,7 +515,7 @@ func TestHandler_Handle(t *testing.T) {
 						},
 					},
 					{
-						Message:  "UserGroup does not exist",
+						Message:  "UserGroup does not exist (neither in the workspace nor in Nobl9)",
 						Severity: messages.DiagnosticSeverityError,
 						Source:   ptr(config.ServerName),
 						Range: messages.Range{
@@ -738,6 +746,296 @@ func TestHandler_Handle(t *testing.T) {
 			assert.Equal(t, test.expected, params)
 		})
 	}
+
+	t.Run("related information not supported", func(t *testing.T) {
+		handler := NewHandler(fileSystem, provider, progress.NewReporter(nil), newCapabilitiesStore(false))
+		params, err := handler.Handle(context.Background(), messages.TextDocumentItem{
+			URI:     getTestFileURI("duplicate-field.yaml").URI,
+			Version: 1,
+		})
+		require.NoError(t, err)
+		diags := params.(*messages.PublishDiagnosticsParams).Diagnostics
+		require.Len(t, diags, 1)
+		assert.Nil(t, diags[0].RelatedInformation)
+	})
+	t.Run("related information not supported in pulled diagnostics", func(t *testing.T) {
+		store := capabilities.NewStore()
+		store.Set(messages.ClientCapabilities{
+			TextDocument: &messages.TextDocumentClientCapabilities{
+				Diagnostic: &messages.DiagnosticClientCapabilities{RelatedInformation: ptr(false)},
+				PublishDiagnostics: &messages.PublishDiagnosticsClientCapabilities{
+					RelatedInformation: true,
+				},
+			},
+		})
+		handler := NewHandler(fileSystem, provider, progress.NewReporter(nil), store)
+		result, err := handler.HandleDocumentDiagnostic(context.Background(), messages.DocumentDiagnosticParams{
+			TextDocument: messages.TextDocumentIdentifier{URI: getTestFileURI("duplicate-field.yaml").URI},
+		})
+		require.NoError(t, err)
+		diags := result.(messages.FullDocumentDiagnosticReport).Items
+		require.Len(t, diags, 1)
+		assert.Nil(t, diags[0].RelatedInformation)
+	})
+	t.Run("references to workspace objects", func(t *testing.T) {
+		ctx := context.Background()
+		workspaceFS := files.NewFS(nil)
+		_, err := workspaceFS.IndexFile(ctx, "file:///project.yaml",
+			"apiVersion: n9/v1alpha\nkind: Project\nmetadata:\n  name: local\n")
+		require.NoError(t, err)
+		service := "apiVersion: n9/v1alpha\nkind: Service\nmetadata:\n  name: api\n  project: %s\n"
+		require.NoError(t, workspaceFS.OpenFile(ctx, "file:///local.yaml", fmt.Sprintf(service, "local"), 1))
+		require.NoError(t, workspaceFS.OpenFile(ctx, "file:///missing.yaml", fmt.Sprintf(service, "missing"), 1))
+		provider := NewProvider(
+			docs,
+			objectsProviderMock{},
+			workspace.NewObjectsResolver(workspaceFS, objectsProviderMock{}, fileScopesMock{}),
+			fileScopesMock{},
+		)
+		handler := NewHandler(workspaceFS, provider, progress.NewReporter(nil), newCapabilitiesStore(true))
+
+		params, err := handler.Handle(ctx, messages.TextDocumentItem{URI: "file:///local.yaml", Version: 1})
+		require.NoError(t, err)
+		assert.Empty(t, params.(*messages.PublishDiagnosticsParams).Diagnostics)
+
+		params, err = handler.Handle(ctx, messages.TextDocumentItem{URI: "file:///missing.yaml", Version: 1})
+		require.NoError(t, err)
+		diags := params.(*messages.PublishDiagnosticsParams).Diagnostics
+		require.Len(t, diags, 1)
+		assert.Equal(t, "Project does not exist (neither in the workspace nor in Nobl9)", diags[0].Message)
+	})
+	t.Run("duplicate objects", func(t *testing.T) {
+		ctx := context.Background()
+		workspaceFS := files.NewFS(nil)
+		service := "apiVersion: n9/v1alpha\nkind: Service\nmetadata:\n  name: api\n  project: %s\n"
+		project := "apiVersion: n9/v1alpha\nkind: Project\nmetadata:\n  name: %s\n"
+		require.NoError(t, workspaceFS.OpenFile(ctx, "file:///service.yaml", fmt.Sprintf(service, "default"), 1))
+		_, err := workspaceFS.IndexFile(ctx, "file:///copy.yaml",
+			"apiVersion: n9/v1alpha\nkind: Service\nmetadata:\n  name: api\n"+
+				"---\n"+fmt.Sprintf(project, "default"))
+		require.NoError(t, err)
+		_, err = workspaceFS.IndexFile(ctx, "file:///other-project.yaml", fmt.Sprintf(service, "other"))
+		require.NoError(t, err)
+		_, err = workspaceFS.IndexFile(ctx, "file:///other-context/service.yaml",
+			fmt.Sprintf(service, "default"))
+		require.NoError(t, err)
+		require.NoError(t, workspaceFS.OpenFile(ctx, "file:///project.yaml", fmt.Sprintf(project, "default"), 1))
+		scopes := fileScopesMock{"file:///other-context/": "other"}
+		provider := NewProvider(
+			docs,
+			objectsProviderMock{},
+			workspace.NewObjectsResolver(workspaceFS, objectsProviderMock{}, scopes),
+			scopes,
+		)
+		handler := NewHandler(workspaceFS, provider, progress.NewReporter(nil), newCapabilitiesStore(true))
+
+		params, err := handler.Handle(ctx, messages.TextDocumentItem{URI: "file:///service.yaml", Version: 1})
+		require.NoError(t, err)
+		assert.Equal(t, []messages.Diagnostic{{
+			Range:    messages.NewLineRange(4, 8, 11),
+			Severity: messages.DiagnosticSeverityError,
+			Source:   ptr(config.ServerName),
+			Message: "Service api is defined more than once in Project default, " +
+				"the definitions will overwrite each other when applied",
+			RelatedInformation: []messages.DiagnosticRelatedInformation{{
+				Location: messages.Location{URI: "file:///copy.yaml", Range: messages.NewLineRange(4, 8, 11)},
+				Message:  "duplicate definition",
+			}},
+		}}, params.(*messages.PublishDiagnosticsParams).Diagnostics)
+
+		params, err = handler.Handle(ctx, messages.TextDocumentItem{URI: "file:///project.yaml", Version: 1})
+		require.NoError(t, err)
+		diags := params.(*messages.PublishDiagnosticsParams).Diagnostics
+		require.Len(t, diags, 1)
+		assert.Equal(t, "Project default is defined more than once, "+
+			"the definitions will overwrite each other when applied", diags[0].Message)
+	})
+	t.Run("unused objects", func(t *testing.T) {
+		ctx := context.Background()
+		workspaceFS := files.NewFS(nil)
+		require.NoError(t, workspaceFS.OpenFile(ctx, "file:///objects.yaml", `apiVersion: n9/v1alpha
+kind: Service
+metadata:
+  name: api
+  project: default
+---
+apiVersion: n9/v1alpha
+kind: Service
+metadata:
+  name: web
+  project: default
+---
+apiVersion: n9/v1alpha
+kind: AlertMethod
+metadata:
+  name: webhook
+  project: default
+spec:
+  webhook:
+    url: https://example.com
+    template: "SLO $slo_name needs attention"
+`, 1))
+		_, err := workspaceFS.IndexFile(ctx, "file:///slo.yaml",
+			"apiVersion: n9/v1alpha\nkind: SLO\nmetadata:\n  name: slo\nspec:\n  service: api\n")
+		require.NoError(t, err)
+		objects := unusedObjectsProviderMock{objects: []manifest.Object{
+			v1alphaAlertPolicy.New(
+				v1alphaAlertPolicy.Metadata{Name: "policy", Project: "default"},
+				v1alphaAlertPolicy.Spec{AlertMethods: []v1alphaAlertPolicy.AlertMethodRef{
+					{Metadata: v1alphaAlertPolicy.AlertMethodRefMetadata{Name: "webhook"}},
+				}},
+			),
+		}}
+		provider := NewProvider(
+			docs,
+			objects,
+			workspace.NewObjectsResolver(workspaceFS, objects, fileScopesMock{}),
+			fileScopesMock{},
+		)
+		provider.SetSettings(config.DiagnosticsSettings{References: ptr(false), Unused: ptr(true)})
+		store := capabilities.NewStore()
+		store.Set(messages.ClientCapabilities{
+			TextDocument: &messages.TextDocumentClientCapabilities{
+				PublishDiagnostics: &messages.PublishDiagnosticsClientCapabilities{
+					TagSupport: &messages.DiagnosticTagSupport{
+						ValueSet: []messages.DiagnosticTag{messages.DiagnosticTagUnnecessary},
+					},
+				},
+			},
+		})
+		handler := NewHandler(workspaceFS, provider, progress.NewReporter(nil), store)
+
+		params, err := handler.Handle(ctx, messages.TextDocumentItem{URI: "file:///objects.yaml", Version: 1})
+		require.NoError(t, err)
+		assert.Equal(t, []messages.Diagnostic{
+			{
+				Range:    messages.NewLineRange(10, 8, 11),
+				Severity: messages.DiagnosticSeverityInformation,
+				Source:   ptr(config.ServerName),
+				Message:  "Service web is not referenced by any Report or SLO in the workspace",
+				Tags:     []messages.DiagnosticTag{messages.DiagnosticTagUnnecessary},
+			},
+			{
+				Range:    messages.NewLineRange(16, 8, 15),
+				Severity: messages.DiagnosticSeverityInformation,
+				Source:   ptr(config.ServerName),
+				Message:  "AlertMethod webhook is not referenced by any AlertPolicy or SLO in the workspace",
+				Tags:     []messages.DiagnosticTag{messages.DiagnosticTagUnnecessary},
+			},
+		}, params.(*messages.PublishDiagnosticsParams).Diagnostics)
+
+		provider.SetSettings(config.DiagnosticsSettings{
+			References:     ptr(false),
+			Unused:         ptr(true),
+			UnusedPlatform: ptr(true),
+		})
+		// Objects fetched from the Nobl9 API are only considered when the whole workspace is diagnosed.
+		params, err = handler.Handle(ctx, messages.TextDocumentItem{URI: "file:///objects.yaml", Version: 1})
+		require.NoError(t, err)
+		assert.Len(t, params.(*messages.PublishDiagnosticsParams).Diagnostics, 2)
+
+		result, err := handler.HandleWorkspaceDiagnostic(ctx, messages.WorkspaceDiagnosticParams{})
+		require.NoError(t, err)
+		items := result.(messages.WorkspaceDiagnosticReport).Items
+		require.Len(t, items, 2)
+		report := items[0].(messages.WorkspaceFullDocumentDiagnosticReport)
+		require.Equal(t, "file:///objects.yaml", report.URI)
+		require.Len(t, report.Items, 1)
+		assert.Equal(t, "Service web is not referenced by any Report or SLO in the workspace nor in Nobl9",
+			report.Items[0].Message)
+
+		handler = NewHandler(workspaceFS, provider, progress.NewReporter(nil), newCapabilitiesStore(true))
+		params, err = handler.Handle(ctx, messages.TextDocumentItem{URI: "file:///objects.yaml", Version: 1})
+		require.NoError(t, err)
+		diags := params.(*messages.PublishDiagnosticsParams).Diagnostics
+		require.Len(t, diags, 2)
+		assert.Nil(t, diags[0].Tags)
+
+		// Clients which don't advertise the pulled diagnostics tags fall back to the published ones.
+		publishDiagnostics := store.Get().TextDocument.PublishDiagnostics
+		store.Set(messages.ClientCapabilities{
+			TextDocument: &messages.TextDocumentClientCapabilities{
+				Diagnostic:         &messages.DiagnosticClientCapabilities{},
+				PublishDiagnostics: publishDiagnostics,
+			},
+		})
+		handler = NewHandler(workspaceFS, provider, progress.NewReporter(nil), store)
+		result, err = handler.HandleDocumentDiagnostic(ctx, messages.DocumentDiagnosticParams{
+			TextDocument: messages.TextDocumentIdentifier{URI: "file:///objects.yaml"},
+		})
+		require.NoError(t, err)
+		diags = result.(messages.FullDocumentDiagnosticReport).Items
+		require.Len(t, diags, 2)
+		assert.Equal(t, []messages.DiagnosticTag{messages.DiagnosticTagUnnecessary}, diags[0].Tags)
+
+		// Tags supported only in the published diagnostics are not sent in the pulled ones.
+		store.Set(messages.ClientCapabilities{
+			TextDocument: &messages.TextDocumentClientCapabilities{
+				Diagnostic:         &messages.DiagnosticClientCapabilities{TagSupport: &messages.DiagnosticTagSupport{}},
+				PublishDiagnostics: publishDiagnostics,
+			},
+		})
+		result, err = handler.HandleDocumentDiagnostic(ctx, messages.DocumentDiagnosticParams{
+			TextDocument: messages.TextDocumentIdentifier{URI: "file:///objects.yaml"},
+		})
+		require.NoError(t, err)
+		diags = result.(messages.FullDocumentDiagnosticReport).Items
+		require.Len(t, diags, 2)
+		assert.Nil(t, diags[0].Tags)
+	})
+	t.Run("settings", func(t *testing.T) {
+		provider := NewProvider(
+			docs,
+			objectsProviderMock{},
+			workspace.NewObjectsResolver(files.NewFS(nil), objectsProviderMock{}, fileScopesMock{}),
+			fileScopesMock{},
+		)
+		handler := NewHandler(fileSystem, provider, progress.NewReporter(nil), newCapabilitiesStore(true))
+		item := messages.TextDocumentItem{
+			URI:     getTestFileURI("deprecated-composite.yaml").URI,
+			Version: 1,
+		}
+
+		provider.SetSettings(config.DiagnosticsSettings{Deprecated: ptr(false)})
+		params, err := handler.Handle(context.Background(), item)
+		require.NoError(t, err)
+		assert.Empty(t, params.(*messages.PublishDiagnosticsParams).Diagnostics)
+
+		provider.SetSettings(config.DiagnosticsSettings{})
+		params, err = handler.Handle(context.Background(), item)
+		require.NoError(t, err)
+		assert.Len(t, params.(*messages.PublishDiagnosticsParams).Diagnostics, 2)
+
+		provider.SetSettings(config.DiagnosticsSettings{Enabled: ptr(false)})
+		params, err = handler.Handle(context.Background(), messages.TextDocumentItem{
+			URI:     getTestFileURI("invalid-composite.yaml").URI,
+			Version: 1,
+		})
+		require.NoError(t, err)
+		assert.Empty(t, params.(*messages.PublishDiagnosticsParams).Diagnostics)
+	})
+	t.Run("cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		params, err := handler.Handle(ctx, messages.TextDocumentItem{
+			URI:     getTestFileURI("data-exports.yaml").URI,
+			Version: 1,
+		})
+		require.NoError(t, err)
+		assert.Nil(t, params)
+	})
+}
+
+func newCapabilitiesStore(relatedInformation bool) *capabilities.Store {
+	store := capabilities.NewStore()
+	store.Set(messages.ClientCapabilities{
+		TextDocument: &messages.TextDocumentClientCapabilities{
+			PublishDiagnostics: &messages.PublishDiagnosticsClientCapabilities{
+				RelatedInformation: relatedInformation,
+			},
+		},
+	})
+	return store
 }
 
 type objectsProviderMock struct{}
@@ -763,10 +1061,20 @@ func (o objectsProviderMock) GetObject(
 	return nil, nil
 }
 
-func (o objectsProviderMock) GetDefaultProject() string {
+func (o objectsProviderMock) GetAllNames(context.Context, manifest.Kind, string) ([]string, error) {
+	return nil, nil
+}
+
+func (o objectsProviderMock) CacheEpoch() uint64 { return 0 }
+
+func (o objectsProviderMock) GetDefaultProject(context.Context) string {
 	return "default"
 }
 
+func (o objectsProviderMock) GetAllObjects(context.Context, manifest.Kind) ([]manifest.Object, error) {
+	return nil, nil
+}
+
 func (o objectsProviderMock) GetUser(_ context.Context, id string) (*nobl9repo.User, error) {
 	if id == "default" {
 		return &nobl9repo.User{}, nil
@@ -780,3 +1088,222 @@ func (o objectsProviderMock) GetRoles(_ context.Context) (*nobl9repo.Roles, erro
 		ProjectRoles:      []nobl9repo.Role{{Name: "default"}},
 	}, nil
 }
+
+// fileScopesMock binds the configuration context to the files with the given URI prefixes.
+type fileScopesMock map[string]string
+
+func (f fileScopesMock) WithFileScope(ctx context.Context, uri files.URI) context.Context {
+	for prefix, configContext := range f {
+		if strings.HasPrefix(uri, prefix) {
+			return nobl9repo.WithScope(ctx, nobl9repo.Scope{ConfigContext: configContext})
+		}
+	}
+	return nobl9repo.WithScope(ctx, nobl9repo.Scope{})
+}
+
+func (f fileScopesMock) Generation() uint64 { return 0 }
+
+// unusedObjectsProviderMock returns the objects from the Nobl9 API.
+type unusedObjectsProviderMock struct {
+	objectsProviderMock
+	objects []manifest.Object
+}
+
+func (u unusedObjectsProviderMock) GetAllObjects(_ context.Context, kind manifest.Kind) ([]manifest.Object, error) {
+	var objects []manifest.Object
+	for _, object := range u.objects {
+		if object.GetKind() == kind {
+			objects = append(objects, object)
+		}
+	}
+	return objects, nil
+}
+
+func TestHandler_HandleDocumentDiagnostic(t *testing.T) {
+	t.Parallel()
+
+	fileSystem := files.NewFS(nil)
+	content := "apiVersion: n9/v1alpha\nkind: Project\nmetadata:\n  name: foo\n"
+	require.NoError(t, fileSystem.OpenFile(context.Background(), "file:///foo.yaml", content, 1))
+	handler := NewHandler(fileSystem, providerMock{}, progress.NewReporter(nil), capabilities.NewStore())
+
+	result, err := handler.HandleDocumentDiagnostic(context.Background(), messages.DocumentDiagnosticParams{
+		TextDocument: messages.TextDocumentIdentifier{URI: "file:///foo.yaml"},
+	})
+	require.NoError(t, err)
+	require.IsType(t, messages.FullDocumentDiagnosticReport{}, result)
+	report := result.(messages.FullDocumentDiagnosticReport)
+	assert.Equal(t, messages.FullDocumentDiagnosticReport{
+		Kind:     messages.DocumentDiagnosticReportKindFull,
+		ResultID: report.ResultID,
+		Items:    []messages.Diagnostic{{Message: "file:///foo.yaml"}},
+	}, report)
+	assert.NotEmpty(t, report.ResultID)
+
+	t.Run("unchanged", func(t *testing.T) {
+		result, err := handler.HandleDocumentDiagnostic(context.Background(), messages.DocumentDiagnosticParams{
+			TextDocument:     messages.TextDocumentIdentifier{URI: "file:///foo.yaml"},
+			PreviousResultID: report.ResultID,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, messages.UnchangedDocumentDiagnosticReport{
+			Kind:     messages.DocumentDiagnosticReportKindUnchanged,
+			ResultID: report.ResultID,
+		}, result)
+	})
+	t.Run("outdated result id", func(t *testing.T) {
+		result, err := handler.HandleDocumentDiagnostic(context.Background(), messages.DocumentDiagnosticParams{
+			TextDocument:     messages.TextDocumentIdentifier{URI: "file:///foo.yaml"},
+			PreviousResultID: "outdated",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, report, result)
+	})
+	t.Run("cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := handler.HandleDocumentDiagnostic(ctx, messages.DocumentDiagnosticParams{
+			TextDocument: messages.TextDocumentIdentifier{URI: "file:///foo.yaml"},
+		})
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+	t.Run("workspace changed", func(t *testing.T) {
+		_, err := fileSystem.IndexFile(context.Background(), "file:///bar.yaml", content)
+		require.NoError(t, err)
+		result, err := handler.HandleDocumentDiagnostic(context.Background(), messages.DocumentDiagnosticParams{
+			TextDocument:     messages.TextDocumentIdentifier{URI: "file:///foo.yaml"},
+			PreviousResultID: report.ResultID,
+		})
+		require.NoError(t, err)
+		require.IsType(t, messages.FullDocumentDiagnosticReport{}, result)
+		assert.NotEqual(t, report.ResultID, result.(messages.FullDocumentDiagnosticReport).ResultID)
+		report = result.(messages.FullDocumentDiagnosticReport)
+	})
+	t.Run("cache expired", func(t *testing.T) {
+		handler := NewHandler(fileSystem, providerMock{cacheEpoch: 1}, progress.NewReporter(nil), capabilities.NewStore())
+		result, err := handler.HandleDocumentDiagnostic(context.Background(), messages.DocumentDiagnosticParams{
+			TextDocument:     messages.TextDocumentIdentifier{URI: "file:///foo.yaml"},
+			PreviousResultID: report.ResultID,
+		})
+		require.NoError(t, err)
+		require.IsType(t, messages.FullDocumentDiagnosticReport{}, result)
+		assert.NotEqual(t, report.ResultID, result.(messages.FullDocumentDiagnosticReport).ResultID)
+	})
+	t.Run("settings changed", func(t *testing.T) {
+		handler.Invalidate()
+		result, err := handler.HandleDocumentDiagnostic(context.Background(), messages.DocumentDiagnosticParams{
+			TextDocument:     messages.TextDocumentIdentifier{URI: "file:///foo.yaml"},
+			PreviousResultID: report.ResultID,
+		})
+		require.NoError(t, err)
+		require.IsType(t, messages.FullDocumentDiagnosticReport{}, result)
+		assert.NotEqual(t, report.ResultID, result.(messages.FullDocumentDiagnosticReport).ResultID)
+	})
+}
+
+func TestHandler_HandleWorkspaceDiagnostic(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	fileSystem := files.NewFS(nil)
+	content := "apiVersion: n9/v1alpha\nkind: Project\nmetadata:\n  name: foo\n"
+	require.NoError(t, fileSystem.OpenFile(ctx, "file:///opened.yaml", content, 3))
+	_, err := fileSystem.IndexFile(ctx, "file:///indexed.yaml", content)
+	require.NoError(t, err)
+	_, err = fileSystem.IndexFile(ctx, "file:///unchanged.yaml", content+"\n")
+	require.NoError(t, err)
+	handler := NewHandler(fileSystem, providerMock{}, progress.NewReporter(nil), capabilities.NewStore())
+
+	workspaceCtx := withWorkspaceDiagnostic(ctx)
+	unchangedFile := &files.File{Content: content + "\n"}
+	result, err := handler.HandleWorkspaceDiagnostic(ctx, messages.WorkspaceDiagnosticParams{
+		PreviousResultIDs: []messages.PreviousResultID{
+			{URI: "file:///opened.yaml", Value: "outdated"},
+			{URI: "file:///unchanged.yaml", Value: handler.getResultID(workspaceCtx, unchangedFile)},
+		},
+	})
+	require.NoError(t, err)
+
+	resultID := handler.getResultID(workspaceCtx, &files.File{Content: content})
+	assert.Equal(t, messages.WorkspaceDiagnosticReport{
+		Items: []any{
+			messages.WorkspaceFullDocumentDiagnosticReport{
+				FullDocumentDiagnosticReport: messages.FullDocumentDiagnosticReport{
+					Kind:     messages.DocumentDiagnosticReportKindFull,
+					ResultID: resultID,
+					Items:    []messages.Diagnostic{{Message: "file:///indexed.yaml"}},
+				},
+				URI: "file:///indexed.yaml",
+			},
+			messages.WorkspaceFullDocumentDiagnosticReport{
+				FullDocumentDiagnosticReport: messages.FullDocumentDiagnosticReport{
+					Kind:     messages.DocumentDiagnosticReportKindFull,
+					ResultID: resultID,
+					Items:    []messages.Diagnostic{{Message: "file:///opened.yaml"}},
+				},
+				URI:     "file:///opened.yaml",
+				Version: ptr(3),
+			},
+			messages.WorkspaceUnchangedDocumentDiagnosticReport{
+				UnchangedDocumentDiagnosticReport: messages.UnchangedDocumentDiagnosticReport{
+					Kind:     messages.DocumentDiagnosticReportKindUnchanged,
+					ResultID: handler.getResultID(workspaceCtx, unchangedFile),
+				},
+				URI: "file:///unchanged.yaml",
+			},
+		},
+	}, result)
+}
+
+func TestHandler_Handle_RelatedInformationPositionEncoding(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	fileSystem := files.NewFS(nil)
+	fileSystem.SetPositionEncoding(messages.PositionEncodingUTF16)
+	content := "# 🙂 api\napiVersion: n9/v1alpha\n"
+	require.NoError(t, fileSystem.OpenFile(ctx, "file:///opened.yaml", content, 1))
+	_, err := fileSystem.IndexFile(ctx, "file:///indexed.yaml", content)
+	require.NoError(t, err)
+	handler := NewHandler(
+		fileSystem,
+		relatedInformationProviderMock{},
+		progress.NewReporter(nil),
+		newCapabilitiesStore(true),
+	)
+
+	params, err := handler.Handle(ctx, messages.TextDocumentItem{URI: "file:///opened.yaml", Version: 1})
+	require.NoError(t, err)
+	diags := params.(*messages.PublishDiagnosticsParams).Diagnostics
+	require.Len(t, diags, 1)
+	assert.Equal(t, []messages.DiagnosticRelatedInformation{
+		{Location: messages.Location{URI: "file:///opened.yaml", Range: messages.NewLineRange(0, 5, 8)}},
+		{Location: messages.Location{URI: "file:///indexed.yaml", Range: messages.NewLineRange(0, 5, 8)}},
+	}, diags[0].RelatedInformation)
+}
+
+// relatedInformationProviderMock reports a single diagnostic related to the same object in two files.
+type relatedInformationProviderMock struct{}
+
+func (relatedInformationProviderMock) DiagnoseFile(context.Context, *files.File) []messages.Diagnostic {
+	return []messages.Diagnostic{{
+		Range: messages.NewLineRange(0, 4, 7),
+		RelatedInformation: []messages.DiagnosticRelatedInformation{
+			{Location: messages.Location{URI: "file:///opened.yaml", Range: messages.NewLineRange(0, 4, 7)}},
+			{Location: messages.Location{URI: "file:///indexed.yaml", Range: messages.NewLineRange(0, 4, 7)}},
+		},
+	}}
+}
+
+func (relatedInformationProviderMock) CacheEpoch() uint64 { return 0 }
+
+// providerMock reports a single diagnostic with the file's URI as its message.
+type providerMock struct {
+	cacheEpoch uint64
+}
+
+func (providerMock) DiagnoseFile(_ context.Context, file *files.File) []messages.Diagnostic {
+	return []messages.Diagnostic{{Message: file.URI}}
+}
+
+func (p providerMock) CacheEpoch() uint64 { return p.cacheEpoch }
@@ -9,6 +9,7 @@ import (
 	"strconv"
 	"sync"
 	"sync/atomic"
+	"unicode/utf8"
 
 	"github.com/goccy/go-yaml"
 	"github.com/goccy/go-yaml/ast"
@@ -29,6 +30,7 @@ import (
 	"github.com/nobl9/nobl9-language-server/internal/messages"
 	"github.com/nobl9/nobl9-language-server/internal/nobl9repo"
 	"github.com/nobl9/nobl9-language-server/internal/recovery"
+	"github.com/nobl9/nobl9-language-server/internal/workspace"
 	"github.com/nobl9/nobl9-language-server/internal/yamlast"
 	"github.com/nobl9/nobl9-language-server/internal/yamlastsimple"
 	"github.com/nobl9/nobl9-language-server/internal/yamlpath"
@@ -41,30 +43,78 @@ type deprecatedPathsProvider interface {
 }
 
 type objectsProvider interface {
-	GetObject(ctx context.Context, kind manifest.Kind, name, project string) (manifest.Object, error)
-	GetDefaultProject() string
+	GetDefaultProject(ctx context.Context) string
 	GetUser(ctx context.Context, id string) (*nobl9repo.User, error)
 	GetRoles(ctx context.Context) (*nobl9repo.Roles, error)
+	GetAllObjects(ctx context.Context, kind manifest.Kind) ([]manifest.Object, error)
+	CacheEpoch() uint64
 }
 
-func NewProvider(deprecated deprecatedPathsProvider, objects objectsProvider) *Provider {
+type objectsResolver interface {
+	ResolveObject(ctx context.Context, kind manifest.Kind, name, project string) (*workspace.ResolvedObject, error)
+	FindLocalObjects(ctx context.Context, kind manifest.Kind, name string) []*workspace.ResolvedObject
+	FindLocalReferences(ctx context.Context, kind manifest.Kind) []*workspace.Reference
+}
+
+// fileScopes bind the [nobl9repo.Scope] of the file's workspace folder to the context.
+type fileScopes interface {
+	WithFileScope(ctx context.Context, uri files.URI) context.Context
+}
+
+func NewProvider(
+	deprecated deprecatedPathsProvider,
+	objects objectsProvider,
+	resolver objectsResolver,
+	scopes fileScopes,
+) *Provider {
 	return &Provider{
 		deprecated: deprecated,
 		objects:    objects,
+		resolver:   resolver,
+		scopes:     scopes,
+		settings:   new(atomic.Pointer[config.DiagnosticsSettings]),
 	}
 }
 
 type Provider struct {
 	deprecated deprecatedPathsProvider
 	objects    objectsProvider
+	resolver   objectsResolver
+	scopes     fileScopes
+	settings   *atomic.Pointer[config.DiagnosticsSettings]
 }
 
+// SetSettings changes which diagnostics are reported.
+func (d Provider) SetSettings(settings config.DiagnosticsSettings) {
+	d.settings.Store(&settings)
+}
+
+// CacheEpoch identifies the period in which the cached Nobl9 API responses used by the diagnostics are valid.
+func (d Provider) CacheEpoch() uint64 {
+	return d.objects.CacheEpoch()
+}
+
+func (d Provider) getSettings() config.DiagnosticsSettings {
+	if settings := d.settings.Load(); settings != nil {
+		return *settings
+	}
+	return config.DiagnosticsSettings{}
+}
+
+// DiagnoseFile diagnoses the file within the [nobl9repo.Scope] of its workspace folder.
 func (d Provider) DiagnoseFile(ctx context.Context, file *files.File) []messages.Diagnostic {
+	settings := d.getSettings()
+	if !settings.IsEnabled() {
+		return nil
+	}
+	ctx = d.scopes.WithFileScope(ctx, file.URI)
 	if file.Err != nil {
 		return astErrorToDiagnostics(file.Err, 0, file.URI)
 	}
 
 	numDiags := atomic.Int64{}
+	// Each object's validity is recorded at its index, so that the unused objects check can reuse it.
+	valid := make([]bool, len(file.Objects))
 	ch := make(chan []messages.Diagnostic, len(file.Objects))
 	wg := sync.WaitGroup{}
 	wg.Add(len(file.Objects))
@@ -75,7 +125,8 @@ func (d Provider) DiagnoseFile(ctx context.Context, file *files.File) []messages
 				ch <- astErrorToDiagnostics(object.Err, object.Node.StartLine, file.URI)
 				return
 			}
-			diags := d.diagnoseObject(ctx, object, file.SimpleAST[i])
+			diags, isValid := d.diagnoseObject(ctx, settings, file.URI, object, file.SimpleAST[i])
+			valid[i] = isValid
 			if len(diags) > 0 {
 				numDiags.Add(int64(len(diags)))
 			}
@@ -88,22 +139,33 @@ func (d Provider) DiagnoseFile(ctx context.Context, file *files.File) []messages
 	for range file.Objects {
 		diagnostics = append(diagnostics, <-ch...)
 	}
+	if settings.IsUnusedEnabled() {
+		diagnostics = append(diagnostics, d.checkUnused(ctx, settings, file, valid)...)
+	}
 	return diagnostics
 }
 
+// diagnoseObject returns the diagnostics of the object and whether the object passed the validation.
 func (d Provider) diagnoseObject(
 	ctx context.Context,
+	settings config.DiagnosticsSettings,
+	uri files.URI,
 	object *files.ObjectNode,
 	simpleObject *files.SimpleObjectNode,
-) []messages.Diagnostic {
+) (diagnostics []messages.Diagnostic, valid bool) {
 	objectValidityDiags := d.validateObject(ctx, object)
-	diagnostics := append(d.checkDeprecated(simpleObject), objectValidityDiags...)
+	valid = len(objectValidityDiags) == 0
+	if settings.IsDeprecatedEnabled() {
+		diagnostics = d.checkDeprecated(simpleObject)
+	}
+	diagnostics = append(diagnostics, objectValidityDiags...)
+	diagnostics = append(diagnostics, d.checkDuplicates(ctx, uri, object)...)
 	// Only check referenced objects if the object is valid.
-	if len(objectValidityDiags) > 0 {
-		return diagnostics
+	if !valid || !settings.IsReferencesEnabled() {
+		return diagnostics, valid
 	}
 	diagnostics = append(diagnostics, d.checkReferencedObjects(ctx, object)...)
-	return diagnostics
+	return diagnostics, valid
 }
 
 func (d Provider) validateObject(ctx context.Context, object *files.ObjectNode) []messages.Diagnostic {
@@ -608,7 +670,7 @@ func (d Provider) checkObjectExistence(
 	if objectName == "" {
 		return nil
 	}
-	object, err := d.objects.GetObject(ctx, kind, objectName, projectName)
+	object, err := d.resolver.ResolveObject(ctx, kind, objectName, projectName)
 	if err != nil {
 		slog.ErrorContext(
 			ctx,
@@ -630,6 +692,7 @@ func (d Provider) checkObjectExistence(
 	} else {
 		message = fmt.Sprintf("%s does not exist", kind)
 	}
+	message += " (neither in the workspace nor in Nobl9)"
 	return []messages.Diagnostic{{
 		Range:    getRangeForNodePath(ctx, node, propertyPath),
 		Severity: messages.DiagnosticSeverityError,
@@ -647,7 +710,7 @@ func (d Provider) checkObjectiveExistence(
 	if objectiveName == "" || sloName == "" {
 		return nil
 	}
-	object, err := d.objects.GetObject(ctx, manifest.KindSLO, sloName, projectName)
+	object, err := d.resolver.ResolveObject(ctx, manifest.KindSLO, sloName, projectName)
 	if err != nil {
 		slog.ErrorContext(
 			ctx,
@@ -662,7 +725,7 @@ func (d Provider) checkObjectiveExistence(
 	if object == nil {
 		return nil
 	}
-	slo, ok := object.(v1alphaSLO.SLO)
+	slo, ok := object.Object.(v1alphaSLO.SLO)
 	if !ok {
 		slog.ErrorContext(ctx, "failed to cast object to SLO")
 		return nil
@@ -672,14 +735,25 @@ func (d Provider) checkObjectiveExistence(
 			return nil
 		}
 	}
-	return []messages.Diagnostic{{
+	diagnostic := messages.Diagnostic{
 		Range:    getRangeForNodePath(ctx, node, propertyPath),
 		Severity: messages.DiagnosticSeverityError,
 		Source:   ptr(config.ServerName),
-		Message: fmt.Sprintf(
-			"objective does not exist in SLO %s and Project %s",
-			sloName, projectName),
-	}}
+	}
+	if object.IsLocal() {
+		diagnostic.Message = fmt.Sprintf(
+			"objective does not exist in SLO %s and Project %s (SLO defined in the workspace)",
+			sloName, projectName)
+		diagnostic.RelatedInformation = []messages.DiagnosticRelatedInformation{{
+			Location: *object.Location,
+			Message:  "SLO definition",
+		}}
+	} else {
+		diagnostic.Message = fmt.Sprintf(
+			"objective does not exist in SLO %s and Project %s (SLO found in Nobl9)",
+			sloName, projectName)
+	}
+	return []messages.Diagnostic{diagnostic}
 }
 
 func (d Provider) checkUserExistence(
@@ -861,7 +935,7 @@ var duplicateYAMLKeyRegexp = regexp.MustCompile(`already defined at \[(\d+):(\d+
 func yamlErrorToDiagnostic(yamlError yaml.Error, fileURI string) messages.Diagnostic {
 	token := yamlError.GetToken()
 	start := token.Position.Column - 1
-	end := start + len(token.Value)
+	end := start + utf8.RuneCountInString(token.Value)
 	diag := messages.Diagnostic{
 		Range: messages.NewLineRange(
 			// Shift the line number to the actual line in the file as the SDK
@@ -946,11 +1020,19 @@ func getRangeFromNode(node ast.Node) messages.Range {
 			//
 			//   name: foo
 			token := v.Value.GetToken()
-			return messages.NewLineRange(token.Position.Line, token.Position.Column-1, len(node.String()))
+			return messages.NewLineRange(
+				token.Position.Line,
+				token.Position.Column-1,
+				utf8.RuneCountInString(node.String()),
+			)
 		}
 	case *ast.StringNode:
 		token := node.GetToken()
-		return messages.NewLineRange(token.Position.Line, token.Position.Column-1, token.Position.Column+len(v.Value)-1)
+		return messages.NewLineRange(
+			token.Position.Line,
+			token.Position.Column-1,
+			token.Position.Column+utf8.RuneCountInString(v.Value)-1,
+		)
 	default:
 		token := node.GetToken()
 		return messages.NewLineRange(token.Position.Line, token.Position.IndentNum, token.Position.Column)
@@ -0,0 +1,145 @@
+package diagnostics
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/nobl9/nobl9-language-server/internal/files"
+	"github.com/nobl9/nobl9-language-server/internal/messages"
+)
+
+// HandleDocumentDiagnostic handles pull diagnostics requests for a single document.
+// If neither the document nor the workspace has changed since the previous result was computed,
+// an unchanged report is returned, sparing the client re-evaluating API-backed checks.
+func (h *Handler) HandleDocumentDiagnostic(
+	ctx context.Context,
+	params messages.DocumentDiagnosticParams,
+) (any, error) {
+	file, err := h.fs.GetFile(params.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+	ctx = file.AddToLogContext(ctx)
+	report := h.getReport(ctx, file, params.PreviousResultID)
+	if err = ctx.Err(); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// HandleWorkspaceDiagnostic handles pull diagnostics requests for all the files in the workspace,
+// both opened by the client and indexed.
+// Since the files' objects references are validated against the Nobl9 API,
+// the progress is reported for each file, granted the client provided the progress token.
+// Clients tend to pull the workspace diagnostics repeatedly, the server does not initiate the progress on its own.
+// Unlike the other diagnostics, the unused objects hints consider the objects fetched from the Nobl9 API
+// (if enabled) only here, as fetching all of them can take a while.
+func (h *Handler) HandleWorkspaceDiagnostic(
+	ctx context.Context,
+	params messages.WorkspaceDiagnosticParams,
+) (any, error) {
+	ctx = withWorkspaceDiagnostic(ctx)
+	previousResultIDs := make(map[files.URI]string, len(params.PreviousResultIDs))
+	for _, id := range params.PreviousResultIDs {
+		previousResultIDs[id.URI] = id.Value
+	}
+	workspaceFiles := h.fs.GetFiles()
+	slices.SortFunc(workspaceFiles, func(f1, f2 *files.File) int { return strings.Compare(f1.URI, f2.URI) })
+
+	ctx, p := h.progress.BeginRequested(ctx, params.WorkDoneToken, "Validating Nobl9 files", len(workspaceFiles))
+	defer p.End(ctx, "")
+
+	items := make([]any, 0, len(workspaceFiles))
+	for i, file := range workspaceFiles {
+		if file.Skip {
+			continue
+		}
+		if err := context.Cause(ctx); err != nil {
+			return nil, err
+		}
+		p.Report(ctx, i, fmt.Sprintf("Validating %d/%d files", i+1, len(workspaceFiles)))
+		var version *int
+		if h.fs.HasFile(file.URI) {
+			version = &file.Version
+		}
+		switch report := h.getReport(file.AddToLogContext(ctx), file, previousResultIDs[file.URI]).(type) {
+		case messages.UnchangedDocumentDiagnosticReport:
+			items = append(items, messages.WorkspaceUnchangedDocumentDiagnosticReport{
+				UnchangedDocumentDiagnosticReport: report,
+				URI:                               file.URI,
+				Version:                           version,
+			})
+		case messages.FullDocumentDiagnosticReport:
+			items = append(items, messages.WorkspaceFullDocumentDiagnosticReport{
+				FullDocumentDiagnosticReport: report,
+				URI:                          file.URI,
+				Version:                      version,
+			})
+		}
+	}
+	if err := context.Cause(ctx); err != nil {
+		return nil, err
+	}
+	return messages.WorkspaceDiagnosticReport{Items: items}, nil
+}
+
+// getReport returns either [messages.FullDocumentDiagnosticReport] or,
+// if the previous result ID matches the current one, [messages.UnchangedDocumentDiagnosticReport].
+func (h *Handler) getReport(ctx context.Context, file *files.File, previousResultID string) any {
+	resultID := h.getResultID(ctx, file)
+	if previousResultID == resultID {
+		slog.DebugContext(ctx, "diagnostics have not changed", slog.String("resultId", resultID))
+		return messages.UnchangedDocumentDiagnosticReport{
+			Kind:     messages.DocumentDiagnosticReportKindUnchanged,
+			ResultID: resultID,
+		}
+	}
+	report := messages.FullDocumentDiagnosticReport{
+		Kind:     messages.DocumentDiagnosticReportKindFull,
+		ResultID: resultID,
+		Items:    make([]messages.Diagnostic, 0),
+	}
+	if file.Skip {
+		slog.DebugContext(ctx, "skipping file")
+		return report
+	}
+	report.Items = h.diagnose(ctx, file)
+	return report
+}
+
+// getResultID identifies the diagnostics result by the file's content hash,
+// the generations of the workspace files and settings and the Nobl9 API cache epoch.
+// Since the file's diagnostics depend on the other files, for instance when checking references,
+// the result changes whenever any of the workspace files changes.
+// Likewise, once the cached Nobl9 API responses expire, the references have to be validated again.
+// The workspace diagnostics results are told apart from the document ones, as they may differ.
+func (h *Handler) getResultID(ctx context.Context, file *files.File) string {
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(file.Content))
+	resultID := strconv.FormatUint(hash.Sum64(), 16) +
+		"-" + strconv.FormatUint(h.fs.Generation(), 16) +
+		"-" + strconv.FormatUint(h.settingsGeneration.Load(), 16) +
+		"-" + strconv.FormatUint(h.diagnostics.CacheEpoch(), 16)
+	if isWorkspaceDiagnostic(ctx) {
+		resultID += "-workspace"
+	}
+	return resultID
+}
+
+type workspaceDiagnosticKey struct{}
+
+// withWorkspaceDiagnostic marks the context as handling the workspace/diagnostic request.
+func withWorkspaceDiagnostic(ctx context.Context) context.Context {
+	return context.WithValue(ctx, workspaceDiagnosticKey{}, true)
+}
+
+// isWorkspaceDiagnostic returns true if the context was marked with [withWorkspaceDiagnostic].
+func isWorkspaceDiagnostic(ctx context.Context) bool {
+	marked, _ := ctx.Value(workspaceDiagnosticKey{}).(bool)
+	return marked
+}
@@ -0,0 +1,123 @@
+package diagnostics
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/nobl9/nobl9-language-server/internal/files"
+	"github.com/nobl9/nobl9-language-server/internal/messages"
+)
+
+// RunFunc evaluates and publishes diagnostics for a single document version.
+// The context is cancelled once a newer version of the document is scheduled.
+type RunFunc func(ctx context.Context, item messages.TextDocumentItem)
+
+// NewScheduler creates a new [Scheduler].
+// Updates of the same document are debounced by the given duration
+// and at most maxConcurrent documents are diagnosed at the same time.
+func NewScheduler(run RunFunc, debounce time.Duration, maxConcurrent int) *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Scheduler{
+		run:       run,
+		debounce:  debounce,
+		afterFunc: func(d time.Duration, f func()) timer { return time.AfterFunc(d, f) },
+		semaphore: make(chan struct{}, max(maxConcurrent, 1)),
+		runs:      make(map[files.URI]*scheduledRun),
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+}
+
+// Scheduler schedules diagnostics runs per document.
+// Subsequent updates of the same document are coalesced into a single run of its latest version,
+// if a run for a superseded version is already in progress, it is cancelled.
+// Different documents are diagnosed in parallel.
+type Scheduler struct {
+	run       RunFunc
+	debounce  time.Duration
+	afterFunc afterFunc
+	semaphore chan struct{}
+	runs      map[files.URI]*scheduledRun
+	mu        sync.Mutex
+	ctx       context.Context
+	cancel    context.CancelFunc
+}
+
+// afterFunc calls f in its own goroutine after the duration elapses, just like [time.AfterFunc].
+type afterFunc func(d time.Duration, f func()) timer
+
+type timer interface {
+	Stop() bool
+}
+
+type scheduledRun struct {
+	timer  timer
+	cancel context.CancelFunc
+}
+
+// Schedule schedules diagnostics for the document version,
+// any pending or in-progress run for its previous version is cancelled.
+func (s *Scheduler) Schedule(item messages.TextDocumentItem) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ctx.Err() != nil {
+		return
+	}
+	s.cancelRun(item.URI)
+	ctx, cancel := context.WithCancel(s.ctx)
+	run := &scheduledRun{cancel: cancel}
+	run.timer = s.afterFunc(s.debounce, func() { s.execute(ctx, run, item) })
+	s.runs[item.URI] = run
+}
+
+// Cancel cancels pending or in-progress diagnostics of the document.
+func (s *Scheduler) Cancel(uri files.URI) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cancelRun(uri)
+}
+
+// Stop cancels all runs, no new runs can be scheduled afterward.
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for uri := range s.runs {
+		s.cancelRun(uri)
+	}
+	s.cancel()
+}
+
+func (s *Scheduler) cancelRun(uri files.URI) {
+	run, ok := s.runs[uri]
+	if !ok {
+		return
+	}
+	run.timer.Stop()
+	run.cancel()
+	delete(s.runs, uri)
+}
+
+func (s *Scheduler) execute(ctx context.Context, run *scheduledRun, item messages.TextDocumentItem) {
+	defer s.finish(run, item.URI)
+	select {
+	case s.semaphore <- struct{}{}:
+		defer func() { <-s.semaphore }()
+	case <-ctx.Done():
+		return
+	}
+	if ctx.Err() != nil {
+		return
+	}
+	s.run(ctx, item)
+}
+
+// finish releases the run's resources and removes it, unless it has already been superseded.
+func (s *Scheduler) finish(run *scheduledRun, uri files.URI) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	run.cancel()
+	if s.runs[uri] == run {
+		delete(s.runs, uri)
+	}
+}
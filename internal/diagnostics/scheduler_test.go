@@ -0,0 +1,198 @@
+package diagnostics
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nobl9/nobl9-language-server/internal/messages"
+)
+
+const testDebounce = 20 * time.Millisecond
+
+func TestScheduler_Schedule(t *testing.T) {
+	t.Parallel()
+
+	t.Run("coalesce updates of the same document", func(t *testing.T) {
+		t.Parallel()
+
+		runs := make(chan messages.TextDocumentItem, 10)
+		scheduler, clock := newTestScheduler(func(_ context.Context, item messages.TextDocumentItem) {
+			runs <- item
+		}, 1)
+
+		for version := 1; version <= 3; version++ {
+			scheduler.Schedule(messages.TextDocumentItem{URI: "foo.yaml", Version: version})
+		}
+
+		require.Equal(t, 1, clock.Pending())
+		clock.Fire()
+		assert.Equal(t, 3, receive(t, runs).Version)
+	})
+
+	t.Run("cancel in-progress run of a superseded version", func(t *testing.T) {
+		t.Parallel()
+
+		started := make(chan struct{})
+		cancelled := make(chan int, 1)
+		runs := make(chan messages.TextDocumentItem, 10)
+		scheduler, clock := newTestScheduler(func(ctx context.Context, item messages.TextDocumentItem) {
+			if item.Version == 1 {
+				close(started)
+				<-ctx.Done()
+				cancelled <- item.Version
+				return
+			}
+			runs <- item
+		}, 1)
+
+		scheduler.Schedule(messages.TextDocumentItem{URI: "foo.yaml", Version: 1})
+		clock.Fire()
+		receive(t, started)
+		scheduler.Schedule(messages.TextDocumentItem{URI: "foo.yaml", Version: 2})
+
+		assert.Equal(t, 1, receive(t, cancelled))
+		clock.Fire()
+		assert.Equal(t, 2, receive(t, runs).Version)
+	})
+
+	t.Run("diagnose different documents in parallel with a limit", func(t *testing.T) {
+		t.Parallel()
+
+		const limit = 2
+		var (
+			running    atomic.Int32
+			maxRunning atomic.Int32
+			wg         sync.WaitGroup
+		)
+		started := make(chan struct{}, 10)
+		release := make(chan struct{})
+		scheduler, clock := newTestScheduler(func(_ context.Context, _ messages.TextDocumentItem) {
+			defer wg.Done()
+			n := running.Add(1)
+			defer running.Add(-1)
+			for {
+				current := maxRunning.Load()
+				if n <= current || maxRunning.CompareAndSwap(current, n) {
+					break
+				}
+			}
+			started <- struct{}{}
+			<-release
+		}, limit)
+
+		uris := []string{"1.yaml", "2.yaml", "3.yaml", "4.yaml", "5.yaml"}
+		wg.Add(len(uris))
+		for _, uri := range uris {
+			scheduler.Schedule(messages.TextDocumentItem{URI: uri, Version: 1})
+		}
+		clock.Fire()
+		for range limit {
+			receive(t, started)
+		}
+		assert.Len(t, scheduler.semaphore, limit)
+		close(release)
+		wg.Wait()
+
+		assert.Equal(t, int32(limit), maxRunning.Load())
+	})
+}
+
+func TestScheduler_Cancel(t *testing.T) {
+	t.Parallel()
+
+	runs := make(chan messages.TextDocumentItem, 10)
+	scheduler, clock := newTestScheduler(func(_ context.Context, item messages.TextDocumentItem) {
+		runs <- item
+	}, 1)
+
+	scheduler.Schedule(messages.TextDocumentItem{URI: "foo.yaml", Version: 1})
+	scheduler.Schedule(messages.TextDocumentItem{URI: "bar.yaml", Version: 1})
+	scheduler.Cancel("foo.yaml")
+
+	require.Equal(t, 1, clock.Pending())
+	clock.Fire()
+	assert.Equal(t, "bar.yaml", receive(t, runs).URI)
+}
+
+func TestScheduler_Stop(t *testing.T) {
+	t.Parallel()
+
+	scheduler, clock := newTestScheduler(func(context.Context, messages.TextDocumentItem) {}, 1)
+
+	scheduler.Schedule(messages.TextDocumentItem{URI: "foo.yaml", Version: 1})
+	scheduler.Stop()
+	scheduler.Schedule(messages.TextDocumentItem{URI: "bar.yaml", Version: 1})
+
+	assert.Zero(t, clock.Pending())
+}
+
+func newTestScheduler(run RunFunc, maxConcurrent int) (*Scheduler, *fakeClock) {
+	clock := &fakeClock{}
+	scheduler := NewScheduler(run, testDebounce, maxConcurrent)
+	scheduler.afterFunc = clock.AfterFunc
+	return scheduler, clock
+}
+
+// fakeClock fires the debounce timers on demand instead of waiting for them.
+type fakeClock struct {
+	timers []*fakeTimer
+	mu     sync.Mutex
+}
+
+type fakeTimer struct {
+	fn      func()
+	stopped atomic.Bool
+}
+
+func (t *fakeTimer) Stop() bool { return !t.stopped.Swap(true) }
+
+func (c *fakeClock) AfterFunc(_ time.Duration, fn func()) timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := &fakeTimer{fn: fn}
+	c.timers = append(c.timers, t)
+	return t
+}
+
+// Pending returns the number of timers which were neither fired nor stopped.
+func (c *fakeClock) Pending() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n := 0
+	for _, t := range c.timers {
+		if !t.stopped.Load() {
+			n++
+		}
+	}
+	return n
+}
+
+// Fire fires all pending timers, each in its own goroutine.
+func (c *fakeClock) Fire() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, t := range c.timers {
+		if t.Stop() {
+			go t.fn()
+		}
+	}
+	c.timers = nil
+}
+
+func receive[T any](t *testing.T, ch <-chan T) T {
+	t.Helper()
+	select {
+	case v := <-ch:
+		return v
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "timed out waiting for diagnostics run")
+	}
+	var zero T
+	return zero
+}
@@ -0,0 +1,127 @@
+package diagnostics
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/nobl9/nobl9-go/manifest"
+
+	"github.com/nobl9/nobl9-language-server/internal/config"
+	"github.com/nobl9/nobl9-language-server/internal/files"
+	"github.com/nobl9/nobl9-language-server/internal/messages"
+	"github.com/nobl9/nobl9-language-server/internal/objectref"
+	"github.com/nobl9/nobl9-language-server/internal/workspace"
+)
+
+// unusedObjectKinds are the kinds of objects which serve no purpose unless other objects reference them.
+var unusedObjectKinds = []manifest.Kind{
+	manifest.KindAlertMethod,
+	manifest.KindAlertPolicy,
+	manifest.KindService,
+	manifest.KindUserGroup,
+}
+
+// checkUnused reports the valid objects of [unusedObjectKinds] which no other object references.
+// The validity of each of the file's objects is provided at its index by the caller.
+// The kinds of the referencing objects are derived from the [objectref] table.
+// If [config.DiagnosticsSettings.UnusedPlatform] is enabled and the whole workspace is diagnosed,
+// the references from the objects fetched from the Nobl9 API are checked as well.
+// If the API request fails, objects of the affected kind are not reported.
+func (d Provider) checkUnused(
+	ctx context.Context,
+	settings config.DiagnosticsSettings,
+	file *files.File,
+	valid []bool,
+) []messages.Diagnostic {
+	withPlatform := settings.IsUnusedPlatformEnabled() && isWorkspaceDiagnostic(ctx)
+	refsByKind := make(map[manifest.Kind][]*workspace.Reference)
+	failedKinds := make(map[manifest.Kind]bool)
+	var diagnostics []messages.Diagnostic
+	for i, object := range file.Objects {
+		// Just like the references, invalid objects are not checked.
+		if !valid[i] || object.Err != nil || object.Object == nil || object.Node == nil ||
+			!slices.Contains(unusedObjectKinds, object.Kind) {
+			continue
+		}
+		name := object.Object.GetName()
+		if name == "" {
+			continue
+		}
+		if _, fetched := refsByKind[object.Kind]; !fetched {
+			refs, err := d.getReferences(ctx, withPlatform, object.Kind)
+			if err != nil {
+				slog.ErrorContext(ctx, "failed to fetch objects for unused objects check",
+					slog.Any("error", err),
+					slog.String("kind", object.Kind.String()))
+				failedKinds[object.Kind] = true
+			}
+			refsByKind[object.Kind] = refs
+		}
+		if failedKinds[object.Kind] {
+			continue
+		}
+		refs := refsByKind[object.Kind]
+		identity := d.getObjectIdentity(ctx, object.Object)
+		if slices.ContainsFunc(refs, func(ref *workspace.Reference) bool {
+			return ref.Target.Name == name && d.getIdentity(ctx, ref.Target.Kind, ref.Target.Project) == identity
+		}) {
+			continue
+		}
+		diagnostics = append(diagnostics, messages.Diagnostic{
+			Range:    getRangeForNodePath(ctx, object.Node, "$.metadata.name"),
+			Severity: messages.DiagnosticSeverityInformation,
+			Source:   ptr(config.ServerName),
+			Message:  getUnusedMessage(withPlatform, object.Kind, name),
+			Tags:     []messages.DiagnosticTag{messages.DiagnosticTagUnnecessary},
+		})
+	}
+	return diagnostics
+}
+
+// getReferences returns the references to the objects of the given kind made by the workspace objects
+// and, if withPlatform is true, by the objects fetched from the Nobl9 API.
+func (d Provider) getReferences(
+	ctx context.Context,
+	withPlatform bool,
+	kind manifest.Kind,
+) ([]*workspace.Reference, error) {
+	refs := d.resolver.FindLocalReferences(ctx, kind)
+	if !withPlatform {
+		return refs, nil
+	}
+	for _, referencingKind := range objectref.GetReferencingKinds(kind) {
+		objects, err := d.objects.GetAllObjects(ctx, referencingKind)
+		if err != nil {
+			return nil, err
+		}
+		for _, object := range objects {
+			remote, err := workspace.NewRemoteObject(object)
+			if err != nil {
+				return nil, err
+			}
+			for _, ref := range remote.References() {
+				if ref.Target.Kind == kind && ref.Objective == "" {
+					refs = append(refs, ref)
+				}
+			}
+		}
+	}
+	return refs, nil
+}
+
+func getUnusedMessage(withPlatform bool, kind manifest.Kind, name string) string {
+	referencingKinds := objectref.GetReferencingKinds(kind)
+	kindNames := make([]string, 0, len(referencingKinds))
+	for _, referencingKind := range referencingKinds {
+		kindNames = append(kindNames, referencingKind.String())
+	}
+	message := fmt.Sprintf("%s %s is not referenced by any %s in the workspace",
+		kind, name, strings.Join(kindNames, " or "))
+	if withPlatform {
+		message += " nor in Nobl9"
+	}
+	return message
+}
@@ -0,0 +1,61 @@
+package files
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+
+	"github.com/nobl9/nobl9-language-server/internal/messages"
+)
+
+// ApplyContentChanges applies the changes to the content in the order they were sent by the client.
+// Each change is applied to the result of the previous one.
+// A change without a range replaces the whole content.
+// Ranges' character offsets are interpreted according to the provided encoding.
+func ApplyContentChanges(
+	content string,
+	changes []messages.TextDocumentContentChangeEvent,
+	encoding messages.PositionEncodingKind,
+) (string, error) {
+	for i, change := range changes {
+		if change.Range == nil {
+			content = change.Text
+			continue
+		}
+		start := getContentOffset(content, change.Range.Start, encoding)
+		end := getContentOffset(content, change.Range.End, encoding)
+		if start > end {
+			return "", errors.Errorf("invalid content change at index %d: range start is after its end", i)
+		}
+		content = content[:start] + change.Text + content[end:]
+	}
+	return content, nil
+}
+
+// getContentOffset converts the [messages.Position] to a byte offset within the content.
+// Positions which are outside the content are clamped to the end of the line or the end of the content.
+func getContentOffset(content string, pos messages.Position, encoding messages.PositionEncodingKind) int {
+	offset := 0
+	for range pos.Line {
+		idx := strings.IndexByte(content[offset:], '\n')
+		if idx == -1 {
+			return len(content)
+		}
+		offset += idx + 1
+	}
+	lineEnd := strings.IndexByte(content[offset:], '\n')
+	if lineEnd == -1 {
+		lineEnd = len(content) - offset
+	}
+	line := content[offset : offset+lineEnd]
+	for range decodeCharacter(line, pos.Character, encoding) {
+		if line == "" {
+			break
+		}
+		_, size := utf8.DecodeRuneInString(line)
+		line = line[size:]
+		offset += size
+	}
+	return offset
+}
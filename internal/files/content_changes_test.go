@@ -0,0 +1,98 @@
+package files
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nobl9/nobl9-language-server/internal/messages"
+)
+
+func TestApplyContentChanges(t *testing.T) {
+	content := "kind: SLO\nmetadata:\n  name: żółw\n"
+
+	tests := map[string]struct {
+		changes  []messages.TextDocumentContentChangeEvent
+		encoding messages.PositionEncodingKind
+		expected string
+		error    string
+	}{
+		"full content": {
+			changes: []messages.TextDocumentContentChangeEvent{
+				{Text: "kind: Project"},
+			},
+			expected: "kind: Project",
+		},
+		"insert": {
+			changes: []messages.TextDocumentContentChangeEvent{
+				{Range: ptr(messages.NewPointRange(3, 0)), Text: "  project: default\n"},
+			},
+			expected: "kind: SLO\nmetadata:\n  project: default\n  name: żółw\n",
+		},
+		"replace multi-byte characters": {
+			changes: []messages.TextDocumentContentChangeEvent{
+				{Range: ptr(messages.NewLineRange(3, 9, 10)), Text: "o"},
+			},
+			expected: "kind: SLO\nmetadata:\n  name: żołw\n",
+		},
+		"replace multi-byte characters with utf-8 offsets": {
+			changes: []messages.TextDocumentContentChangeEvent{
+				{Range: ptr(messages.NewLineRange(3, 10, 12)), Text: "o"},
+			},
+			encoding: messages.PositionEncodingUTF8,
+			expected: "kind: SLO\nmetadata:\n  name: żołw\n",
+		},
+		"replace multi-byte characters with utf-16 offsets": {
+			changes: []messages.TextDocumentContentChangeEvent{
+				{Range: ptr(messages.NewLineRange(3, 9, 10)), Text: "🐢"},
+				{Range: ptr(messages.NewLineRange(3, 11, 12)), Text: "o"},
+			},
+			encoding: messages.PositionEncodingUTF16,
+			expected: "kind: SLO\nmetadata:\n  name: ż🐢ow\n",
+		},
+		"delete across lines": {
+			changes: []messages.TextDocumentContentChangeEvent{
+				{Range: ptr(messages.NewRange(1, 9, 3, 12)), Text: ""},
+			},
+			expected: "kind: SLO\n",
+		},
+		"multiple changes are applied in order": {
+			changes: []messages.TextDocumentContentChangeEvent{
+				{Range: ptr(messages.NewLineRange(1, 6, 9)), Text: "Service"},
+				{Range: ptr(messages.NewLineRange(1, 6, 13)), Text: "Project"},
+				{Range: ptr(messages.NewPointRange(4, 0)), Text: "spec: {}\n"},
+			},
+			expected: "kind: Project\nmetadata:\n  name: żółw\nspec: {}\n",
+		},
+		"positions outside of the content are clamped": {
+			changes: []messages.TextDocumentContentChangeEvent{
+				{Range: ptr(messages.NewRange(3, 100, 10, 0)), Text: "!"},
+			},
+			expected: "kind: SLO\nmetadata:\n  name: żółw!",
+		},
+		"invalid range": {
+			changes: []messages.TextDocumentContentChangeEvent{
+				{Range: ptr(messages.NewRange(2, 0, 1, 0)), Text: ""},
+			},
+			error: "invalid content change at index 0: range start is after its end",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			encoding := tc.encoding
+			if encoding == "" {
+				encoding = messages.PositionEncodingUTF32
+			}
+			result, err := ApplyContentChanges(content, tc.changes, encoding)
+			if tc.error != "" {
+				require.EqualError(t, err, tc.error)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func ptr[T any](v T) *T { return &v }
@@ -5,6 +5,7 @@ import (
 	"log/slog"
 
 	"github.com/nobl9/nobl9-language-server/internal/logging"
+	"github.com/nobl9/nobl9-language-server/internal/messages"
 	"github.com/nobl9/nobl9-language-server/internal/yamlast"
 )
 
@@ -21,6 +22,14 @@ type File struct {
 	SimpleAST SimpleObjectFile
 	// Err is the error that occurred while parsing the file AST (if any).
 	Err error
+
+	positionEncoding messages.PositionEncodingKind
+}
+
+// GetPositionMapper returns a [PositionMapper] which converts positions within the file
+// between the server's representation and the one negotiated with the client.
+func (f *File) GetPositionMapper() *PositionMapper {
+	return NewPositionMapper(f.Content, f.positionEncoding)
 }
 
 // AddToLogContext adds basic file details to the logging context.
@@ -98,5 +107,7 @@ func (f *File) copy() *File {
 		Objects:   objects,
 		SimpleAST: f.SimpleAST,
 		Err:       f.Err,
+
+		positionEncoding: f.positionEncoding,
 	}
 }
@@ -3,27 +3,94 @@ package files
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"path/filepath"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	"github.com/bmatcuk/doublestar/v4"
 	"github.com/pkg/errors"
+
+	"github.com/nobl9/nobl9-language-server/internal/messages"
 )
 
 func NewFS(filePatterns []string) *FS {
 	return &FS{
 		files:        make(map[URI]*File),
+		indexedFiles: make(map[URI]*File),
 		filePatterns: filePatterns,
 		mu:           new(sync.RWMutex),
 	}
 }
 
+// FS keys the files by their [CanonicalURI], while the opened files keep the URI spelled by the client.
 type FS struct {
 	files map[URI]*File
+	// indexedFiles are read from the workspace and are not managed by the client.
+	// Whenever a file is opened by the client, its opened version takes precedence.
+	indexedFiles map[URI]*File
 	// filePatterns are assumed to be validated and normalized with [filepath.ToSlash].
 	filePatterns []string
-	mu           *sync.RWMutex
+	// positionEncoding is negotiated with the client.
+	positionEncoding messages.PositionEncodingKind
+	// generation is incremented whenever any of the opened or indexed files changes.
+	generation atomic.Uint64
+	mu         *sync.RWMutex
+}
+
+// NormalizeFilePatterns converts the file patterns with [filepath.ToSlash] and validates them.
+func NormalizeFilePatterns(patterns []string) ([]string, error) {
+	normalized := make([]string, 0, len(patterns))
+	for _, p := range patterns {
+		p = filepath.ToSlash(p)
+		if ok := doublestar.ValidatePattern(p); !ok {
+			return nil, fmt.Errorf("invalid file pattern: %s", p)
+		}
+		normalized = append(normalized, p)
+	}
+	return normalized, nil
+}
+
+// SetFilePatterns replaces the file patterns and reparses the opened files,
+// since they might no longer be skipped (or the other way around).
+// Indexed files have to be indexed again by the caller.
+// The file patterns must be normalized with [NormalizeFilePatterns].
+func (fs *FS) SetFilePatterns(ctx context.Context, filePatterns []string) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	fs.filePatterns = filePatterns
+	fs.generation.Add(1)
+	for uri, file := range fs.files {
+		// The version has not changed, the file has to be parsed from scratch.
+		reparsed := &File{URI: file.URI, Version: -1}
+		if err := fs.updateFile(ctx, reparsed, file.Content, file.Version); err != nil {
+			slog.ErrorContext(ctx, "failed to reparse file", slog.String("uri", uri), slog.Any("error", err))
+			continue
+		}
+		fs.files[uri] = reparsed
+	}
+}
+
+// GetFilePatterns returns the current file patterns.
+func (fs *FS) GetFilePatterns() []string {
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
+	return fs.filePatterns
+}
+
+// Generation identifies the state of the workspace files.
+// It changes whenever a file is opened, changed, closed, indexed or removed from the index.
+func (fs *FS) Generation() uint64 {
+	return fs.generation.Load()
+}
+
+// SetPositionEncoding sets the position encoding negotiated with the client.
+// It is used to interpret the client's content changes and to create each file's [PositionMapper].
+func (fs *FS) SetPositionEncoding(encoding messages.PositionEncodingKind) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	fs.positionEncoding = encoding
 }
 
 // GetFile returns a copy of [File] by its URI.
@@ -31,17 +98,91 @@ type FS struct {
 func (fs *FS) GetFile(uri URI) (*File, error) {
 	fs.mu.RLock()
 	defer fs.mu.RUnlock()
-	file, ok := fs.files[uri]
+	file, ok := fs.files[CanonicalURI(uri)]
 	if !ok {
 		return nil, fmt.Errorf("file not found: %s", uri)
 	}
-	return file.copy(), nil
+	return fs.copyFile(file), nil
+}
+
+// GetOpenedFiles returns copies of the files opened by the client.
+func (fs *FS) GetOpenedFiles() []*File {
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
+	result := make([]*File, 0, len(fs.files))
+	for _, file := range fs.files {
+		result = append(result, fs.copyFile(file))
+	}
+	return result
+}
+
+// GetFiles returns copies of all the files, both opened and indexed.
+func (fs *FS) GetFiles() []*File {
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
+	result := make([]*File, 0, len(fs.files)+len(fs.indexedFiles))
+	for _, file := range fs.files {
+		result = append(result, fs.copyFile(file))
+	}
+	for uri, file := range fs.indexedFiles {
+		if _, isOpen := fs.files[uri]; isOpen {
+			continue
+		}
+		result = append(result, fs.copyFile(file))
+	}
+	return result
+}
+
+// IndexFile parses and stores a file which was read from the workspace.
+// Files which are not Nobl9 configuration files are not stored.
+// It returns true if the file was stored in the index.
+func (fs *FS) IndexFile(ctx context.Context, uri URI, content string) (bool, error) {
+	// The file patterns may change concurrently,
+	// the file must be checked against the same patterns it's stored with.
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	skipFile, err := fs.shouldSkipFile(uri, content)
+	if err != nil {
+		return false, err
+	}
+	fs.generation.Add(1)
+	if skipFile {
+		delete(fs.indexedFiles, CanonicalURI(uri))
+		return false, nil
+	}
+	file := &File{URI: uri, Version: -1}
+	file.Update(ctx, 0, content)
+	fs.indexedFiles[CanonicalURI(uri)] = file
+	return true, nil
+}
+
+// RemoveIndexedFile removes the file from the index.
+func (fs *FS) RemoveIndexedFile(uri URI) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	delete(fs.indexedFiles, CanonicalURI(uri))
+	fs.generation.Add(1)
+}
+
+// RemoveIndexedPath removes the file from the index.
+// If the URI points to a directory, all the files inside it are removed.
+func (fs *FS) RemoveIndexedPath(uri URI) {
+	uri = CanonicalURI(uri)
+	dirPrefix := strings.TrimSuffix(uri, "/") + "/"
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	for indexedURI := range fs.indexedFiles {
+		if indexedURI == uri || strings.HasPrefix(indexedURI, dirPrefix) {
+			delete(fs.indexedFiles, indexedURI)
+		}
+	}
+	fs.generation.Add(1)
 }
 
 func (fs *FS) HasFile(uri URI) bool {
 	fs.mu.RLock()
 	defer fs.mu.RUnlock()
-	_, hasFile := fs.files[uri]
+	_, hasFile := fs.files[CanonicalURI(uri)]
 	return hasFile
 }
 
@@ -51,10 +192,11 @@ func (fs *FS) CloseFile(uri URI) error {
 	}
 	fs.mu.Lock()
 	defer fs.mu.Unlock()
-	if _, hasFile := fs.files[uri]; !hasFile {
+	if _, hasFile := fs.files[CanonicalURI(uri)]; !hasFile {
 		return errors.Errorf("file already closed: %s", uri)
 	}
-	delete(fs.files, uri)
+	delete(fs.files, CanonicalURI(uri))
+	fs.generation.Add(1)
 	return nil
 }
 
@@ -68,20 +210,49 @@ func (fs *FS) OpenFile(ctx context.Context, uri URI, content string, version int
 	if err := fs.updateFile(ctx, file, content, version); err != nil {
 		return err
 	}
-	fs.files[uri] = file
+	fs.files[CanonicalURI(uri)] = file
+	fs.generation.Add(1)
 	return nil
 }
 
 func (fs *FS) UpdateFile(ctx context.Context, uri URI, content string, version int) error {
 	fs.mu.Lock()
 	defer fs.mu.Unlock()
-	file, ok := fs.files[uri]
+	file, ok := fs.files[CanonicalURI(uri)]
+	if !ok {
+		return fmt.Errorf("file not found: %s", uri)
+	}
+	fs.generation.Add(1)
+	return fs.updateFile(ctx, file, content, version)
+}
+
+// ChangeFile applies the content changes sent by the client to the file and reparses it.
+func (fs *FS) ChangeFile(
+	ctx context.Context,
+	uri URI,
+	changes []messages.TextDocumentContentChangeEvent,
+	version int,
+) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	file, ok := fs.files[CanonicalURI(uri)]
 	if !ok {
 		return fmt.Errorf("file not found: %s", uri)
 	}
+	content, err := ApplyContentChanges(file.Content, changes, fs.positionEncoding)
+	if err != nil {
+		return err
+	}
+	fs.generation.Add(1)
 	return fs.updateFile(ctx, file, content, version)
 }
 
+func (fs *FS) copyFile(file *File) *File {
+	c := file.copy()
+	c.positionEncoding = fs.positionEncoding
+	return c
+}
+
 func (fs *FS) updateFile(ctx context.Context, file *File, content string, version int) error {
 	skipFile, err := fs.shouldSkipFile(file.URI, content)
 	if err != nil {
@@ -100,6 +271,7 @@ const (
 	nobl9ApiVersionPrefix = "apiVersion: n9/"
 )
 
+// shouldSkipFile must be called with fs.mu held.
 func (fs *FS) shouldSkipFile(uri URI, content string) (bool, error) {
 	if len(fs.filePatterns) == 0 {
 		if strings.HasPrefix(content, serverActivateComment) ||
@@ -109,7 +281,7 @@ func (fs *FS) shouldSkipFile(uri URI, content string) (bool, error) {
 		return true, nil
 	}
 
-	fileName, err := filePathFromURI(uri)
+	fileName, err := FilePathFromURI(uri)
 	if err != nil {
 		return true, err
 	}
@@ -7,6 +7,8 @@ import (
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/nobl9/nobl9-language-server/internal/messages"
 )
 
 func TestFS_CloseFile(t *testing.T) {
@@ -465,3 +467,185 @@ func TestFS_UpdateFile(t *testing.T) {
 		})
 	}
 }
+
+func TestFS_IndexFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		uri      URI
+		content  string
+		setup    func(fs *FS)
+		indexed  bool
+		expected []URI
+	}{
+		{
+			name:     "index Nobl9 file",
+			uri:      "file://file1",
+			content:  "apiVersion: n9/v1alpha",
+			setup:    func(fs *FS) {},
+			indexed:  true,
+			expected: []URI{"file://file1"},
+		},
+		{
+			name:     "skip non-Nobl9 file",
+			uri:      "file://file1",
+			content:  "content",
+			setup:    func(fs *FS) {},
+			expected: []URI{},
+		},
+		{
+			name:    "remove file which is no longer a Nobl9 file",
+			uri:     "file://file1",
+			content: "content",
+			setup: func(fs *FS) {
+				fs.indexedFiles["file://file1"] = &File{URI: "file://file1"}
+			},
+			expected: []URI{},
+		},
+		{
+			name:    "opened file takes precedence",
+			uri:     "file://file1",
+			content: "apiVersion: n9/v1alpha",
+			setup: func(fs *FS) {
+				fs.files["file://file1"] = &File{URI: "file://file1", Version: 2}
+			},
+			indexed:  true,
+			expected: []URI{"file://file1"},
+		},
+		{
+			name:    "opened file spelled differently by the client takes precedence",
+			uri:     "file:///c:/dir/file1",
+			content: "apiVersion: n9/v1alpha",
+			setup: func(fs *FS) {
+				require.NoError(t, fs.OpenFile(context.Background(), "file:///c%3A/dir/file1", "content", 2))
+			},
+			indexed:  true,
+			expected: []URI{"file:///c%3A/dir/file1"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			fs := NewFS(nil)
+			tc.setup(fs)
+
+			indexed, err := fs.IndexFile(context.Background(), tc.uri, tc.content)
+			require.NoError(t, err)
+			assert.Equal(t, tc.indexed, indexed)
+
+			uris := make([]URI, 0)
+			for _, file := range fs.GetFiles() {
+				uris = append(uris, file.URI)
+				if fs.HasFile(file.URI) {
+					assert.Equal(t, 2, file.Version)
+				} else {
+					assert.Equal(t, tc.content, file.Content)
+				}
+			}
+			assert.Equal(t, tc.expected, uris)
+		})
+	}
+}
+
+func TestFS_RemoveIndexedPath(t *testing.T) {
+	fs := NewFS(nil)
+	for _, uri := range []URI{
+		"file:///dir/file1.yaml",
+		"file:///dir/nested/file2.yaml",
+		"file:///dir2/file3.yaml",
+		"file:///file4.yaml",
+	} {
+		fs.indexedFiles[uri] = &File{URI: uri}
+	}
+
+	fs.RemoveIndexedPath("file:///file4.yaml")
+	fs.RemoveIndexedPath("file:///dir")
+
+	assert.Len(t, fs.indexedFiles, 1)
+	assert.Contains(t, fs.indexedFiles, "file:///dir2/file3.yaml")
+}
+
+func TestFS_ChangeFile(t *testing.T) {
+	tests := []struct {
+		name            string
+		uri             URI
+		changes         []messages.TextDocumentContentChangeEvent
+		expectedContent string
+		error           error
+	}{
+		{
+			name: "full content change",
+			uri:  "file://file1",
+			changes: []messages.TextDocumentContentChangeEvent{
+				{Text: "apiVersion: n9/v1alpha\nkind: Service"},
+			},
+			expectedContent: "apiVersion: n9/v1alpha\nkind: Service",
+		},
+		{
+			name: "incremental changes",
+			uri:  "file://file1",
+			changes: []messages.TextDocumentContentChangeEvent{
+				{Range: ptr(messages.NewLineRange(2, 6, 13)), Text: "Service"},
+				{Range: ptr(messages.NewPointRange(3, 0)), Text: "\nmetadata:"},
+			},
+			expectedContent: "apiVersion: n9/v1alpha\nkind: Service\nmetadata:",
+		},
+		{
+			name: "change non-existing file",
+			uri:  "file://file2",
+			changes: []messages.TextDocumentContentChangeEvent{
+				{Text: "content"},
+			},
+			error: errors.New("file not found: file://file2"),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			fs := NewFS(nil)
+			fs.files["file://file1"] = &File{
+				URI:     "file://file1",
+				Version: 1,
+				Content: "apiVersion: n9/v1alpha\nkind: Project",
+			}
+
+			err := fs.ChangeFile(context.Background(), tc.uri, tc.changes, 2)
+			if tc.error != nil {
+				assert.EqualError(t, err, tc.error.Error())
+				return
+			}
+			require.NoError(t, err)
+			file, err := fs.GetFile(tc.uri)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedContent, file.Content)
+			assert.Equal(t, 2, file.Version)
+		})
+	}
+}
+
+func TestFS_SetFilePatterns(t *testing.T) {
+	fs := NewFS(nil)
+	ctx := context.Background()
+	require.NoError(t, fs.OpenFile(ctx, "file:///foo/service.yaml", "kind: Service", 1))
+	require.NoError(t, fs.OpenFile(ctx, "file:///bar/project.yaml", "apiVersion: n9/v1alpha", 1))
+
+	fs.SetFilePatterns(ctx, []string{"/foo/*.yaml"})
+
+	service, err := fs.GetFile("file:///foo/service.yaml")
+	require.NoError(t, err)
+	assert.False(t, service.Skip)
+	assert.Equal(t, 1, service.Version)
+	assert.Equal(t, "kind: Service", service.Content)
+	project, err := fs.GetFile("file:///bar/project.yaml")
+	require.NoError(t, err)
+	assert.True(t, project.Skip)
+	assert.Equal(t, []string{"/foo/*.yaml"}, fs.GetFilePatterns())
+}
+
+func TestNormalizeFilePatterns(t *testing.T) {
+	patterns, err := NormalizeFilePatterns([]string{"foo/**/*.yaml", "bar"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"foo/**/*.yaml", "bar"}, patterns)
+
+	_, err = NormalizeFilePatterns([]string{"foo", "\\"})
+	assert.EqualError(t, err, "invalid file pattern: \\")
+}
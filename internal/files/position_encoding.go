@@ -0,0 +1,110 @@
+package files
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/nobl9/nobl9-language-server/internal/messages"
+)
+
+// PositionMapper converts positions between the server's internal representation,
+// where [messages.Position.Character] is counted in Unicode code points,
+// and the [messages.PositionEncodingKind] negotiated with the client.
+// Positions which are outside the content are left intact.
+type PositionMapper struct {
+	encoding messages.PositionEncodingKind
+	lines    []string
+}
+
+// NewPositionMapper creates a new [PositionMapper] for the given content.
+// An empty encoding is equivalent to [messages.PositionEncodingUTF32], which requires no conversion.
+func NewPositionMapper(content string, encoding messages.PositionEncodingKind) *PositionMapper {
+	m := &PositionMapper{encoding: encoding}
+	if !m.isNoop() {
+		m.lines = strings.Split(content, "\n")
+	}
+	return m
+}
+
+// ToClientPosition converts the internal position to the client's encoding.
+func (m *PositionMapper) ToClientPosition(pos messages.Position) messages.Position {
+	if line, ok := m.getLine(pos.Line); ok {
+		pos.Character = encodeCharacter(line, pos.Character, m.encoding)
+	}
+	return pos
+}
+
+// ToClientRange converts the internal range to the client's encoding.
+func (m *PositionMapper) ToClientRange(rng messages.Range) messages.Range {
+	return messages.Range{
+		Start: m.ToClientPosition(rng.Start),
+		End:   m.ToClientPosition(rng.End),
+	}
+}
+
+// FromClientPosition converts the position sent by the client to the internal representation.
+func (m *PositionMapper) FromClientPosition(pos messages.Position) messages.Position {
+	if line, ok := m.getLine(pos.Line); ok {
+		pos.Character = decodeCharacter(line, pos.Character, m.encoding)
+	}
+	return pos
+}
+
+// FromClientRange converts the range sent by the client to the internal representation.
+func (m *PositionMapper) FromClientRange(rng messages.Range) messages.Range {
+	return messages.Range{
+		Start: m.FromClientPosition(rng.Start),
+		End:   m.FromClientPosition(rng.End),
+	}
+}
+
+func (m *PositionMapper) getLine(line int) (string, bool) {
+	if m.isNoop() || line < 0 || line >= len(m.lines) {
+		return "", false
+	}
+	return m.lines[line], true
+}
+
+func (m *PositionMapper) isNoop() bool {
+	return m.encoding == "" || m.encoding == messages.PositionEncodingUTF32
+}
+
+// encodeCharacter converts the offset in Unicode code points to the encoding's code units.
+func encodeCharacter(line string, character int, encoding messages.PositionEncodingKind) int {
+	units := 0
+	for ; character > 0 && line != ""; character-- {
+		r, size := utf8.DecodeRuneInString(line)
+		line = line[size:]
+		units += getCodeUnitsCount(r, size, encoding)
+	}
+	return units + character
+}
+
+// decodeCharacter converts the offset in the encoding's code units to Unicode code points.
+// Offsets pointing in the middle of a code point are rounded up to the next one.
+func decodeCharacter(line string, units int, encoding messages.PositionEncodingKind) int {
+	character := 0
+	for ; units > 0 && line != ""; character++ {
+		r, size := utf8.DecodeRuneInString(line)
+		line = line[size:]
+		units -= getCodeUnitsCount(r, size, encoding)
+	}
+	return character + max(units, 0)
+}
+
+// getCodeUnitsCount returns the number of the encoding's code units required to represent the rune.
+// Size is the number of bytes the rune occupies in UTF-8.
+func getCodeUnitsCount(r rune, size int, encoding messages.PositionEncodingKind) int {
+	switch encoding {
+	case messages.PositionEncodingUTF8:
+		return size
+	case messages.PositionEncodingUTF16:
+		// Runes outside the Basic Multilingual Plane are encoded with surrogate pairs.
+		if r > 0xFFFF {
+			return 2
+		}
+		return 1
+	default:
+		return 1
+	}
+}
@@ -0,0 +1,60 @@
+package files
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nobl9/nobl9-language-server/internal/messages"
+)
+
+func TestPositionMapper(t *testing.T) {
+	content := "metadata:\n  displayName: Zespół 🐢 チーム\n"
+
+	tests := map[messages.PositionEncodingKind]struct {
+		internal messages.Position
+		client   messages.Position
+	}{
+		messages.PositionEncodingUTF8: {
+			internal: messages.Position{Line: 1, Character: 24},
+			client:   messages.Position{Line: 1, Character: 29},
+		},
+		messages.PositionEncodingUTF16: {
+			internal: messages.Position{Line: 1, Character: 24},
+			client:   messages.Position{Line: 1, Character: 25},
+		},
+		messages.PositionEncodingUTF32: {
+			internal: messages.Position{Line: 1, Character: 24},
+			client:   messages.Position{Line: 1, Character: 24},
+		},
+	}
+	for encoding, tc := range tests {
+		t.Run(string(encoding), func(t *testing.T) {
+			mapper := NewPositionMapper(content, encoding)
+			assert.Equal(t, tc.client, mapper.ToClientPosition(tc.internal))
+			assert.Equal(t, tc.internal, mapper.FromClientPosition(tc.client))
+		})
+	}
+
+	t.Run("ascii line is not converted", func(t *testing.T) {
+		mapper := NewPositionMapper(content, messages.PositionEncodingUTF16)
+		pos := messages.Position{Line: 0, Character: 8}
+		assert.Equal(t, pos, mapper.ToClientPosition(pos))
+		assert.Equal(t, pos, mapper.FromClientPosition(pos))
+	})
+	t.Run("position past the end of line is shifted", func(t *testing.T) {
+		mapper := NewPositionMapper(content, messages.PositionEncodingUTF8)
+		pos := messages.Position{Line: 1, Character: 33}
+		assert.Equal(t, messages.Position{Line: 1, Character: 44}, mapper.ToClientPosition(pos))
+	})
+	t.Run("line outside of content is not converted", func(t *testing.T) {
+		mapper := NewPositionMapper(content, messages.PositionEncodingUTF8)
+		pos := messages.Position{Line: 5, Character: 3}
+		assert.Equal(t, pos, mapper.ToClientPosition(pos))
+	})
+	t.Run("offset in the middle of a surrogate pair is rounded up", func(t *testing.T) {
+		mapper := NewPositionMapper(content, messages.PositionEncodingUTF16)
+		pos := messages.Position{Line: 1, Character: 23}
+		assert.Equal(t, messages.Position{Line: 1, Character: 23}, mapper.FromClientPosition(pos))
+	})
+}
@@ -3,12 +3,16 @@ package files
 import (
 	"fmt"
 	"net/url"
+	"runtime"
 	"strings"
 )
 
 type URI = string
 
-func filePathFromURI(uri URI) (string, error) {
+// FilePathFromURI converts a file URI into a native file system path.
+// Percent-encoded characters, like spaces or '#', are decoded.
+// On Windows, drive letters (file:///C:/dir) and UNC paths (file://server/share) are supported.
+func FilePathFromURI(uri URI) (string, error) {
 	parsedURL, err := url.ParseRequestURI(uri)
 	if err != nil {
 		return "", fmt.Errorf("failed to parse URI %v: %w", uri, err)
@@ -16,5 +20,60 @@ func filePathFromURI(uri URI) (string, error) {
 	if parsedURL.Scheme != "file" {
 		return "", fmt.Errorf("only file URIs are supported, got %v", parsedURL.Scheme)
 	}
-	return strings.TrimPrefix(uri, "file://"), nil
+	return toFilePath(parsedURL.Host, parsedURL.Path, runtime.GOOS == "windows"), nil
+}
+
+// URIFromFilePath converts a native file system path into a percent-encoded file URI.
+// It is the inverse of [FilePathFromURI].
+func URIFromFilePath(path string) URI {
+	return fromFilePath(path, runtime.GOOS == "windows")
+}
+
+// CanonicalURI re-encodes the file URI, this way the same file is always identified by the same URI,
+// regardless of how the client spelled it, e.g. file:///c%3A/dir and file:///c:/dir.
+// URIs which cannot be parsed or are not file URIs are returned as is.
+func CanonicalURI(uri URI) URI {
+	parsedURL, err := url.ParseRequestURI(uri)
+	if err != nil || parsedURL.Scheme != "file" {
+		return uri
+	}
+	return (&url.URL{Scheme: "file", Host: parsedURL.Host, Path: parsedURL.Path}).String()
+}
+
+func toFilePath(host, path string, windows bool) string {
+	if !windows {
+		// Host is kept for the authority-based URIs, e.g. file://server/share.
+		return host + path
+	}
+	switch {
+	case host != "" && host != "localhost":
+		// Authority-based URIs point to the UNC paths, e.g. \\server\share.
+		path = "//" + host + path
+	case hasDriveLetter(strings.TrimPrefix(path, "/")):
+		path = strings.TrimPrefix(path, "/")
+	}
+	return strings.ReplaceAll(path, "/", `\`)
+}
+
+func fromFilePath(path string, windows bool) URI {
+	if windows {
+		path = strings.ReplaceAll(path, `\`, "/")
+		if strings.HasPrefix(path, "//") {
+			host, share, _ := strings.Cut(strings.TrimPrefix(path, "//"), "/")
+			return (&url.URL{Scheme: "file", Host: host, Path: "/" + share}).String()
+		}
+	}
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return (&url.URL{Scheme: "file", Path: path}).String()
+}
+
+// hasDriveLetter checks if the slash-separated path starts with a Windows drive letter, e.g. C:/dir.
+func hasDriveLetter(path string) bool {
+	if len(path) < 2 || path[1] != ':' || (len(path) > 2 && path[2] != '/') {
+		return false
+	}
+	letter := path[0]
+	return ('a' <= letter && letter <= 'z') || ('A' <= letter && letter <= 'Z')
 }
@@ -0,0 +1,86 @@
+package files
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestURIFromFilePath(t *testing.T) {
+	tests := map[string]struct {
+		path string
+		uri  URI
+	}{
+		"plain path": {
+			path: "/home/user/project/slo.yaml",
+			uri:  "file:///home/user/project/slo.yaml",
+		},
+		"special characters": {
+			path: "/home/user/my project/#1/100%.yaml",
+			uri:  "file:///home/user/my%20project/%231/100%25.yaml",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.uri, URIFromFilePath(tc.path))
+			path, err := FilePathFromURI(tc.uri)
+			require.NoError(t, err)
+			assert.Equal(t, tc.path, path)
+		})
+	}
+}
+
+func TestFilePathFromURI_NotFileScheme(t *testing.T) {
+	_, err := FilePathFromURI("https://example.com/slo.yaml")
+	assert.Error(t, err)
+}
+
+func TestURIFromFilePath_Windows(t *testing.T) {
+	tests := map[string]struct {
+		path string
+		uri  URI
+	}{
+		"drive letter": {
+			path: `C:\Users\user\my project\slo.yaml`,
+			uri:  "file:///C:/Users/user/my%20project/slo.yaml",
+		},
+		"UNC path": {
+			path: `\\server\share\project\slo.yaml`,
+			uri:  "file://server/share/project/slo.yaml",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.uri, fromFilePath(tc.path, true))
+			parsedURL, err := url.ParseRequestURI(tc.uri)
+			require.NoError(t, err)
+			assert.Equal(t, tc.path, toFilePath(parsedURL.Host, parsedURL.Path, true))
+		})
+	}
+	t.Run("encoded drive letter", func(t *testing.T) {
+		parsedURL, err := url.ParseRequestURI("file:///c%3A/Users/user/slo.yaml")
+		require.NoError(t, err)
+		assert.Equal(t, `c:\Users\user\slo.yaml`, toFilePath(parsedURL.Host, parsedURL.Path, true))
+	})
+}
+
+func TestCanonicalURI(t *testing.T) {
+	tests := map[string]struct {
+		uri      URI
+		expected URI
+	}{
+		"canonical":            {uri: "file:///home/user/slo.yaml", expected: "file:///home/user/slo.yaml"},
+		"encoded drive letter": {uri: "file:///c%3A/Users/slo.yaml", expected: "file:///c:/Users/slo.yaml"},
+		"encoded at sign":      {uri: "file:///home/%40user/slo.yaml", expected: "file:///home/@user/slo.yaml"},
+		"unencoded space":      {uri: "file:///home/my user/slo.yaml", expected: "file:///home/my%20user/slo.yaml"},
+		"authority":            {uri: "file://server/share/slo.yaml", expected: "file://server/share/slo.yaml"},
+		"not a file URI":       {uri: "untitled:Untitled-1", expected: "untitled:Untitled-1"},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, CanonicalURI(tc.uri))
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package folding
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/nobl9/nobl9-language-server/internal/files"
+	"github.com/nobl9/nobl9-language-server/internal/messages"
+	"github.com/nobl9/nobl9-language-server/internal/yamlastsimple"
+)
+
+func NewHandler(files *files.FS) *Handler {
+	return &Handler{files: files}
+}
+
+type Handler struct {
+	files *files.FS
+}
+
+// Handle returns folding ranges for every object, nested mapping, list element and multi-line scalar.
+// Folding ranges are computed from the [files.SimpleObjectFile],
+// which means they're available even if the file is not a valid YAML.
+func (h *Handler) Handle(ctx context.Context, params messages.FoldingRangeParams) (any, error) {
+	file, err := h.files.GetFile(params.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+	ctx = file.AddToLogContext(ctx)
+	if file.Skip {
+		slog.DebugContext(ctx, "skipping file")
+		return nil, nil
+	}
+	return buildFoldingRanges(file.SimpleAST), nil
+}
+
+// buildFoldingRanges creates a [messages.FoldingRange] for every object in the file and for every line
+// which is followed by more indented lines.
+// If multiple ranges start at the same line, only the outermost one is kept.
+func buildFoldingRanges(file files.SimpleObjectFile) []messages.FoldingRange {
+	ranges := make([]messages.FoldingRange, 0)
+	startLines := make(map[int]bool)
+	addRange := func(doc *yamlastsimple.Document, start, end int) {
+		if end <= start || startLines[doc.Offset+start] {
+			return
+		}
+		startLines[doc.Offset+start] = true
+		ranges = append(ranges, messages.FoldingRange{
+			StartLine: doc.Offset + start,
+			EndLine:   doc.Offset + end,
+		})
+	}
+	for _, node := range file {
+		lines := node.Doc.Lines
+		first, last := -1, -1
+		for i, line := range lines {
+			if isContentLine(line) {
+				if first == -1 {
+					first = i
+				}
+				last = i
+			}
+		}
+		if first == -1 {
+			continue
+		}
+		addRange(node.Doc, first, last)
+		for i, line := range lines {
+			if line.IsType(yamlastsimple.LineTypeList) {
+				addRange(node.Doc, i, findBlockEnd(lines, i, line.GetIndent()-2, true))
+			}
+			if line.IsType(yamlastsimple.LineTypeMapping) {
+				addRange(node.Doc, i, findBlockEnd(lines, i, line.GetIndent(), false))
+			}
+		}
+	}
+	return ranges
+}
+
+// findBlockEnd returns the index of the last line which is nested under the line at the given index.
+// Nested lines are the ones which are indented deeper than the column of the parent's key
+// (or sequence entry indicator if isListElement is true).
+// Sequences are allowed to be placed at the same column as their parent mapping key.
+// Blank lines and comments neither end the block nor extend it.
+func findBlockEnd(lines []*yamlastsimple.Line, idx, column int, isListElement bool) int {
+	end := idx
+	for i := idx + 1; i < len(lines); i++ {
+		line := lines[i]
+		if !isContentLine(line) {
+			if line.IsType(yamlastsimple.LineTypeDocSeparator) {
+				break
+			}
+			continue
+		}
+		lineColumn := line.GetIndent()
+		isList := line.IsType(yamlastsimple.LineTypeList)
+		if isList {
+			lineColumn -= 2
+		}
+		if lineColumn > column || (!isListElement && isList && lineColumn == column) {
+			end = i
+			continue
+		}
+		break
+	}
+	return end
+}
+
+func isContentLine(line *yamlastsimple.Line) bool {
+	return !line.IsType(yamlastsimple.LineTypeEmpty |
+		yamlastsimple.LineTypeComment |
+		yamlastsimple.LineTypeDocSeparator)
+}
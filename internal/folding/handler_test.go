@@ -0,0 +1,83 @@
+package folding
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nobl9/nobl9-language-server/internal/files"
+	"github.com/nobl9/nobl9-language-server/internal/messages"
+	"github.com/nobl9/nobl9-language-server/internal/testutils"
+)
+
+var testDir = filepath.Join(testutils.FindModuleRoot(), "internal", "folding", "testdata")
+
+func TestHandler_Handle(t *testing.T) {
+	t.Parallel()
+
+	fileSystem := files.NewFS(nil)
+	testutils.RegisterTestFiles(t, fileSystem, testDir)
+	handler := NewHandler(fileSystem)
+
+	tests := map[string]struct {
+		uri      string
+		expected []messages.FoldingRange
+	}{
+		"objects, mappings, list elements and block scalars": {
+			uri: getTestFileURI("slo.yaml"),
+			expected: []messages.FoldingRange{
+				newFoldingRange(2, 27),
+				newFoldingRange(4, 6),
+				newFoldingRange(7, 27),
+				newFoldingRange(8, 11),
+				newFoldingRange(13, 15),
+				newFoldingRange(14, 15),
+				newFoldingRange(17, 27),
+				newFoldingRange(18, 24),
+				newFoldingRange(20, 24),
+				newFoldingRange(21, 24),
+				newFoldingRange(22, 24),
+				newFoldingRange(23, 24),
+				newFoldingRange(26, 27),
+				newFoldingRange(29, 37),
+				newFoldingRange(31, 33),
+				newFoldingRange(34, 37),
+				newFoldingRange(35, 37),
+				newFoldingRange(36, 37),
+				newFoldingRange(38, 41),
+				newFoldingRange(40, 41),
+			},
+		},
+		"invalid YAML": {
+			uri: getTestFileURI("invalid.yaml"),
+			expected: []messages.FoldingRange{
+				newFoldingRange(1, 7),
+				newFoldingRange(3, 5),
+				newFoldingRange(6, 7),
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			result, err := handler.Handle(context.Background(), messages.FoldingRangeParams{
+				TextDocument: messages.TextDocumentIdentifier{URI: tc.uri},
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+// newFoldingRange creates a [messages.FoldingRange] from 1-based line numbers.
+func newFoldingRange(start, end int) messages.FoldingRange {
+	return messages.FoldingRange{StartLine: start - 1, EndLine: end - 1}
+}
+
+func getTestFileURI(name string) string {
+	return filepath.Join(testDir, name)
+}
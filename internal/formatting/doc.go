@@ -0,0 +1,2 @@
+// Package formatting formats Nobl9 configuration files in a canonical format.
+package formatting
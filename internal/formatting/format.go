@@ -0,0 +1,121 @@
+package formatting
+
+import (
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/lexer"
+	"github.com/goccy/go-yaml/parser"
+	"github.com/goccy/go-yaml/token"
+	"github.com/pkg/errors"
+)
+
+// Format formats the YAML content in the canonical Nobl9 format:
+//   - indentation is normalized to two spaces,
+//   - documents are separated with a single '---' line,
+//   - strings are quoted only if needed, with double quotes,
+//   - keys are ordered as defined by the SDK docs, e.g. apiVersion, kind, metadata and spec,
+//   - comments and single blank lines are preserved.
+//
+// The formatted content is verified to hold the same values and comments as the original content,
+// if that's not the case, an error is returned.
+func Format(content string, docs docsProvider) (string, error) {
+	file, err := parser.Parse(lexer.Tokenize(content), parser.ParseComments)
+	if err != nil {
+		return "", err
+	}
+	p := newPrinter(docs, content)
+	for i, doc := range file.Docs {
+		if i > 0 {
+			p.buf.WriteString("---\n")
+		}
+		if err = printDocument(p, doc); err != nil {
+			return "", err
+		}
+	}
+	formatted := p.String()
+	if err = verifyFormatting(content, formatted); err != nil {
+		return "", err
+	}
+	return formatted, nil
+}
+
+func printDocument(p *printer, doc *ast.DocumentNode) error {
+	if seq, ok := doc.Body.(*ast.SequenceNode); ok {
+		return p.printListOfObjects(seq, 0)
+	}
+	return p.printObject(doc.Body, 0)
+}
+
+// verifyFormatting ensures the formatted content did not lose any values or comments.
+// It compares both the lexer tokens and the decoded documents.
+func verifyFormatting(original, formatted string) error {
+	if !slices.Equal(getTokens(original), getTokens(formatted)) {
+		return errors.New("formatting did not preserve all values or comments")
+	}
+	originalValues, err := decodeDocuments(original)
+	if err != nil {
+		return err
+	}
+	formattedValues, err := decodeDocuments(formatted)
+	if err != nil {
+		return errors.Wrap(err, "formatted content is not a valid YAML")
+	}
+	if !reflect.DeepEqual(originalValues, formattedValues) {
+		return errors.New("formatting did not preserve all values")
+	}
+	return nil
+}
+
+// structuralTokens are the tokens which can be added, removed or changed by formatting.
+var structuralTokens = []token.Type{
+	token.SequenceEntryType,
+	token.MappingKeyType,
+	token.MappingValueType,
+	token.CollectEntryType,
+	token.SequenceStartType,
+	token.SequenceEndType,
+	token.MappingStartType,
+	token.MappingEndType,
+	token.DocumentHeaderType,
+	token.DocumentEndType,
+	token.SpaceType,
+}
+
+// getTokens returns a sorted list of all non-structural tokens, including comments.
+// Quoted strings are treated as plain strings, as the quoting can change.
+func getTokens(content string) []string {
+	var tokens []string
+	for _, tk := range lexer.Tokenize(content) {
+		if slices.Contains(structuralTokens, tk.Type) {
+			continue
+		}
+		typ := tk.Type
+		if typ == token.SingleQuoteType || typ == token.DoubleQuoteType {
+			typ = token.StringType
+		}
+		tokens = append(tokens, typ.String()+":"+strings.TrimSpace(tk.Value))
+	}
+	slices.Sort(tokens)
+	return tokens
+}
+
+func decodeDocuments(content string) ([]any, error) {
+	dec := yaml.NewDecoder(strings.NewReader(content))
+	var documents []any
+	for {
+		var v any
+		err := dec.Decode(&v)
+		if errors.Is(err, io.EOF) {
+			return documents, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		documents = append(documents, v)
+	}
+}
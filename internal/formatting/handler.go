@@ -0,0 +1,99 @@
+package formatting
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/nobl9/nobl9-language-server/internal/files"
+	"github.com/nobl9/nobl9-language-server/internal/messages"
+)
+
+func NewHandler(files *files.FS, docs docsProvider) *Handler {
+	return &Handler{
+		files: files,
+		docs:  docs,
+	}
+}
+
+type Handler struct {
+	files *files.FS
+	docs  docsProvider
+}
+
+// HandleFormatting formats the whole document.
+// The formatting options sent by the client are ignored,
+// Nobl9 configuration is always formatted with two spaces indentation.
+func (h *Handler) HandleFormatting(ctx context.Context, params messages.DocumentFormattingParams) (any, error) {
+	file, err := h.files.GetFile(params.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+	ctx = file.AddToLogContext(ctx)
+	if file.Skip {
+		slog.DebugContext(ctx, "skipping file")
+		return nil, nil
+	}
+	if file.Err != nil {
+		slog.DebugContext(ctx, "cannot format file with syntax errors", slog.Any("error", file.Err))
+		return nil, nil
+	}
+
+	formatted, err := Format(file.Content, h.docs)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to format file", slog.Any("error", err))
+		return nil, nil
+	}
+	edits := make([]messages.TextEdit, 0, 1)
+	if formatted != file.Content {
+		edits = append(edits, messages.TextEdit{
+			Range:   file.GetPositionMapper().ToClientRange(getContentRange(file.Content)),
+			NewText: formatted,
+		})
+	}
+	return edits, nil
+}
+
+// HandleRangeFormatting formats the objects or their parts which overlap with the selected range.
+// Same as with [Handler.HandleFormatting], the formatting options are ignored.
+func (h *Handler) HandleRangeFormatting(
+	ctx context.Context,
+	params messages.DocumentRangeFormattingParams,
+) (any, error) {
+	file, err := h.files.GetFile(params.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+	ctx = file.AddToLogContext(ctx)
+	if file.Skip {
+		slog.DebugContext(ctx, "skipping file")
+		return nil, nil
+	}
+	if file.Err != nil {
+		slog.DebugContext(ctx, "cannot format file with syntax errors", slog.Any("error", file.Err))
+		return nil, nil
+	}
+
+	mapper := file.GetPositionMapper()
+	edits, err := FormatRange(file, h.docs, mapper.FromClientRange(params.Range))
+	if err != nil {
+		slog.WarnContext(ctx, "failed to format range", slog.Any("error", err))
+		return nil, nil
+	}
+	for i := range edits {
+		edits[i].Range = mapper.ToClientRange(edits[i].Range)
+	}
+	return edits, nil
+}
+
+// getContentRange returns a [messages.Range] spanning over the whole content.
+func getContentRange(content string) messages.Range {
+	lastLineIdx := strings.LastIndex(content, "\n") + 1
+	return messages.Range{
+		End: messages.Position{
+			Line:      strings.Count(content, "\n"),
+			Character: utf8.RuneCountInString(content[lastLineIdx:]),
+		},
+	}
+}
@@ -0,0 +1,203 @@
+package formatting
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nobl9/nobl9-language-server/internal/files"
+	"github.com/nobl9/nobl9-language-server/internal/messages"
+	"github.com/nobl9/nobl9-language-server/internal/sdkdocs"
+	"github.com/nobl9/nobl9-language-server/internal/testutils"
+)
+
+var testDir = filepath.Join(testutils.FindModuleRoot(), "internal", "formatting", "testdata")
+
+func TestHandler_HandleFormatting(t *testing.T) {
+	t.Parallel()
+
+	fileSystem := files.NewFS(nil)
+	testutils.RegisterTestFiles(t, fileSystem, testDir)
+	docs, err := sdkdocs.New()
+	require.NoError(t, err)
+	handler := NewHandler(fileSystem, docs)
+
+	tests := map[string]struct {
+		uri      string
+		expected any
+	}{
+		"unformatted file": {
+			uri: getTestFileURI("unformatted.yaml"),
+			expected: []messages.TextEdit{{
+				Range:   messages.NewRange(1, 0, 45, 0),
+				NewText: readTestFile(t, "formatted.yaml"),
+			}},
+		},
+		"formatted file": {
+			uri:      getTestFileURI("formatted.yaml"),
+			expected: []messages.TextEdit{},
+		},
+		"comment would be lost": {
+			uri: getTestFileURI("lost_comment.yaml"),
+		},
+		"syntax error": {
+			uri: getTestFileURI("invalid.yaml"),
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			result, err := handler.HandleFormatting(context.Background(), messages.DocumentFormattingParams{
+				TextDocument: messages.TextDocumentIdentifier{URI: tc.uri},
+			})
+			require.NoError(t, err)
+			if tc.expected == nil {
+				assert.Nil(t, result)
+				return
+			}
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestHandler_HandleRangeFormatting(t *testing.T) {
+	t.Parallel()
+
+	fileSystem := files.NewFS(nil)
+	testutils.RegisterTestFiles(t, fileSystem, testDir)
+	docs, err := sdkdocs.New()
+	require.NoError(t, err)
+	handler := NewHandler(fileSystem, docs)
+
+	formattedLines := strings.Split(readTestFile(t, "formatted.yaml"), "\n")
+
+	tests := map[string]struct {
+		uri      string
+		rng      messages.Range
+		expected any
+	}{
+		"single value": {
+			uri: getTestFileURI("unformatted.yaml"),
+			rng: messages.NewLineRange(11, 10, 12),
+			expected: []messages.TextEdit{{
+				Range:   messages.NewLineRange(11, 0, 35),
+				NewText: "        name: good # Good enough.",
+			}},
+		},
+		"smallest subtree containing the selection": {
+			uri: getTestFileURI("unformatted.yaml"),
+			rng: messages.NewRange(22, 5, 23, 10),
+			expected: []messages.TextEdit{{
+				Range: messages.NewRange(22, 0, 25, 51),
+				NewText: "                prometheus:\n" +
+					"                  promql: |\n" +
+					"                    sum(rate(\n" +
+					"                      http_requests_total[5m]))",
+			}},
+		},
+		"selection spanning multiple objects": {
+			uri: getTestFileURI("unformatted.yaml"),
+			rng: messages.NewRange(30, 0, 37, 0),
+			expected: []messages.TextEdit{
+				{
+					Range:   messages.NewRange(2, 0, 34, 21),
+					NewText: strings.Join(formattedLines[1:34], "\n"),
+				},
+				{
+					Range:   messages.NewRange(36, 0, 39, 24),
+					NewText: strings.Join(formattedLines[35:39], "\n"),
+				},
+			},
+		},
+		"formatted selection": {
+			uri:      getTestFileURI("formatted.yaml"),
+			rng:      messages.NewRange(2, 0, 20, 0),
+			expected: []messages.TextEdit{},
+		},
+		"syntax error": {
+			uri: getTestFileURI("invalid.yaml"),
+			rng: messages.NewRange(1, 0, 2, 0),
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			result, err := handler.HandleRangeFormatting(context.Background(), messages.DocumentRangeFormattingParams{
+				TextDocument: messages.TextDocumentIdentifier{URI: tc.uri},
+				Range:        tc.rng,
+			})
+			require.NoError(t, err)
+			if tc.expected == nil {
+				assert.Nil(t, result)
+				return
+			}
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestFormat(t *testing.T) {
+	t.Parallel()
+
+	docs, err := sdkdocs.New()
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		input    string
+		expected string
+	}{
+		"unknown kind keeps default root keys order": {
+			input:    "spec: {}\nmetadata:\n  name: foo\nkind: Foo\napiVersion: n9/v1alpha\n",
+			expected: "apiVersion: n9/v1alpha\nkind: Foo\nmetadata:\n  name: foo\nspec: {}\n",
+		},
+		"quotes are kept if needed": {
+			input: "apiVersion: n9/v1alpha\nkind: Project\nmetadata:\n" +
+				"  name: 'foo'\n  displayName: 'true'\n  labels:\n    'a': ['1', \"b: c\", 'd']\n",
+			expected: "apiVersion: n9/v1alpha\nkind: Project\nmetadata:\n" +
+				"  name: foo\n  displayName: \"true\"\n  labels:\n    a: [\"1\", \"b: c\", d]\n",
+		},
+		"block scalars are reindented": {
+			input: "apiVersion: n9/v1alpha\nkind: Project\nspec:\n" +
+				"      description: >-\n          foo\n\n           bar\n",
+			expected: "apiVersion: n9/v1alpha\nkind: Project\nspec:\n" +
+				"  description: >-\n    foo\n\n     bar\n",
+		},
+		"document separators": {
+			input:    "---\nkind: Project\n\n---\n\nkind: Service\n",
+			expected: "kind: Project\n---\nkind: Service\n",
+		},
+		"head and foot comments": {
+			input: "# Head.\nkind: Project\n# Before apiVersion.\napiVersion: n9/v1alpha\n" +
+				"metadata:\n  name: foo\n  # Foot.\n",
+			expected: "# Head.\n# Before apiVersion.\napiVersion: n9/v1alpha\nkind: Project\n" +
+				"metadata:\n  name: foo\n  # Foot.\n",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			formatted, err := Format(tc.input, docs)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, formatted)
+		})
+	}
+}
+
+func readTestFile(t *testing.T, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(getTestFileURI(name)) // #nosec G304
+	require.NoError(t, err)
+	return string(data)
+}
+
+func getTestFileURI(name string) string {
+	return filepath.Join(testDir, name)
+}
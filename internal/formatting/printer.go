@@ -0,0 +1,435 @@
+package formatting
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/token"
+	"github.com/nobl9/nobl9-go/manifest"
+	"github.com/pkg/errors"
+
+	"github.com/nobl9/nobl9-language-server/internal/sdkdocs"
+)
+
+// indentWidth is the number of spaces used for each indentation level.
+const indentWidth = 2
+
+// defaultRootKeysOrder is used for objects whose kind is unknown.
+var defaultRootKeysOrder = []string{"$.apiVersion", "$.kind", "$.metadata", "$.spec"}
+
+var errUnsupportedNode = errors.New("unsupported YAML node")
+
+type docsProvider interface {
+	GetProperty(kind manifest.Kind, path string) *sdkdocs.PropertyDoc
+}
+
+func newPrinter(docs docsProvider, content string) *printer {
+	return &printer{
+		docs:    docs,
+		lines:   strings.Split(content, "\n"),
+		printed: make(map[*ast.CommentGroupNode]bool),
+	}
+}
+
+// printer prints YAML AST in the canonical Nobl9 format.
+type printer struct {
+	docs docsProvider
+	// lines are the original content lines, used to preserve blank lines.
+	lines []string
+	// kind of the currently printed object.
+	kind manifest.Kind
+	// printed keeps track of comments which were already printed.
+	printed map[*ast.CommentGroupNode]bool
+	// lineRange if set, limits the printed comments to the ones located within these lines (inclusive, 1-based).
+	lineRange *[2]int
+	buf       strings.Builder
+}
+
+func (p *printer) String() string {
+	return p.buf.String()
+}
+
+// printObject prints a single Nobl9 object (document body).
+func (p *printer) printObject(node ast.Node, indent int) error {
+	switch v := node.(type) {
+	case nil:
+		return nil
+	case *ast.MappingNode:
+		if v.IsFlowStyle {
+			return errors.Wrap(errUnsupportedNode, "flow style object")
+		}
+		p.kind = inferKind(v)
+		values := p.orderedValues(v, "$")
+		// Keep the object's head comment at the top, regardless of the keys order.
+		if len(v.Values) > 0 {
+			p.printComment(v.Values[0].GetComment(), indent)
+		}
+		if err := p.printMappingValues(values, indent, "$", false); err != nil {
+			return err
+		}
+		p.printComment(v.FootComment, indent)
+		return nil
+	default:
+		return errors.Wrapf(errUnsupportedNode, "%T object", node)
+	}
+}
+
+// printListElementObject prints a single Nobl9 object which is an element of a list document.
+func (p *printer) printListElementObject(m *ast.MappingNode, indent int) error {
+	p.kind = inferKind(m)
+	return p.printSequenceItemValue(m, indent, "$")
+}
+
+// printListOfObjects prints a sequence of Nobl9 objects (list document).
+func (p *printer) printListOfObjects(seq *ast.SequenceNode, indent int) error {
+	if seq.IsFlowStyle {
+		return errors.Wrap(errUnsupportedNode, "flow style list of objects")
+	}
+	for i, item := range seq.Values {
+		m, ok := item.(*ast.MappingNode)
+		if !ok {
+			return errors.Wrapf(errUnsupportedNode, "%T list element", item)
+		}
+		p.kind = inferKind(m)
+		if err := p.printSequenceItem(seq, i, indent, "$"); err != nil {
+			return err
+		}
+	}
+	p.printComment(seq.FootComment, indent)
+	return nil
+}
+
+func (p *printer) printMappingValues(values []*ast.MappingValueNode, indent int, path string, inline bool) error {
+	for i, mv := range values {
+		if i > 0 || !inline {
+			if i > 0 && p.hasBlankLineBefore(mv) {
+				p.buf.WriteString("\n")
+			}
+			p.printComment(mv.GetComment(), indent)
+			p.writeIndent(indent)
+		}
+		if err := p.printMappingValue(mv, indent, path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *printer) printMappingValue(mv *ast.MappingValueNode, indent int, path string) error {
+	key, err := p.scalar(mv.Key)
+	if err != nil {
+		return errors.Wrap(err, "mapping key")
+	}
+	p.buf.WriteString(key)
+	p.buf.WriteString(":")
+	p.printLineComment(mv.Key.GetComment())
+	if err = p.printValue(mv.Value, indent, path+"."+mv.Key.GetToken().Value); err != nil {
+		return err
+	}
+	p.printComment(mv.FootComment, indent)
+	return nil
+}
+
+// printValue prints the value of a mapping or sequence item.
+// The key or sequence entry indicator is expected to be already written.
+func (p *printer) printValue(node ast.Node, indent int, path string) error {
+	switch v := node.(type) {
+	case nil:
+		p.buf.WriteString("\n")
+	case *ast.MappingNode:
+		if v.IsFlowStyle {
+			return p.printFlowValue(v, path)
+		}
+		p.printLineComment(v.GetComment())
+		p.buf.WriteString("\n")
+		if err := p.printMappingValues(p.orderedValues(v, path), indent+indentWidth, path, false); err != nil {
+			return err
+		}
+		p.printComment(v.FootComment, indent+indentWidth)
+	case *ast.SequenceNode:
+		if v.IsFlowStyle {
+			return p.printFlowValue(v, path)
+		}
+		p.buf.WriteString("\n")
+		return p.printSequence(v, indent+indentWidth, path+"[*]")
+	case *ast.LiteralNode:
+		return p.printLiteral(v, indent+indentWidth)
+	case *ast.AnchorNode:
+		p.buf.WriteString(" &" + v.Name.GetToken().Value)
+		return p.printValue(v.Value, indent, path)
+	case *ast.TagNode:
+		p.buf.WriteString(" " + v.Start.Value)
+		return p.printValue(v.Value, indent, path)
+	default:
+		value, err := p.scalar(node)
+		if err != nil {
+			return err
+		}
+		if value != "" {
+			p.buf.WriteString(" " + value)
+		}
+		p.printLineComment(node.GetComment())
+		p.buf.WriteString("\n")
+	}
+	return nil
+}
+
+func (p *printer) printFlowValue(node ast.Node, path string) error {
+	value, err := p.flow(node, path)
+	if err != nil {
+		return err
+	}
+	p.buf.WriteString(" " + value)
+	p.printLineComment(node.GetComment())
+	p.buf.WriteString("\n")
+	return nil
+}
+
+func (p *printer) printSequence(seq *ast.SequenceNode, indent int, itemPath string) error {
+	for i := range seq.Values {
+		if err := p.printSequenceItem(seq, i, indent, itemPath); err != nil {
+			return err
+		}
+	}
+	p.printComment(seq.FootComment, indent)
+	return nil
+}
+
+func (p *printer) printSequenceItem(seq *ast.SequenceNode, i, indent int, itemPath string) error {
+	item := seq.Values[i]
+	if i > 0 && p.hasBlankLineBefore(item) {
+		p.buf.WriteString("\n")
+	}
+	if i == 0 {
+		p.printComment(seq.GetComment(), indent)
+	}
+	if i < len(seq.ValueHeadComments) {
+		p.printComment(seq.ValueHeadComments[i], indent)
+	}
+	return p.printSequenceItemValue(item, indent, itemPath)
+}
+
+func (p *printer) printSequenceItemValue(item ast.Node, indent int, itemPath string) error {
+	m, isMapping := item.(*ast.MappingNode)
+	if !isMapping || m.IsFlowStyle || len(m.Values) == 0 {
+		p.writeIndent(indent)
+		p.buf.WriteString("-")
+		return p.printValue(item, indent, itemPath)
+	}
+	values := p.orderedValues(m, itemPath)
+	// The first key is printed in the same line as the sequence entry indicator,
+	// its head comment has to be printed before the indicator.
+	p.printComment(values[0].GetComment(), indent)
+	p.writeIndent(indent)
+	p.buf.WriteString("- ")
+	if err := p.printMappingValues(values, indent+indentWidth, itemPath, true); err != nil {
+		return err
+	}
+	p.printComment(m.FootComment, indent+indentWidth)
+	return nil
+}
+
+// printLiteral prints block scalar, its content is re-indented based on the original lines.
+func (p *printer) printLiteral(node *ast.LiteralNode, indent int) error {
+	header := node.Start.Value
+	if strings.ContainsAny(header, "123456789") {
+		return errors.Wrap(errUnsupportedNode, "block scalar with indentation indicator")
+	}
+	p.buf.WriteString(" " + header)
+	p.printLineComment(node.GetComment())
+	p.buf.WriteString("\n")
+
+	lines := strings.Split(node.Value.GetToken().Origin, "\n")
+	// The last line holds either nothing or the indentation of the following node.
+	lines = lines[:len(lines)-1]
+	if !strings.HasSuffix(header, "+") {
+		for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+			lines = lines[:len(lines)-1]
+		}
+	}
+	contentIndent := -1
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		lineIndent := len(line) - len(strings.TrimLeft(line, " "))
+		if contentIndent == -1 || lineIndent < contentIndent {
+			contentIndent = lineIndent
+		}
+	}
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			p.buf.WriteString("\n")
+			continue
+		}
+		p.writeIndent(indent)
+		p.buf.WriteString(line[contentIndent:])
+		p.buf.WriteString("\n")
+	}
+	return nil
+}
+
+// flow returns the flow style representation of the node.
+func (p *printer) flow(node ast.Node, path string) (string, error) {
+	switch v := node.(type) {
+	case *ast.MappingNode:
+		values := p.orderedValues(v, path)
+		entries := make([]string, 0, len(values))
+		for _, mv := range values {
+			key, err := p.scalar(mv.Key)
+			if err != nil {
+				return "", err
+			}
+			value, err := p.flow(mv.Value, path+"."+mv.Key.GetToken().Value)
+			if err != nil {
+				return "", err
+			}
+			entries = append(entries, key+": "+value)
+		}
+		return "{" + strings.Join(entries, ", ") + "}", nil
+	case *ast.SequenceNode:
+		entries := make([]string, 0, len(v.Values))
+		for _, item := range v.Values {
+			value, err := p.flow(item, path+"[*]")
+			if err != nil {
+				return "", err
+			}
+			entries = append(entries, value)
+		}
+		return "[" + strings.Join(entries, ", ") + "]", nil
+	case *ast.AnchorNode:
+		value, err := p.flow(v.Value, path)
+		return "&" + v.Name.GetToken().Value + " " + value, err
+	case *ast.TagNode:
+		value, err := p.flow(v.Value, path)
+		return v.Start.Value + " " + value, err
+	default:
+		return p.scalar(node)
+	}
+}
+
+// scalar returns the text representation of a scalar node.
+// Quoted strings are only quoted if needed, always with double quotes.
+func (p *printer) scalar(node ast.Node) (string, error) {
+	switch v := node.(type) {
+	case *ast.StringNode:
+		switch v.GetToken().Type {
+		case token.DoubleQuoteType, token.SingleQuoteType:
+			if needsQuotes(v.Value) {
+				return strconv.Quote(v.Value), nil
+			}
+			return v.Value, nil
+		default:
+			return v.Value, nil
+		}
+	case *ast.IntegerNode, *ast.FloatNode, *ast.BoolNode, *ast.NullNode,
+		*ast.InfinityNode, *ast.NanNode, *ast.MergeKeyNode:
+		return v.GetToken().Value, nil
+	case *ast.AliasNode:
+		return "*" + v.Value.GetToken().Value, nil
+	default:
+		return "", errors.Wrapf(errUnsupportedNode, "%T scalar", node)
+	}
+}
+
+// orderedValues returns mapping values sorted in the order defined by the SDK docs.
+// Keys which are not documented are placed at the end, in their original order.
+func (p *printer) orderedValues(m *ast.MappingNode, path string) []*ast.MappingValueNode {
+	var childrenPaths []string
+	if prop := p.docs.GetProperty(p.kind, path); prop != nil {
+		childrenPaths = prop.ChildrenPaths
+	} else if path == "$" {
+		childrenPaths = defaultRootKeysOrder
+	}
+	getIndex := func(mv *ast.MappingValueNode) int {
+		if i := slices.Index(childrenPaths, path+"."+mv.Key.GetToken().Value); i != -1 {
+			return i
+		}
+		return len(childrenPaths)
+	}
+	values := slices.Clone(m.Values)
+	slices.SortStableFunc(values, func(mv1, mv2 *ast.MappingValueNode) int {
+		return cmp.Compare(getIndex(mv1), getIndex(mv2))
+	})
+	return values
+}
+
+func (p *printer) printComment(comment *ast.CommentGroupNode, indent int) {
+	if !p.shouldPrintComment(comment) {
+		return
+	}
+	p.printed[comment] = true
+	for _, line := range strings.Split(comment.String(), "\n") {
+		p.writeIndent(indent)
+		p.buf.WriteString(strings.TrimSpace(line))
+		p.buf.WriteString("\n")
+	}
+}
+
+func (p *printer) printLineComment(comment *ast.CommentGroupNode) {
+	if !p.shouldPrintComment(comment) {
+		return
+	}
+	p.printed[comment] = true
+	p.buf.WriteString(" ")
+	p.buf.WriteString(strings.TrimSpace(comment.String()))
+}
+
+func (p *printer) shouldPrintComment(comment *ast.CommentGroupNode) bool {
+	if comment == nil || p.printed[comment] {
+		return false
+	}
+	if p.lineRange == nil || len(comment.Comments) == 0 {
+		return true
+	}
+	line := comment.Comments[0].GetToken().Position.Line
+	return line >= p.lineRange[0] && line <= p.lineRange[1]
+}
+
+// hasBlankLineBefore checks if the node (along with its head comment) was preceded by a blank line.
+func (p *printer) hasBlankLineBefore(node ast.Node) bool {
+	tk := node.GetToken()
+	if mv, ok := node.(*ast.MappingValueNode); ok {
+		tk = mv.Key.GetToken()
+	}
+	if tk == nil || tk.Position == nil {
+		return false
+	}
+	line := tk.Position.Line
+	if comment := node.GetComment(); comment != nil && len(comment.Comments) > 0 {
+		line = min(line, comment.Comments[0].GetToken().Position.Line)
+	}
+	// Lines are 1-based.
+	idx := line - 2
+	return idx >= 0 && idx < len(p.lines) && strings.TrimSpace(p.lines[idx]) == ""
+}
+
+func (p *printer) writeIndent(indent int) {
+	p.buf.WriteString(strings.Repeat(" ", indent))
+}
+
+func inferKind(m *ast.MappingNode) manifest.Kind {
+	for _, mv := range m.Values {
+		if mv.Key.GetToken().Value != "kind" {
+			continue
+		}
+		kind, err := manifest.ParseKind(mv.Value.GetToken().Value)
+		if err != nil {
+			return 0
+		}
+		return kind
+	}
+	return 0
+}
+
+// needsQuotes checks if the string value has to be quoted in order to preserve its value and type.
+func needsQuotes(value string) bool {
+	return token.IsNeedQuoted(value) ||
+		strings.HasPrefix(value, "?") ||
+		strings.IndexFunc(value, unicode.IsControl) != -1 ||
+		strings.IndexFunc(value, func(r rune) bool { return !unicode.IsPrint(r) }) != -1
+}
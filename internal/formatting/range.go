@@ -0,0 +1,250 @@
+package formatting
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/token"
+	"github.com/pkg/errors"
+
+	"github.com/nobl9/nobl9-language-server/internal/files"
+	"github.com/nobl9/nobl9-language-server/internal/messages"
+)
+
+// FormatRange formats the objects or mapping subtrees which overlap with the selected range.
+// If the selection is contained within a single object, the smallest mapping subtree
+// which contains the whole selection is formatted.
+// Otherwise, every object overlapping with the selection is formatted.
+//
+// Only the lines spanned by the formatted nodes are changed,
+// comments preceding or following them are left untouched.
+func FormatRange(file *files.File, docs docsProvider, selection messages.Range) ([]messages.TextEdit, error) {
+	// Lines are 1-based.
+	start, end := selection.Start.Line+1, selection.End.Line+1
+	// Selection which ends at the beginning of a line does not include that line.
+	if selection.End.Character == 0 && end > start {
+		end--
+	}
+	f := &rangeFormatter{
+		file:  file,
+		docs:  docs,
+		lines: strings.Split(file.Content, "\n"),
+	}
+	if object := file.FindObject(start); object != nil && object == file.FindObject(end) {
+		if subtree := f.findSubtree(object, start, end); subtree != nil {
+			edit, err := f.format(subtree)
+			if err != nil {
+				return nil, err
+			}
+			return toEdits(edit), nil
+		}
+	}
+	edits := make([]messages.TextEdit, 0)
+	for _, object := range file.Objects {
+		if object.Node.StartLine > end || object.Node.EndLine < start {
+			continue
+		}
+		edit, err := f.format(f.newObjectSubtree(object))
+		if err != nil {
+			return nil, err
+		}
+		edits = append(edits, toEdits(edit)...)
+	}
+	return edits, nil
+}
+
+type rangeFormatter struct {
+	file  *files.File
+	docs  docsProvider
+	lines []string
+}
+
+// subtree is a part of the file which is formatted.
+type subtree struct {
+	// object is the [ast.MappingNode] of the whole object.
+	object *ast.MappingNode
+	// value is set if only a single mapping value of the object is formatted.
+	value *ast.MappingValueNode
+	// path is the generalized path of the mapping which holds the value.
+	path   string
+	indent int
+	// isListElement is true if the whole object is an element of a list document.
+	isListElement bool
+	// from and to are the formatted lines (inclusive, 1-based).
+	from, to int
+}
+
+// format formats the [subtree] and returns a [messages.TextEdit] replacing its lines.
+// If the formatted subtree does not differ from the original, nil is returned.
+func (f *rangeFormatter) format(s *subtree) (*messages.TextEdit, error) {
+	if s == nil {
+		return nil, nil
+	}
+	p := newPrinter(f.docs, f.file.Content)
+	p.lineRange = &[2]int{s.from, s.to}
+	var err error
+	switch {
+	case s.value != nil:
+		p.kind = inferKind(s.object)
+		err = p.printMappingValues([]*ast.MappingValueNode{s.value}, s.indent, s.path, false)
+	case s.isListElement:
+		err = p.printListElementObject(s.object, s.indent)
+	default:
+		err = p.printObject(s.object, s.indent)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	original := strings.Join(f.lines[s.from-1:s.to], "\n")
+	formatted := strings.TrimSuffix(p.String(), "\n")
+	if err = verifyFormatting(original, formatted); err != nil {
+		return nil, errors.Wrapf(err, "failed to format lines %d-%d", s.from, s.to)
+	}
+	if original == formatted {
+		return nil, nil
+	}
+	return &messages.TextEdit{
+		Range: messages.Range{
+			Start: messages.Position{Line: s.from - 1},
+			End: messages.Position{
+				Line:      s.to - 1,
+				Character: utf8.RuneCountInString(f.lines[s.to-1]),
+			},
+		},
+		NewText: formatted,
+	}, nil
+}
+
+func (f *rangeFormatter) newObjectSubtree(object *files.ObjectNode) *subtree {
+	m, ok := object.Node.Node.(*ast.MappingNode)
+	if !ok || m.IsFlowStyle || len(m.Values) == 0 {
+		return nil
+	}
+	from := object.Node.StartLine
+	indent, isFirst := f.getIndent(m.Values[0].Key.GetToken())
+	s := &subtree{
+		object: m,
+		indent: indent,
+		from:   from,
+		to:     f.trimTrailingLines(from, object.Node.EndLine),
+	}
+	if !isFirst {
+		// The object starts after the sequence entry indicator.
+		s.isListElement = true
+		s.indent = len(f.lines[from-1]) - len(strings.TrimLeft(f.lines[from-1], " "))
+	}
+	return s
+}
+
+// findSubtree returns the smallest mapping value subtree of the object which contains the selected lines.
+func (f *rangeFormatter) findSubtree(object *files.ObjectNode, start, end int) *subtree {
+	root := f.newObjectSubtree(object)
+	if root == nil {
+		return nil
+	}
+	var result *subtree
+	var walk func(m *ast.MappingNode, path string, bound int)
+	walk = func(m *ast.MappingNode, path string, bound int) {
+		for i, mv := range m.Values {
+			from := mv.Key.GetToken().Position.Line
+			to := bound
+			if i+1 < len(m.Values) {
+				to = getStartLine(m.Values[i+1]) - 1
+			}
+			to = f.trimTrailingLines(from, to)
+			if from > start || to < end {
+				continue
+			}
+			indent, isFirst := f.getIndent(mv.Key.GetToken())
+			if !isFirst {
+				return
+			}
+			result = &subtree{
+				object: root.object,
+				value:  mv,
+				path:   path,
+				indent: indent,
+				from:   from,
+				to:     to,
+			}
+			f.walkValue(mv.Value, path+"."+mv.Key.GetToken().Value, to, walk)
+			return
+		}
+	}
+	walk(root.object, "$", root.to)
+	return result
+}
+
+// walkValue calls walk for every block mapping which is either the value itself or an element of the sequence.
+func (f *rangeFormatter) walkValue(
+	node ast.Node,
+	path string,
+	bound int,
+	walk func(m *ast.MappingNode, path string, bound int),
+) {
+	switch v := node.(type) {
+	case *ast.MappingNode:
+		if !v.IsFlowStyle {
+			walk(v, path, bound)
+		}
+	case *ast.SequenceNode:
+		if v.IsFlowStyle {
+			return
+		}
+		for i, item := range v.Values {
+			itemBound := bound
+			if i+1 < len(v.Values) {
+				itemBound = v.Values[i+1].GetToken().Position.Line - 1
+				if i+1 < len(v.ValueHeadComments) && v.ValueHeadComments[i+1] != nil {
+					itemBound = getCommentLine(v.ValueHeadComments[i+1]) - 1
+				}
+			}
+			f.walkValue(item, path+"[*]", itemBound, walk)
+		}
+	}
+}
+
+// getIndent returns the indentation of the token's line and whether the token is the first one in that line.
+func (f *rangeFormatter) getIndent(tk *token.Token) (int, bool) {
+	line := []rune(f.lines[tk.Position.Line-1])
+	column := min(tk.Position.Column-1, len(line))
+	return column, strings.TrimSpace(string(line[:column])) == ""
+}
+
+// trimTrailingLines moves the last line up, skipping blank and comment lines.
+func (f *rangeFormatter) trimTrailingLines(from, to int) int {
+	to = min(to, len(f.lines))
+	for to > from {
+		line := strings.TrimSpace(f.lines[to-1])
+		if line != "" && !strings.HasPrefix(line, "#") && line != "---" {
+			break
+		}
+		to--
+	}
+	return to
+}
+
+// getStartLine returns the first line of the mapping value, including its head comment.
+func getStartLine(mv *ast.MappingValueNode) int {
+	line := mv.Key.GetToken().Position.Line
+	if comment := mv.GetComment(); comment != nil {
+		line = min(line, getCommentLine(comment))
+	}
+	return line
+}
+
+func getCommentLine(comment *ast.CommentGroupNode) int {
+	if len(comment.Comments) == 0 {
+		return 0
+	}
+	return comment.Comments[0].GetToken().Position.Line
+}
+
+func toEdits(edit *messages.TextEdit) []messages.TextEdit {
+	if edit == nil {
+		return []messages.TextEdit{}
+	}
+	return []messages.TextEdit{*edit}
+}
@@ -18,16 +18,22 @@ type providerInterface interface {
 	) *messages.HoverResponse
 }
 
-func NewHandler(files *files.FS, provider providerInterface) *Handler {
+type clientCapabilities interface {
+	Get() messages.ClientCapabilities
+}
+
+func NewHandler(files *files.FS, provider providerInterface, capabilities clientCapabilities) *Handler {
 	return &Handler{
-		files:    files,
-		provider: provider,
+		files:        files,
+		provider:     provider,
+		capabilities: capabilities,
 	}
 }
 
 type Handler struct {
-	files    *files.FS
-	provider providerInterface
+	files        *files.FS
+	provider     providerInterface
+	capabilities clientCapabilities
 }
 
 func (h *Handler) Handle(ctx context.Context, params messages.HoverParams) (any, error) {
@@ -40,6 +46,7 @@ func (h *Handler) Handle(ctx context.Context, params messages.HoverParams) (any,
 		slog.DebugContext(ctx, "skipping file")
 		return nil, nil
 	}
+	params.Position = file.GetPositionMapper().FromClientPosition(params.Position)
 
 	var (
 		node *files.SimpleObjectNode
@@ -55,5 +62,18 @@ func (h *Handler) Handle(ctx context.Context, params messages.HoverParams) (any,
 		slog.ErrorContext(ctx, "no document found", slog.Any("line", params.Position.Line))
 		return nil, nil
 	}
-	return h.provider.Hover(ctx, params, node, line), nil
+	hover := h.provider.Hover(ctx, params, node, line)
+	// The hover could have been generated from partial data if the request was cancelled.
+	if err = ctx.Err(); err != nil {
+		return nil, err
+	}
+	if hover != nil && h.capabilities.Get().GetHoverContentFormat() == messages.PlainText {
+		if content, ok := hover.Contents.(messages.MarkupContent); ok && content.Kind == messages.Markdown {
+			hover.Contents = messages.MarkupContent{
+				Kind:  messages.PlainText,
+				Value: markdownToPlainText(content.Value),
+			}
+		}
+	}
+	return hover, nil
 }
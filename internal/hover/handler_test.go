@@ -4,6 +4,7 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/nobl9/nobl9-go/manifest"
@@ -14,12 +15,14 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/nobl9/nobl9-language-server/internal/capabilities"
 	"github.com/nobl9/nobl9-language-server/internal/files"
 	"github.com/nobl9/nobl9-language-server/internal/messages"
 	"github.com/nobl9/nobl9-language-server/internal/nobl9repo"
 	"github.com/nobl9/nobl9-language-server/internal/objectref"
 	"github.com/nobl9/nobl9-language-server/internal/sdkdocs"
 	"github.com/nobl9/nobl9-language-server/internal/testutils"
+	"github.com/nobl9/nobl9-language-server/internal/workspace"
 )
 
 var (
@@ -45,10 +48,8 @@ func TestHandler_Handle(t *testing.T) {
 		names: []string{"foo", "bar"},
 	}
 
-	handler := &Handler{
-		files:    fileSystem,
-		provider: NewProvider(docs, repo),
-	}
+	provider := NewProvider(docs, repo, workspace.NewObjectsResolver(files.NewFS(nil), repo, noFileScopes{}))
+	handler := NewHandler(fileSystem, provider, newCapabilitiesStore(messages.Markdown))
 
 	tests := map[string]handlerTestCase{
 		"service - apiVersion key": {
@@ -204,6 +205,73 @@ func TestHandler_Handle(t *testing.T) {
 	}
 }
 
+func TestHandler_Handle_PlainText(t *testing.T) {
+	t.Parallel()
+
+	fileSystem := files.NewFS(nil)
+	testutils.RegisterTestFiles(t, fileSystem, inputsDir)
+
+	docs, err := sdkdocs.New()
+	require.NoError(t, err)
+	resolver := workspace.NewObjectsResolver(files.NewFS(nil), mockObjectsRepo{}, noFileScopes{})
+	provider := NewProvider(docs, mockObjectsRepo{}, resolver)
+	handler := NewHandler(fileSystem, provider, newCapabilitiesStore(messages.PlainText))
+
+	result, err := handler.Handle(context.Background(), messages.HoverParams{
+		TextDocumentPositionParams: messages.TextDocumentPositionParams{
+			TextDocument: getTestFileURI("service.yaml"),
+			Position:     messages.Position{Line: 4, Character: 6},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &messages.HoverResponse{
+		Contents: messages.MarkupContent{
+			Kind:  messages.PlainText,
+			Value: mustReadFile(t, "metadata-project-key.txt"),
+		},
+	}, result)
+}
+
+func TestHandler_Handle_WorkspaceObject(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	fileSystem := files.NewFS(nil)
+	_, err := fileSystem.IndexFile(ctx, "file:///project.yaml",
+		"apiVersion: n9/v1alpha\nkind: Project\nmetadata:\n  name: local\nspec:\n  description: Not applied yet\n")
+	require.NoError(t, err)
+	require.NoError(t, fileSystem.OpenFile(ctx, "file:///service.yaml",
+		"apiVersion: n9/v1alpha\nkind: Service\nmetadata:\n  name: api\n  project: local\n", 1))
+
+	docs, err := sdkdocs.New()
+	require.NoError(t, err)
+	resolver := workspace.NewObjectsResolver(fileSystem, mockObjectsRepo{}, noFileScopes{})
+	provider := NewProvider(docs, mockObjectsRepo{}, resolver)
+	handler := NewHandler(fileSystem, provider, newCapabilitiesStore(messages.Markdown))
+
+	result, err := handler.Handle(ctx, messages.HoverParams{
+		TextDocumentPositionParams: messages.TextDocumentPositionParams{
+			TextDocument: messages.TextDocumentIdentifier{URI: "file:///service.yaml"},
+			Position:     messages.Position{Line: 4, Character: 13},
+		},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	contents := result.(*messages.HoverResponse).Contents.(messages.MarkupContent).Value
+	assert.True(t, strings.HasPrefix(contents,
+		"`local` Project\n\nNot applied yet\n\nDefined in the workspace: `/project.yaml`"))
+}
+
+func newCapabilitiesStore(contentFormat ...messages.MarkupKind) *capabilities.Store {
+	store := capabilities.NewStore()
+	store.Set(messages.ClientCapabilities{
+		TextDocument: &messages.TextDocumentClientCapabilities{
+			Hover: &messages.HoverClientCapabilities{ContentFormat: contentFormat},
+		},
+	})
+	return store
+}
+
 func mustReadFile(t *testing.T, name string) string {
 	t.Helper()
 	data, err := os.ReadFile(filepath.Join(outputsDir, name))
@@ -215,6 +283,13 @@ func getTestFileURI(name string) messages.TextDocumentIdentifier {
 	return messages.TextDocumentIdentifier{URI: filepath.Join(inputsDir, name)}
 }
 
+// noFileScopes binds no [nobl9repo.Scope], all the workspace files share the same one.
+type noFileScopes struct{}
+
+func (noFileScopes) WithFileScope(ctx context.Context, _ files.URI) context.Context { return ctx }
+
+func (noFileScopes) Generation() uint64 { return 0 }
+
 type mockObjectsRepo struct {
 	names []string
 }
@@ -288,7 +363,7 @@ func (m mockObjectsRepo) GetObject(
 	}
 }
 
-func (m mockObjectsRepo) GetDefaultProject() string {
+func (m mockObjectsRepo) GetDefaultProject(context.Context) string {
 	return "default"
 }
 
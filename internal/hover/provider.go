@@ -15,6 +15,7 @@ import (
 	"github.com/nobl9/nobl9-language-server/internal/nobl9repo"
 	"github.com/nobl9/nobl9-language-server/internal/objectref"
 	"github.com/nobl9/nobl9-language-server/internal/sdkdocs"
+	"github.com/nobl9/nobl9-language-server/internal/workspace"
 	"github.com/nobl9/nobl9-language-server/internal/yamlastsimple"
 )
 
@@ -23,20 +24,26 @@ type docsProvider interface {
 }
 
 type objectsRepo interface {
-	GetObject(ctx context.Context, kind manifest.Kind, name, project string) (manifest.Object, error)
 	GetUser(ctx context.Context, id string) (*nobl9repo.User, error)
 }
 
-func NewProvider(docs docsProvider, repo objectsRepo) *Provider {
+// objectsResolver looks up the objects in the workspace first and then in the Nobl9 API.
+type objectsResolver interface {
+	ResolveObject(ctx context.Context, kind manifest.Kind, name, project string) (*workspace.ResolvedObject, error)
+}
+
+func NewProvider(docs docsProvider, repo objectsRepo, resolver objectsResolver) *Provider {
 	return &Provider{
-		docs: docs,
-		repo: repo,
+		docs:     docs,
+		repo:     repo,
+		resolver: resolver,
 	}
 }
 
 type Provider struct {
-	docs docsProvider
-	repo objectsRepo
+	docs     docsProvider
+	repo     objectsRepo
+	resolver objectsResolver
 }
 
 func (p Provider) Hover(
@@ -112,7 +119,7 @@ func (p Provider) generateObjectDocs(
 		return ""
 	}
 	objectName := line.GetMapValue()
-	object, err := p.repo.GetObject(ctx, ref.Kind, objectName, projectName)
+	object, err := p.resolver.ResolveObject(ctx, ref.Kind, objectName, projectName)
 	if err != nil {
 		slog.ErrorContext(ctx, "failed to get object",
 			slog.String("kind", ref.Kind.String()),
@@ -174,8 +181,8 @@ func (p Provider) generateUserDocs(
 	return b.String()
 }
 
-func (p Provider) buildObjectDocs(ctx context.Context, object manifest.Object) string {
-	objectYAML, err := yaml.Marshal(object)
+func (p Provider) buildObjectDocs(ctx context.Context, object *workspace.ResolvedObject) string {
+	objectYAML, err := yaml.Marshal(object.Object)
 	if err != nil {
 		slog.ErrorContext(ctx, "failed to encode object to YAML format",
 			slog.String("kind", object.GetKind().String()),
@@ -191,6 +198,7 @@ func (p Provider) buildObjectDocs(ctx context.Context, object manifest.Object) s
 		Kind:        object.GetKind(),
 		Name:        object.GetName(),
 		Description: findObjectDescription(ctx, objectYAMLStr),
+		Definition:  getObjectDefinitionPath(object),
 		YAML:        objectYAMLStr,
 	}); err != nil {
 		slog.ErrorContext(ctx, "failed to execute object doc template",
@@ -202,6 +210,19 @@ func (p Provider) buildObjectDocs(ctx context.Context, object manifest.Object) s
 	return b.String()
 }
 
+// getObjectDefinitionPath returns the path of the workspace file defining the object.
+// It returns an empty string if the object was fetched from the Nobl9 API.
+func getObjectDefinitionPath(object *workspace.ResolvedObject) string {
+	if !object.IsLocal() {
+		return ""
+	}
+	path, err := files.FilePathFromURI(object.Location.URI)
+	if err != nil {
+		return object.Location.URI
+	}
+	return path
+}
+
 func findObjectDescription(ctx context.Context, rawObject string) string {
 	file, err := files.ParseSimpleObjectFile(rawObject)
 	if err != nil {
@@ -23,7 +23,9 @@ type objectDocData struct {
 	Kind        manifest.Kind
 	Name        string
 	Description string
-	YAML        string
+	// Definition is the path of the workspace file defining the object, if any.
+	Definition string
+	YAML       string
 }
 
 //go:embed templates/property-doc.tpl.md
@@ -63,6 +65,33 @@ func markdownEscape(s string) string {
 	return markdownReplacer.Replace(s)
 }
 
+// markdownToPlainText strips the markdown syntax used by the docs templates,
+// for the clients which can only display plain text.
+// Code blocks' content is left untouched, only their fences are removed.
+func markdownToPlainText(s string) string {
+	lines := strings.Split(s, "\n")
+	plain := make([]string, 0, len(lines))
+	inCodeBlock := false
+	for _, line := range lines {
+		if strings.HasPrefix(line, "```") {
+			inCodeBlock = !inCodeBlock
+			continue
+		}
+		if !inCodeBlock {
+			line = strings.ReplaceAll(line, "**", "")
+			line = strings.ReplaceAll(line, "`", "")
+			line = markdownUnescape(line)
+		}
+		plain = append(plain, line)
+	}
+	return strings.Join(plain, "\n")
+}
+
+// markdownUnescape reverts [markdownEscape].
+func markdownUnescape(s string) string {
+	return markdownUnescaper.Replace(s)
+}
+
 // Based on: https://github.com/mattcone/markdown-guide/blob/master/_basic-syntax/escaping-characters.md
 const markdownSpecialCharacters = "\\`*_{}[]<>()#+-.!|"
 
@@ -73,3 +102,11 @@ var markdownReplacer = func() *strings.Replacer {
 	}
 	return strings.NewReplacer(replacements...)
 }()
+
+var markdownUnescaper = func() *strings.Replacer {
+	var replacements []string
+	for _, c := range markdownSpecialCharacters {
+		replacements = append(replacements, `\`+string(c), string(c))
+	}
+	return strings.NewReplacer(replacements...)
+}()
@@ -0,0 +1,3 @@
+// Package inlayhints provides inlay hints which reveal implicit values of Nobl9 objects,
+// like projects inherited by references or SLO objectives' error budgets.
+package inlayhints
@@ -0,0 +1,85 @@
+package inlayhints
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"github.com/nobl9/nobl9-language-server/internal/files"
+	"github.com/nobl9/nobl9-language-server/internal/messages"
+	"github.com/nobl9/nobl9-language-server/internal/nobl9repo"
+	"github.com/nobl9/nobl9-language-server/internal/workspace"
+)
+
+type objectsRepo interface {
+	GetDefaultProject(ctx context.Context) string
+	GetUser(ctx context.Context, id string) (*nobl9repo.User, error)
+}
+
+func NewHandler(files *files.FS, repo objectsRepo) *Handler {
+	return &Handler{
+		files: files,
+		repo:  repo,
+	}
+}
+
+type Handler struct {
+	files *files.FS
+	repo  objectsRepo
+}
+
+// Handle returns inlay hints for the values located within the requested range.
+func (h *Handler) Handle(ctx context.Context, params messages.InlayHintParams) (any, error) {
+	file, err := h.files.GetFile(params.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+	ctx = file.AddToLogContext(ctx)
+	if file.Skip {
+		slog.DebugContext(ctx, "skipping file")
+		return nil, nil
+	}
+
+	mapper := file.GetPositionMapper()
+	lines := strings.Split(file.Content, "\n")
+	hints := make([]messages.InlayHint, 0)
+	for _, object := range workspace.NewSnapshot([]*files.File{file}).FileObjects(file.URI) {
+		for _, value := range object.Values {
+			if value.Range.Start.Line < params.Range.Start.Line || value.Range.End.Line > params.Range.End.Line {
+				continue
+			}
+			label := h.getLabel(ctx, object, value)
+			if label == "" {
+				continue
+			}
+			hints = append(hints, messages.InlayHint{
+				Position:    mapper.ToClientPosition(getHintPosition(lines, value.Range.End)),
+				Label:       label,
+				PaddingLeft: true,
+			})
+		}
+	}
+	return hints, nil
+}
+
+func (h *Handler) getLabel(ctx context.Context, object *workspace.Object, value *workspace.Value) string {
+	if label := getUserLabel(ctx, h.repo, object, value); label != "" {
+		return label
+	}
+	if label := getInheritedProjectLabel(ctx, h.repo, object, value); label != "" {
+		return label
+	}
+	return getErrorBudgetLabel(object, value)
+}
+
+// getHintPosition returns the position right after the value, including its closing quote.
+func getHintPosition(lines []string, end messages.Position) messages.Position {
+	if end.Line >= len(lines) {
+		return end
+	}
+	line := []rune(lines[end.Line])
+	if end.Character < len(line) && (line[end.Character] == '"' || line[end.Character] == '\'') {
+		end.Character++
+	}
+	return end
+}
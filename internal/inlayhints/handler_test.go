@@ -0,0 +1,102 @@
+package inlayhints
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nobl9/nobl9-language-server/internal/files"
+	"github.com/nobl9/nobl9-language-server/internal/messages"
+	"github.com/nobl9/nobl9-language-server/internal/nobl9repo"
+	"github.com/nobl9/nobl9-language-server/internal/testutils"
+)
+
+var testDir = filepath.Join(testutils.FindModuleRoot(), "internal", "inlayhints", "testdata")
+
+func TestHandler_Handle(t *testing.T) {
+	t.Parallel()
+
+	fileSystem := files.NewFS(nil)
+	testutils.RegisterTestFiles(t, fileSystem, testDir)
+	handler := NewHandler(fileSystem, mockObjectsRepo{})
+
+	tests := map[string]struct {
+		rng      messages.Range
+		expected []messages.InlayHint
+	}{
+		"whole file": {
+			rng: messages.NewRange(1, 0, 68, 0),
+			expected: []messages.InlayHint{
+				newInlayHint(7, 23, "(project: team-a)"),
+				newInlayHint(9, 15, "(project: team-a)"),
+				newInlayHint(16, 18, "error budget: 1d 9h 36m (5%)"),
+				newInlayHint(18, 19, "error budget: 40m 19s (0.1%)"),
+				newInlayHint(28, 19, "(project: team-a)"),
+				newInlayHint(38, 21, "(project: default)"),
+				newInlayHint(47, 18, "error budget: 1%"),
+				newInlayHint(54, 28, "Foo Bar"),
+				newInlayHint(64, 30, "Foo Bar"),
+			},
+		},
+		"partial range": {
+			rng: messages.NewRange(14, 0, 17, 0),
+			expected: []messages.InlayHint{
+				newInlayHint(16, 18, "error budget: 1d 9h 36m (5%)"),
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			result, err := handler.Handle(context.Background(), messages.InlayHintParams{
+				TextDocument: messages.TextDocumentIdentifier{URI: getTestFileURI("objects.yaml")},
+				Range:        tc.rng,
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "0s", formatDuration(0))
+	assert.Equal(t, "1d 2h", formatDuration(26*60*60*1e9))
+	assert.Equal(t, "1m 1s", formatDuration(60_600*1e6))
+}
+
+type mockObjectsRepo struct{}
+
+func (m mockObjectsRepo) GetDefaultProject(context.Context) string {
+	return "default"
+}
+
+func (m mockObjectsRepo) GetUser(_ context.Context, id string) (*nobl9repo.User, error) {
+	if id == "unknown" {
+		return nil, nil
+	}
+	return &nobl9repo.User{
+		UserID:    id,
+		FirstName: "Foo",
+		LastName:  "Bar",
+		Email:     "foo@bar.com",
+	}, nil
+}
+
+// newInlayHint creates a [messages.InlayHint] with 1-based line number.
+func newInlayHint(line, character int, label string) messages.InlayHint {
+	return messages.InlayHint{
+		Position:    messages.Position{Line: line - 1, Character: character},
+		Label:       label,
+		PaddingLeft: true,
+	}
+}
+
+func getTestFileURI(name string) string {
+	return filepath.Join(testDir, name)
+}
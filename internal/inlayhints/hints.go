@@ -0,0 +1,137 @@
+package inlayhints
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nobl9/nobl9-go/manifest"
+
+	"github.com/nobl9/nobl9-language-server/internal/objectref"
+	"github.com/nobl9/nobl9-language-server/internal/workspace"
+	"github.com/nobl9/nobl9-language-server/internal/yamlastsimple"
+)
+
+const projectPath = "$.metadata.project"
+
+// getInheritedProjectLabel returns the project of the referenced object
+// if it's not explicitly defined next to the reference.
+// Example: SLO's alert policies are always in the same project as the SLO.
+func getInheritedProjectLabel(
+	ctx context.Context,
+	repo objectsRepo,
+	object *workspace.Object,
+	value *workspace.Value,
+) string {
+	ref := workspace.ResolveReference(object, value)
+	if ref == nil || ref.Objective != "" || !objectref.IsProjectScoped(ref.Target.Kind) {
+		return ""
+	}
+	objRef := objectref.Get(object.ID.Kind, &yamlastsimple.Line{
+		Path:            value.Path,
+		GeneralizedPath: value.GeneralizedPath,
+	})
+	if objRef == nil || objRef.ProjectPath == "" {
+		return ""
+	}
+	// Project is defined explicitly for this reference.
+	if objRef.ProjectPath != projectPath && object.GetValueString(objRef.ProjectPath) != "" {
+		return ""
+	}
+	project := ref.Target.Project
+	if project == "" {
+		project = repo.GetDefaultProject(ctx)
+	}
+	if project == "" {
+		return ""
+	}
+	return "(project: " + project + ")"
+}
+
+// getUserLabel returns the full name of the user referenced by the user ID.
+func getUserLabel(ctx context.Context, repo objectsRepo, object *workspace.Object, value *workspace.Value) string {
+	switch {
+	case object.ID.Kind == manifest.KindRoleBinding && value.Path == "$.spec.user",
+		object.ID.Kind == manifest.KindUserGroup && value.GeneralizedPath == "$.spec.members[*].id":
+	default:
+		return ""
+	}
+	user, err := repo.GetUser(ctx, value.Value)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get user",
+			slog.String("userID", value.Value),
+			slog.String("error", err.Error()))
+		return ""
+	}
+	if user == nil {
+		return ""
+	}
+	if name := strings.TrimSpace(user.FirstName + " " + user.LastName); name != "" {
+		return name
+	}
+	return user.Email
+}
+
+// fixedTimeUnits are the time window units which have a fixed duration.
+var fixedTimeUnits = map[string]time.Duration{
+	"Second": time.Second,
+	"Minute": time.Minute,
+	"Hour":   time.Hour,
+	"Day":    24 * time.Hour,
+	"Week":   7 * 24 * time.Hour,
+}
+
+// getErrorBudgetLabel returns the error budget implied by the SLO objective's target.
+// If the SLO's time window has a fixed duration, the budget is also presented as a duration.
+func getErrorBudgetLabel(object *workspace.Object, value *workspace.Value) string {
+	if object.ID.Kind != manifest.KindSLO || value.GeneralizedPath != "$.spec.objectives[*].target" {
+		return ""
+	}
+	target, err := strconv.ParseFloat(value.Value, 64)
+	if err != nil || target <= 0 || target >= 1 {
+		return ""
+	}
+	budget := 1 - target
+	percent := strconv.FormatFloat(math.Round(budget*100*1e4)/1e4, 'f', -1, 64) + "%"
+
+	unit, ok := fixedTimeUnits[object.GetValueString("$.spec.timeWindows[0].unit")]
+	if !ok {
+		return "error budget: " + percent
+	}
+	count, err := strconv.Atoi(object.GetValueString("$.spec.timeWindows[0].count"))
+	if err != nil || count <= 0 {
+		return "error budget: " + percent
+	}
+	duration := time.Duration(float64(time.Duration(count)*unit) * budget)
+	return fmt.Sprintf("error budget: %s (%s)", formatDuration(duration), percent)
+}
+
+// formatDuration formats the duration in a human-readable form, e.g. 1d 9h 36m.
+// The duration is rounded to seconds.
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Second)
+	if d == 0 {
+		return "0s"
+	}
+	units := []struct {
+		suffix   string
+		duration time.Duration
+	}{
+		{"d", 24 * time.Hour},
+		{"h", time.Hour},
+		{"m", time.Minute},
+		{"s", time.Second},
+	}
+	parts := make([]string, 0, len(units))
+	for _, u := range units {
+		if n := d / u.duration; n > 0 {
+			parts = append(parts, strconv.Itoa(int(n))+u.suffix)
+			d -= n * u.duration
+		}
+	}
+	return strings.Join(parts, " ")
+}
@@ -12,7 +12,8 @@ import (
 	"github.com/pkg/errors"
 )
 
-var logLevel slog.Level
+// logLevel can be changed at runtime with [SetLogLevel].
+var logLevel = new(slog.LevelVar)
 
 // levelNames maps custom [slog.Leveler] to their string representation.
 var levelNames = map[slog.Leveler]string{
@@ -35,11 +36,11 @@ func Setup(conf Config) (io.Closer, error) {
 		writer = os.Stderr
 	}
 
-	logLevel = conf.LogLevel.Level
+	logLevel.Set(conf.LogLevel.Level)
 	jsonHandler := slog.NewJSONHandler(writer, &slog.HandlerOptions{
 		// We're using our own source handler.
 		AddSource: false,
-		Level:     conf.LogLevel.Level,
+		Level:     logLevel,
 		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
 			if a.Key == slog.LevelKey {
 				level := a.Value.Any().(slog.Level)
@@ -61,7 +62,12 @@ func Setup(conf Config) (io.Closer, error) {
 }
 
 func GetLogLevel() slog.Level {
-	return logLevel
+	return logLevel.Level()
+}
+
+// SetLogLevel changes the level of the messages logged by the default [slog.Logger].
+func SetLogLevel(level slog.Level) {
+	logLevel.Set(level)
 }
 
 // Level is a custom [slog.Leveler] that adds custom levels support extending [slog.Level].
@@ -2,8 +2,11 @@ package logging
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 	"runtime"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -13,14 +16,55 @@ const LevelTrace = slog.Level(-8)
 // Span is an interface for single trace spans.
 type Span interface {
 	Finish()
+	// Record returns the [SpanRecord] of a finished [Span].
+	// It returns nil if the [Span] has not finished yet.
+	Record() *SpanRecord
+}
+
+// SpanRecord is a finished [Span] along with the child spans which finished before it.
+type SpanRecord struct {
+	Name     string
+	Start    time.Time
+	End      time.Time
+	Children []*SpanRecord
+}
+
+// Duration returns the time it took to finish the [Span].
+func (s *SpanRecord) Duration() time.Duration {
+	return s.End.Sub(s.Start)
+}
+
+// String returns the [SpanRecord] tree, each child span is indented on a separate line.
+// Example:
+//
+//	handle 12.5ms
+//	  diagnose 10.1ms
+func (s *SpanRecord) String() string {
+	var b strings.Builder
+	s.write(&b, 0)
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func (s *SpanRecord) write(b *strings.Builder, depth int) {
+	fmt.Fprintf(b, "%s%s %s\n", strings.Repeat("  ", depth), s.Name, s.Duration())
+	for _, child := range s.Children {
+		child.write(b, depth+1)
+	}
 }
 
 type spanHandler struct {
-	ctx   context.Context
-	name  string
-	pc    uintptr
-	start time.Time
-	end   time.Time
+	ctx context.Context
+	// name is the full name of the span, joined with its parents' names.
+	name string
+	// shortName is the name the span was started with.
+	shortName string
+	parent    *spanHandler
+	pc        uintptr
+	start     time.Time
+	end       time.Time
+	children  []*SpanRecord
+	record    *SpanRecord
+	mu        sync.Mutex
 }
 
 type tracerContextKey struct{}
@@ -32,17 +76,21 @@ func StartSpan(ctx context.Context, name string) (Span, context.Context) {
 	if ctx == nil {
 		ctx = context.Background()
 	}
-	if parent, _ := ctx.Value(tracerContextKey{}).(*spanHandler); parent != nil && parent.name != "" {
+	shortName := name
+	parent, _ := ctx.Value(tracerContextKey{}).(*spanHandler)
+	if parent != nil && parent.name != "" {
 		name = parent.name + "." + name
 	}
 	var pcs [1]uintptr
 	// skip [runtime.Callers, this function]
 	runtime.Callers(2, pcs[:])
 	span := &spanHandler{
-		ctx:   ctx,
-		name:  name,
-		start: time.Now(),
-		pc:    pcs[0],
+		ctx:       ctx,
+		name:      name,
+		shortName: shortName,
+		parent:    parent,
+		start:     time.Now(),
+		pc:        pcs[0],
 	}
 	ctx = context.WithValue(ctx, tracerContextKey{}, span)
 	return span, ctx
@@ -50,17 +98,28 @@ func StartSpan(ctx context.Context, name string) (Span, context.Context) {
 
 const spanFinishedMsg = "SPAN FINISHED"
 
-// Finish ends the [Span] and logs it through [slog.Logger].
+// Finish ends the [Span], adds its [SpanRecord] to the parent [Span] and logs it through [slog.Logger].
 func (s *spanHandler) Finish() {
 	if s.ctx == nil {
 		s.ctx = context.Background()
 	}
+	s.mu.Lock()
+	s.end = time.Now()
+	s.record = &SpanRecord{
+		Name:     s.shortName,
+		Start:    s.start,
+		End:      s.end,
+		Children: s.children,
+	}
+	s.mu.Unlock()
+	if s.parent != nil {
+		s.parent.addChild(s.record)
+	}
+
 	l := slog.Default()
 	if !l.Enabled(s.ctx, LevelTrace) {
 		return
 	}
-
-	s.end = time.Now()
 	record := slog.NewRecord(s.end, LevelTrace, spanFinishedMsg, s.pc)
 	record.Add(
 		slog.Group("span",
@@ -72,3 +131,21 @@ func (s *spanHandler) Finish() {
 	)
 	_ = l.Handler().Handle(s.ctx, record)
 }
+
+// Record implements [Span].
+func (s *spanHandler) Record() *SpanRecord {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.record
+}
+
+// addChild adds the [SpanRecord] of a finished child span.
+// Children which finished after their parent are not recorded.
+func (s *spanHandler) addChild(child *SpanRecord) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.record != nil {
+		return
+	}
+	s.children = append(s.children, child)
+}
@@ -1,7 +1,10 @@
 package messages
 
+import "github.com/sourcegraph/jsonrpc2"
+
 const CancelRequestMethod = "$/cancelRequest"
 
 type CancelRequestParams struct {
-	ID any `json:"id"`
+	// ID of the request to cancel, it can be either a number or a string.
+	ID jsonrpc2.ID `json:"id"`
 }
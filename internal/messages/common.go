@@ -3,6 +3,7 @@ package messages
 const (
 	InitializedMethod = "initialized"
 	ShutdownMethod    = "shutdown"
+	ExitMethod        = "exit"
 )
 
 type TextDocumentIdentifier struct {
@@ -74,18 +75,45 @@ type TextEdit struct {
 	NewText string `json:"newText"`
 }
 
-type DocumentSymbolParams struct {
-	TextDocument TextDocumentIdentifier `json:"textDocument"`
-}
-
 type SymbolInformation struct {
-	Name          string   `json:"name"`
-	Kind          int64    `json:"kind"`
-	Deprecated    bool     `json:"deprecated"`
-	Location      Location `json:"location"`
-	ContainerName *string  `json:"containerName"`
+	Name          string     `json:"name"`
+	Kind          SymbolKind `json:"kind"`
+	Deprecated    bool       `json:"deprecated"`
+	Location      Location   `json:"location"`
+	ContainerName *string    `json:"containerName"`
 }
 
+type SymbolKind int
+
+const (
+	SymbolKindFile          SymbolKind = 1
+	SymbolKindModule        SymbolKind = 2
+	SymbolKindNamespace     SymbolKind = 3
+	SymbolKindPackage       SymbolKind = 4
+	SymbolKindClass         SymbolKind = 5
+	SymbolKindMethod        SymbolKind = 6
+	SymbolKindProperty      SymbolKind = 7
+	SymbolKindField         SymbolKind = 8
+	SymbolKindConstructor   SymbolKind = 9
+	SymbolKindEnum          SymbolKind = 10
+	SymbolKindInterface     SymbolKind = 11
+	SymbolKindFunction      SymbolKind = 12
+	SymbolKindVariable      SymbolKind = 13
+	SymbolKindConstant      SymbolKind = 14
+	SymbolKindString        SymbolKind = 15
+	SymbolKindNumber        SymbolKind = 16
+	SymbolKindBoolean       SymbolKind = 17
+	SymbolKindArray         SymbolKind = 18
+	SymbolKindObject        SymbolKind = 19
+	SymbolKindKey           SymbolKind = 20
+	SymbolKindNull          SymbolKind = 21
+	SymbolKindEnumMember    SymbolKind = 22
+	SymbolKindStruct        SymbolKind = 23
+	SymbolKindEvent         SymbolKind = 24
+	SymbolKindOperator      SymbolKind = 25
+	SymbolKindTypeParameter SymbolKind = 26
+)
+
 type Command struct {
 	Title     string `json:"title" yaml:"title"`
 	Command   string `json:"command" yaml:"command"`
@@ -94,8 +122,8 @@ type Command struct {
 }
 
 type WorkspaceEdit struct {
-	Changes         any `json:"changes"`
-	DocumentChanges any `json:"documentChanges"`
+	Changes         any `json:"changes,omitempty"`
+	DocumentChanges any `json:"documentChanges,omitempty"`
 }
 
 type MarkedString struct {
@@ -128,11 +156,7 @@ type NotificationMessage struct {
 	Params any    `json:"params"`
 }
 
-type DocumentDefinitionParams struct {
-	TextDocumentPositionParams
-	WorkDoneProgressParams
-	PartialResultParams
-}
+const DidChangeWorkspaceFoldersMethod = "workspace/didChangeWorkspaceFolders"
 
 type DidChangeWorkspaceFoldersParams struct {
 	Event WorkspaceFoldersChangeEvent `json:"event"`
@@ -0,0 +1,26 @@
+package messages
+
+import "encoding/json"
+
+const (
+	ConfigurationMethod          = "workspace/configuration"
+	DidChangeConfigurationMethod = "workspace/didChangeConfiguration"
+)
+
+type ConfigurationParams struct {
+	Items []ConfigurationItem `json:"items"`
+}
+
+type ConfigurationItem struct {
+	ScopeURI string `json:"scopeUri,omitempty"`
+	Section  string `json:"section,omitempty"`
+}
+
+type DidChangeConfigurationParams struct {
+	// Settings are client specific, they might contain all the settings or none at all.
+	Settings json.RawMessage `json:"settings,omitempty"`
+}
+
+type DidChangeConfigurationRegistrationOptions struct {
+	Section string `json:"section,omitempty"`
+}
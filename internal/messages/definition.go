@@ -0,0 +1,9 @@
+package messages
+
+const DefinitionMethod = "textDocument/definition"
+
+type DocumentDefinitionParams struct {
+	TextDocumentPositionParams
+	WorkDoneProgressParams
+	PartialResultParams
+}
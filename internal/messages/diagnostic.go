@@ -14,6 +14,7 @@ type Diagnostic struct {
 	Source             *string                        `json:"source,omitempty"`
 	Range              Range                          `json:"range"`
 	RelatedInformation []DiagnosticRelatedInformation `json:"relatedInformation,omitempty"`
+	Tags               []DiagnosticTag                `json:"tags,omitempty"`
 }
 
 type DiagnosticRelatedInformation struct {
@@ -21,6 +22,15 @@ type DiagnosticRelatedInformation struct {
 	Message  string   `json:"message"`
 }
 
+// DiagnosticTag provides additional metadata about the [Diagnostic],
+// clients might use it to render the affected code differently, e.g. faded out.
+type DiagnosticTag int
+
+const (
+	DiagnosticTagUnnecessary DiagnosticTag = 1
+	DiagnosticTagDeprecated  DiagnosticTag = 2
+)
+
 const (
 	DiagnosticSeverityError       = 1
 	DiagnosticSeverityWarning     = 2
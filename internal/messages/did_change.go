@@ -12,8 +12,11 @@ type VersionedTextDocumentIdentifier struct {
 	Version int `json:"version"`
 }
 
+// TextDocumentContentChangeEvent describes a change to a text document.
+// If Range is nil, Text is the new full content of the document.
 type TextDocumentContentChangeEvent struct {
-	Range       Range  `json:"range"`
-	RangeLength int    `json:"rangeLength"`
+	Range *Range `json:"range,omitempty"`
+	// Deprecated: use Range instead.
+	RangeLength int    `json:"rangeLength,omitempty"`
 	Text        string `json:"text"`
 }
@@ -0,0 +1,28 @@
+package messages
+
+const DidChangeWatchedFilesMethod = "workspace/didChangeWatchedFiles"
+
+type DidChangeWatchedFilesParams struct {
+	Changes []FileEvent `json:"changes"`
+}
+
+type FileEvent struct {
+	URI  string         `json:"uri"`
+	Type FileChangeType `json:"type"`
+}
+
+type FileChangeType int
+
+const (
+	FileChangeTypeCreated FileChangeType = iota + 1
+	FileChangeTypeChanged
+	FileChangeTypeDeleted
+)
+
+type DidChangeWatchedFilesRegistrationOptions struct {
+	Watchers []FileSystemWatcher `json:"watchers"`
+}
+
+type FileSystemWatcher struct {
+	GlobPattern string `json:"globPattern"`
+}
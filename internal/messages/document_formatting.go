@@ -0,0 +1,24 @@
+package messages
+
+const DocumentFormattingMethod = "textDocument/formatting"
+
+type DocumentFormattingParams struct {
+	WorkDoneProgressParams
+
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+	Options      FormattingOptions      `json:"options"`
+}
+
+// FormattingOptions describes what options formatting should use.
+type FormattingOptions struct {
+	// TabSize is the size of a tab in spaces.
+	TabSize int `json:"tabSize"`
+	// InsertSpaces prefers spaces over tabs.
+	InsertSpaces bool `json:"insertSpaces"`
+	// TrimTrailingWhitespace trims trailing whitespace on a line.
+	TrimTrailingWhitespace bool `json:"trimTrailingWhitespace,omitempty"`
+	// InsertFinalNewline inserts a newline character at the end of the file if one does not exist.
+	InsertFinalNewline bool `json:"insertFinalNewline,omitempty"`
+	// TrimFinalNewlines trims all newlines after the final newline at the end of the file.
+	TrimFinalNewlines bool `json:"trimFinalNewlines,omitempty"`
+}
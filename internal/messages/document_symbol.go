@@ -0,0 +1,21 @@
+package messages
+
+const DocumentSymbolMethod = "textDocument/documentSymbol"
+
+type DocumentSymbolParams struct {
+	WorkDoneProgressParams
+	PartialResultParams
+
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+}
+
+type DocumentSymbol struct {
+	Name   string     `json:"name"`
+	Detail string     `json:"detail,omitempty"`
+	Kind   SymbolKind `json:"kind"`
+	// Range encloses the whole symbol, e.g. the whole object definition.
+	Range Range `json:"range"`
+	// SelectionRange is the range that should be selected when the symbol is being picked, e.g. the object's name.
+	SelectionRange Range            `json:"selectionRange"`
+	Children       []DocumentSymbol `json:"children,omitempty"`
+}
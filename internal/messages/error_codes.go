@@ -0,0 +1,9 @@
+package messages
+
+// LSP specific JSON RPC error codes.
+const (
+	// CodeServerNotInitialized is returned for requests sent before [InitializeMethod].
+	CodeServerNotInitialized int64 = -32002
+	// CodeRequestCancelled is returned for requests cancelled through [CancelRequestMethod].
+	CodeRequestCancelled int64 = -32800
+)
@@ -0,0 +1,26 @@
+package messages
+
+const FoldingRangeMethod = "textDocument/foldingRange"
+
+type FoldingRangeParams struct {
+	WorkDoneProgressParams
+	PartialResultParams
+
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+}
+
+// FoldingRange represents a folding range.
+// Both start and end lines are 0-based and inclusive.
+type FoldingRange struct {
+	StartLine int              `json:"startLine"`
+	EndLine   int              `json:"endLine"`
+	Kind      FoldingRangeKind `json:"kind,omitempty"`
+}
+
+type FoldingRangeKind string
+
+const (
+	FoldingRangeKindComment FoldingRangeKind = "comment"
+	FoldingRangeKindImports FoldingRangeKind = "imports"
+	FoldingRangeKindRegion  FoldingRangeKind = "region"
+)
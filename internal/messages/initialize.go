@@ -1,16 +1,21 @@
 package messages
 
+import "slices"
+
 const InitializeMethod = "initialize"
 
 type InitializeParams struct {
 	// Information about the client
 	ClientInfo *ClientInfo `json:"clientInfo"`
 	RootURI    string      `json:"rootUri,omitempty"`
-	ProcessID  int         `json:"processId,omitempty"`
+	// WorkspaceFolders take precedence over the RootURI, if provided.
+	WorkspaceFolders []WorkspaceFolder `json:"workspaceFolders,omitempty"`
+	// ProcessID of the client's process, it is null if the process did not start the server.
+	ProcessID *int `json:"processId"`
 	// The capabilities provided by the client (editor or tool)
 	Capabilities          ClientCapabilities `json:"capabilities"`
 	InitializationOptions *InitializeOptions `json:"initializationOptions,omitempty"`
-	Trace                 string             `json:"trace,omitempty"`
+	Trace                 TraceValueKind     `json:"trace,omitempty"`
 }
 
 type ClientInfo struct {
@@ -18,7 +23,193 @@ type ClientInfo struct {
 	Version *string `json:"version"`
 }
 
-type ClientCapabilities any
+type ClientCapabilities struct {
+	General      *GeneralClientCapabilities      `json:"general,omitempty"`
+	TextDocument *TextDocumentClientCapabilities `json:"textDocument,omitempty"`
+	Window       *WindowClientCapabilities       `json:"window,omitempty"`
+	Workspace    *WorkspaceClientCapabilities    `json:"workspace,omitempty"`
+}
+
+type GeneralClientCapabilities struct {
+	// PositionEncodings supported by the client.
+	// If omitted, only [PositionEncodingUTF16] is supported.
+	PositionEncodings []PositionEncodingKind `json:"positionEncodings,omitempty"`
+}
+
+type WindowClientCapabilities struct {
+	// WorkDoneProgress is set if the client supports server initiated progress
+	// created with [WorkDoneProgressCreateMethod].
+	WorkDoneProgress bool `json:"workDoneProgress,omitempty"`
+}
+
+type WorkspaceClientCapabilities struct {
+	// Configuration is set if the client supports [ConfigurationMethod].
+	Configuration          bool                                      `json:"configuration,omitempty"`
+	DidChangeConfiguration *DidChangeConfigurationClientCapabilities `json:"didChangeConfiguration,omitempty"`
+	Diagnostics            *DiagnosticWorkspaceClientCapabilities    `json:"diagnostics,omitempty"`
+	DidChangeWatchedFiles  *DidChangeWatchedFilesClientCapabilities  `json:"didChangeWatchedFiles,omitempty"`
+}
+
+type DidChangeWatchedFilesClientCapabilities struct {
+	// DynamicRegistration is set if [DidChangeWatchedFilesMethod] can be registered with [RegisterCapabilityMethod].
+	DynamicRegistration bool `json:"dynamicRegistration,omitempty"`
+}
+
+type DidChangeConfigurationClientCapabilities struct {
+	// DynamicRegistration is set if [DidChangeConfigurationMethod] can be registered with [RegisterCapabilityMethod].
+	DynamicRegistration bool `json:"dynamicRegistration,omitempty"`
+}
+
+type DiagnosticWorkspaceClientCapabilities struct {
+	// RefreshSupport is set if the client supports [DiagnosticRefreshMethod].
+	RefreshSupport bool `json:"refreshSupport,omitempty"`
+}
+
+type TextDocumentClientCapabilities struct {
+	// Diagnostic is set if the client supports pull diagnostics.
+	Diagnostic         *DiagnosticClientCapabilities         `json:"diagnostic,omitempty"`
+	Completion         *CompletionClientCapabilities         `json:"completion,omitempty"`
+	Hover              *HoverClientCapabilities              `json:"hover,omitempty"`
+	PublishDiagnostics *PublishDiagnosticsClientCapabilities `json:"publishDiagnostics,omitempty"`
+}
+
+type CompletionClientCapabilities struct {
+	CompletionItem *CompletionItemClientCapabilities `json:"completionItem,omitempty"`
+}
+
+type CompletionItemClientCapabilities struct {
+	// SnippetSupport is set if the client supports [SnippetTextFormat].
+	SnippetSupport bool `json:"snippetSupport,omitempty"`
+}
+
+type HoverClientCapabilities struct {
+	// ContentFormat lists the [MarkupKind] supported by the client, in the order of preference.
+	ContentFormat []MarkupKind `json:"contentFormat,omitempty"`
+}
+
+type PublishDiagnosticsClientCapabilities struct {
+	// RelatedInformation is set if the client supports [DiagnosticRelatedInformation].
+	RelatedInformation bool `json:"relatedInformation,omitempty"`
+	// TagSupport is set if the client supports [Diagnostic.Tags].
+	TagSupport *DiagnosticTagSupport `json:"tagSupport,omitempty"`
+}
+
+type DiagnosticTagSupport struct {
+	// ValueSet lists the [DiagnosticTag] supported by the client.
+	ValueSet []DiagnosticTag `json:"valueSet,omitempty"`
+}
+
+type DiagnosticClientCapabilities struct {
+	DynamicRegistration    bool `json:"dynamicRegistration,omitempty"`
+	RelatedDocumentSupport bool `json:"relatedDocumentSupport,omitempty"`
+	// RelatedInformation is set if the client supports [DiagnosticRelatedInformation] in the pulled diagnostics.
+	// It was added in LSP 3.18, if it's not set, [PublishDiagnosticsClientCapabilities.RelatedInformation] applies.
+	RelatedInformation *bool `json:"relatedInformation,omitempty"`
+	// TagSupport is set if the client supports [Diagnostic.Tags] in the pulled diagnostics.
+	// It was added in LSP 3.18, if it's not set, [PublishDiagnosticsClientCapabilities.TagSupport] applies.
+	TagSupport *DiagnosticTagSupport `json:"tagSupport,omitempty"`
+}
+
+// SupportsPullDiagnostics returns true if the client advertised [DocumentDiagnosticMethod] support.
+func (c ClientCapabilities) SupportsPullDiagnostics() bool {
+	return c.TextDocument != nil && c.TextDocument.Diagnostic != nil
+}
+
+// GetPositionEncoding returns the first of the client's preferred [PositionEncodingKind]
+// which is supported by the server.
+// If the client did not advertise any supported encoding, [PositionEncodingUTF16] is returned.
+func (c ClientCapabilities) GetPositionEncoding() PositionEncodingKind {
+	if c.General == nil {
+		return PositionEncodingUTF16
+	}
+	for _, encoding := range c.General.PositionEncodings {
+		switch encoding {
+		case PositionEncodingUTF8, PositionEncodingUTF16, PositionEncodingUTF32:
+			return encoding
+		}
+	}
+	return PositionEncodingUTF16
+}
+
+// SupportsSnippets returns true if the client supports completion items with [SnippetTextFormat].
+func (c ClientCapabilities) SupportsSnippets() bool {
+	return c.TextDocument != nil &&
+		c.TextDocument.Completion != nil &&
+		c.TextDocument.Completion.CompletionItem != nil &&
+		c.TextDocument.Completion.CompletionItem.SnippetSupport
+}
+
+// GetHoverContentFormat returns the first of the client's preferred [MarkupKind] for hover contents.
+// If the client did not advertise any supported format, [PlainText] is returned.
+func (c ClientCapabilities) GetHoverContentFormat() MarkupKind {
+	if c.TextDocument == nil || c.TextDocument.Hover == nil {
+		return PlainText
+	}
+	for _, format := range c.TextDocument.Hover.ContentFormat {
+		switch format {
+		case Markdown, PlainText:
+			return format
+		}
+	}
+	return PlainText
+}
+
+// SupportsRelatedInformation returns true if the client supports [Diagnostic.RelatedInformation]
+// in the diagnostics delivery mode it uses, that is either pulled or published by the server.
+// Clients which pull diagnostics but do not advertise the pulled diagnostics capabilities
+// are assumed to support the same features as for the published diagnostics.
+func (c ClientCapabilities) SupportsRelatedInformation() bool {
+	if c.SupportsPullDiagnostics() && c.TextDocument.Diagnostic.RelatedInformation != nil {
+		return *c.TextDocument.Diagnostic.RelatedInformation
+	}
+	return c.TextDocument != nil &&
+		c.TextDocument.PublishDiagnostics != nil &&
+		c.TextDocument.PublishDiagnostics.RelatedInformation
+}
+
+// SupportsDiagnosticTag returns true if the client supports the [DiagnosticTag].
+// Just like with [ClientCapabilities.SupportsRelatedInformation],
+// the published diagnostics capabilities apply if the pulled diagnostics ones are not set.
+func (c ClientCapabilities) SupportsDiagnosticTag(tag DiagnosticTag) bool {
+	if c.SupportsPullDiagnostics() && c.TextDocument.Diagnostic.TagSupport != nil {
+		return slices.Contains(c.TextDocument.Diagnostic.TagSupport.ValueSet, tag)
+	}
+	return c.TextDocument != nil &&
+		c.TextDocument.PublishDiagnostics != nil &&
+		c.TextDocument.PublishDiagnostics.TagSupport != nil &&
+		slices.Contains(c.TextDocument.PublishDiagnostics.TagSupport.ValueSet, tag)
+}
+
+// SupportsConfiguration returns true if the client advertised [ConfigurationMethod] support.
+func (c ClientCapabilities) SupportsConfiguration() bool {
+	return c.Workspace != nil && c.Workspace.Configuration
+}
+
+// SupportsDidChangeConfigurationRegistration returns true if the client expects the server
+// to register for [DidChangeConfigurationMethod] with [RegisterCapabilityMethod].
+func (c ClientCapabilities) SupportsDidChangeConfigurationRegistration() bool {
+	return c.Workspace != nil &&
+		c.Workspace.DidChangeConfiguration != nil &&
+		c.Workspace.DidChangeConfiguration.DynamicRegistration
+}
+
+// SupportsDidChangeWatchedFilesRegistration returns true if the client expects the server
+// to register the file watchers for [DidChangeWatchedFilesMethod] with [RegisterCapabilityMethod].
+func (c ClientCapabilities) SupportsDidChangeWatchedFilesRegistration() bool {
+	return c.Workspace != nil &&
+		c.Workspace.DidChangeWatchedFiles != nil &&
+		c.Workspace.DidChangeWatchedFiles.DynamicRegistration
+}
+
+// SupportsDiagnosticRefresh returns true if the client advertised [DiagnosticRefreshMethod] support.
+func (c ClientCapabilities) SupportsDiagnosticRefresh() bool {
+	return c.Workspace != nil && c.Workspace.Diagnostics != nil && c.Workspace.Diagnostics.RefreshSupport
+}
+
+// SupportsWorkDoneProgress returns true if the client advertised [WorkDoneProgressCreateMethod] support.
+func (c ClientCapabilities) SupportsWorkDoneProgress() bool {
+	return c.Window != nil && c.Window.WorkDoneProgress
+}
 
 type InitializeOptions struct {
 	DocumentFormatting bool `json:"documentFormatting"`
@@ -40,12 +231,20 @@ type ServerInfo struct {
 }
 
 type ServerCapabilities struct {
+	PositionEncoding           PositionEncodingKind         `json:"positionEncoding,omitempty"`
 	TextDocumentSync           TextDocumentSyncKind         `json:"textDocumentSync,omitempty"`
 	DocumentSymbolProvider     bool                         `json:"documentSymbolProvider,omitempty"`
 	CompletionProvider         *CompletionProvider          `json:"completionProvider,omitempty"`
 	DefinitionProvider         bool                         `json:"definitionProvider,omitempty"`
+	ReferencesProvider         bool                         `json:"referencesProvider,omitempty"`
+	WorkspaceSymbolProvider    bool                         `json:"workspaceSymbolProvider,omitempty"`
+	RenameProvider             *RenameOptions               `json:"renameProvider,omitempty"`
 	DocumentFormattingProvider bool                         `json:"documentFormattingProvider,omitempty"`
 	RangeFormattingProvider    bool                         `json:"documentRangeFormattingProvider,omitempty"`
+	FoldingRangeProvider       bool                         `json:"foldingRangeProvider,omitempty"`
+	SemanticTokensProvider     *SemanticTokensOptions       `json:"semanticTokensProvider,omitempty"`
+	InlayHintProvider          bool                         `json:"inlayHintProvider,omitempty"`
+	DiagnosticProvider         *DiagnosticOptions           `json:"diagnosticProvider,omitempty"`
 	ExecuteCommandProvider     *ExecuteCommandProvider      `json:"executeCommandProvider"`
 	HoverProvider              bool                         `json:"hoverProvider,omitempty"`
 	CodeActionProvider         bool                         `json:"codeActionProvider,omitempty"`
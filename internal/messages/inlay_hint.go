@@ -0,0 +1,19 @@
+package messages
+
+const InlayHintMethod = "textDocument/inlayHint"
+
+type InlayHintParams struct {
+	WorkDoneProgressParams
+
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+	// Range is the document range for which inlay hints should be computed.
+	Range Range `json:"range"`
+}
+
+type InlayHint struct {
+	// Position is where the hint is displayed.
+	Position Position `json:"position"`
+	Label    string   `json:"label"`
+	// PaddingLeft renders padding before the hint.
+	PaddingLeft bool `json:"paddingLeft,omitempty"`
+}
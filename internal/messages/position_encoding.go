@@ -0,0 +1,14 @@
+package messages
+
+// PositionEncodingKind defines how [Position.Character] offsets are interpreted.
+type PositionEncodingKind string
+
+const (
+	// PositionEncodingUTF8 counts character offsets in UTF-8 code units (bytes).
+	PositionEncodingUTF8 PositionEncodingKind = "utf-8"
+	// PositionEncodingUTF16 counts character offsets in UTF-16 code units.
+	// It is the default encoding which must always be supported by the server.
+	PositionEncodingUTF16 PositionEncodingKind = "utf-16"
+	// PositionEncodingUTF32 counts character offsets in UTF-32 code units (Unicode code points).
+	PositionEncodingUTF32 PositionEncodingKind = "utf-32"
+)
@@ -0,0 +1,54 @@
+package messages
+
+const (
+	ProgressMethod               = "$/progress"
+	WorkDoneProgressCreateMethod = "window/workDoneProgress/create"
+	WorkDoneProgressCancelMethod = "window/workDoneProgress/cancel"
+)
+
+// ProgressToken is either a string or an integer.
+type ProgressToken = any
+
+type ProgressParams struct {
+	Token ProgressToken `json:"token"`
+	// Value is one of [WorkDoneProgressBegin], [WorkDoneProgressReport] or [WorkDoneProgressEnd].
+	Value any `json:"value"`
+}
+
+type WorkDoneProgressCreateParams struct {
+	Token ProgressToken `json:"token"`
+}
+
+type WorkDoneProgressCancelParams struct {
+	Token ProgressToken `json:"token"`
+}
+
+type WorkDoneProgressKind string
+
+const (
+	WorkDoneProgressKindBegin  WorkDoneProgressKind = "begin"
+	WorkDoneProgressKindReport WorkDoneProgressKind = "report"
+	WorkDoneProgressKindEnd    WorkDoneProgressKind = "end"
+)
+
+type WorkDoneProgressBegin struct {
+	Kind  WorkDoneProgressKind `json:"kind"`
+	Title string               `json:"title"`
+	// Cancellable is set if the client should show a button to cancel the operation.
+	Cancellable bool   `json:"cancellable,omitempty"`
+	Message     string `json:"message,omitempty"`
+	// Percentage is in the range of [0, 100].
+	Percentage *int `json:"percentage,omitempty"`
+}
+
+type WorkDoneProgressReport struct {
+	Kind        WorkDoneProgressKind `json:"kind"`
+	Cancellable bool                 `json:"cancellable,omitempty"`
+	Message     string               `json:"message,omitempty"`
+	Percentage  *int                 `json:"percentage,omitempty"`
+}
+
+type WorkDoneProgressEnd struct {
+	Kind    WorkDoneProgressKind `json:"kind"`
+	Message string               `json:"message,omitempty"`
+}
@@ -0,0 +1,79 @@
+package messages
+
+const (
+	DocumentDiagnosticMethod  = "textDocument/diagnostic"
+	WorkspaceDiagnosticMethod = "workspace/diagnostic"
+	// DiagnosticRefreshMethod is sent by the server to ask the client to pull all the diagnostics again.
+	DiagnosticRefreshMethod = "workspace/diagnostic/refresh"
+)
+
+type DocumentDiagnosticParams struct {
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+	// Identifier provided during registration.
+	Identifier string `json:"identifier,omitempty"`
+	// PreviousResultID is the result ID of a previous response, if provided.
+	PreviousResultID string `json:"previousResultId,omitempty"`
+}
+
+type WorkspaceDiagnosticParams struct {
+	WorkDoneProgressParams
+	PartialResultParams
+
+	// Identifier provided during registration.
+	Identifier string `json:"identifier,omitempty"`
+	// PreviousResultIDs are the currently known diagnostic reports with their previous result IDs.
+	PreviousResultIDs []PreviousResultID `json:"previousResultIds"`
+}
+
+type PreviousResultID struct {
+	URI   string `json:"uri"`
+	Value string `json:"value"`
+}
+
+type DocumentDiagnosticReportKind string
+
+const (
+	// DocumentDiagnosticReportKindFull is a diagnostic report with a full set of problems.
+	DocumentDiagnosticReportKindFull DocumentDiagnosticReportKind = "full"
+	// DocumentDiagnosticReportKindUnchanged is a report indicating
+	// that the last returned report is still accurate.
+	DocumentDiagnosticReportKindUnchanged DocumentDiagnosticReportKind = "unchanged"
+)
+
+type FullDocumentDiagnosticReport struct {
+	Kind     DocumentDiagnosticReportKind `json:"kind"`
+	ResultID string                       `json:"resultId,omitempty"`
+	Items    []Diagnostic                 `json:"items"`
+}
+
+type UnchangedDocumentDiagnosticReport struct {
+	Kind     DocumentDiagnosticReportKind `json:"kind"`
+	ResultID string                       `json:"resultId"`
+}
+
+type WorkspaceDiagnosticReport struct {
+	// Items are either [WorkspaceFullDocumentDiagnosticReport]
+	// or [WorkspaceUnchangedDocumentDiagnosticReport].
+	Items []any `json:"items"`
+}
+
+type WorkspaceFullDocumentDiagnosticReport struct {
+	FullDocumentDiagnosticReport
+	URI string `json:"uri"`
+	// Version is only set for files opened by the client.
+	Version *int `json:"version"`
+}
+
+type WorkspaceUnchangedDocumentDiagnosticReport struct {
+	UnchangedDocumentDiagnosticReport
+	URI string `json:"uri"`
+	// Version is only set for files opened by the client.
+	Version *int `json:"version"`
+}
+
+type DiagnosticOptions struct {
+	// InterFileDependencies is true if editing one file can change the diagnostics of another.
+	InterFileDependencies bool `json:"interFileDependencies"`
+	// WorkspaceDiagnostics is true if the server supports [WorkspaceDiagnosticMethod].
+	WorkspaceDiagnostics bool `json:"workspaceDiagnostics"`
+}
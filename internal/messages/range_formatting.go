@@ -0,0 +1,11 @@
+package messages
+
+const RangeFormattingMethod = "textDocument/rangeFormatting"
+
+type DocumentRangeFormattingParams struct {
+	WorkDoneProgressParams
+
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+	Range        Range                  `json:"range"`
+	Options      FormattingOptions      `json:"options"`
+}
@@ -0,0 +1,16 @@
+package messages
+
+const ReferencesMethod = "textDocument/references"
+
+type ReferenceParams struct {
+	TextDocumentPositionParams
+	WorkDoneProgressParams
+	PartialResultParams
+
+	Context ReferenceContext `json:"context"`
+}
+
+type ReferenceContext struct {
+	// IncludeDeclaration includes the declaration of the current symbol.
+	IncludeDeclaration bool `json:"includeDeclaration"`
+}
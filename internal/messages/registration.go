@@ -0,0 +1,13 @@
+package messages
+
+const RegisterCapabilityMethod = "client/registerCapability"
+
+type RegistrationParams struct {
+	Registrations []Registration `json:"registrations"`
+}
+
+type Registration struct {
+	ID              string `json:"id"`
+	Method          string `json:"method"`
+	RegisterOptions any    `json:"registerOptions,omitempty"`
+}
@@ -0,0 +1,29 @@
+package messages
+
+const (
+	RenameMethod        = "textDocument/rename"
+	PrepareRenameMethod = "textDocument/prepareRename"
+)
+
+type RenameParams struct {
+	TextDocumentPositionParams
+	WorkDoneProgressParams
+
+	// NewName is the new name of the symbol.
+	NewName string `json:"newName"`
+}
+
+type PrepareRenameParams struct {
+	TextDocumentPositionParams
+	WorkDoneProgressParams
+}
+
+type PrepareRenameResult struct {
+	Range       Range  `json:"range"`
+	Placeholder string `json:"placeholder"`
+}
+
+type RenameOptions struct {
+	// PrepareProvider is true if the server supports [PrepareRenameMethod].
+	PrepareProvider bool `json:"prepareProvider,omitempty"`
+}
@@ -0,0 +1,42 @@
+package messages
+
+const (
+	SemanticTokensFullMethod  = "textDocument/semanticTokens/full"
+	SemanticTokensRangeMethod = "textDocument/semanticTokens/range"
+)
+
+type SemanticTokensParams struct {
+	WorkDoneProgressParams
+	PartialResultParams
+
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+}
+
+type SemanticTokensRangeParams struct {
+	WorkDoneProgressParams
+	PartialResultParams
+
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+	Range        Range                  `json:"range"`
+}
+
+// SemanticTokens holds the tokens encoded as a flat list of integers.
+// Each token is represented by five integers: line delta, start character delta
+// (relative to the previous token if it's in the same line), length, token type and token modifiers bit set.
+type SemanticTokens struct {
+	ResultID string `json:"resultId,omitempty"`
+	Data     []int  `json:"data"`
+}
+
+type SemanticTokensOptions struct {
+	Legend SemanticTokensLegend `json:"legend"`
+	Range  bool                 `json:"range,omitempty"`
+	Full   bool                 `json:"full,omitempty"`
+}
+
+// SemanticTokensLegend defines the token types and modifiers the server uses.
+// Token types are referenced by their index, modifiers by their bit position.
+type SemanticTokensLegend struct {
+	TokenTypes     []string `json:"tokenTypes"`
+	TokenModifiers []string `json:"tokenModifiers"`
+}
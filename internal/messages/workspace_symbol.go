@@ -0,0 +1,12 @@
+package messages
+
+const WorkspaceSymbolMethod = "workspace/symbol"
+
+type WorkspaceSymbolParams struct {
+	WorkDoneProgressParams
+	PartialResultParams
+
+	// Query is a non-empty query string to filter symbols by.
+	// Clients may send an empty string to request all symbols.
+	Query string `json:"query"`
+}
@@ -3,12 +3,15 @@ package mux
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"sync"
 
 	"github.com/sourcegraph/jsonrpc2"
 
 	"github.com/nobl9/nobl9-language-server/internal/logging"
+	"github.com/nobl9/nobl9-language-server/internal/messages"
 	"github.com/nobl9/nobl9-language-server/internal/recovery"
 )
 
@@ -20,13 +23,41 @@ type rpcMethod = string
 // designated [MethodHandler], routed via RPC method name.
 // Example: New(map[rpcMethod]MethodHandler{"textDocument/didOpen":...})
 func New(handlers map[rpcMethod]HandlerFunc) *Mux {
-	return &Mux{handlers: handlers}
+	return &Mux{
+		handlers: handlers,
+		requests: make(map[jsonrpc2.ID]*inFlightRequest),
+	}
 }
 
 // Mux is multiplexer used to handle all incoming JSON RPC messages through [Mux.Handle].
 // It delegates JSON RPC messages to a [MethodHandler] based on its method name.
+//
+// Mux keeps track of all in-flight requests by their ID and handles [messages.CancelRequestMethod]
+// by cancelling the context of the matching request.
 type Mux struct {
 	handlers map[rpcMethod]HandlerFunc
+	requests map[jsonrpc2.ID]*inFlightRequest
+	mu       sync.Mutex
+}
+
+type inFlightRequest struct {
+	cancel context.CancelCauseFunc
+}
+
+// errRequestCancelled is the cause of the request context cancellation
+// triggered by [messages.CancelRequestMethod].
+var errRequestCancelled = errors.New("request cancelled by the client")
+
+// errCancelledResponse is returned for the requests cancelled with [messages.CancelRequestMethod].
+var errCancelledResponse = &jsonrpc2.Error{
+	Code:    messages.CodeRequestCancelled,
+	Message: "request cancelled",
+}
+
+type trackedRequestKey struct{}
+
+func isCancelled(ctx context.Context) bool {
+	return errors.Is(context.Cause(ctx), errRequestCancelled)
 }
 
 // HandlerFunc defines a JSON RPC method handler function shape.
@@ -56,6 +87,20 @@ func (m *Mux) Handle(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Req
 		slog.DebugContext(ctx, "received request", slog.Any("params", params))
 	}
 
+	if req.Method == messages.CancelRequestMethod {
+		return nil, m.handleCancelRequest(ctx, req)
+	}
+	if !req.Notif && ctx.Value(trackedRequestKey{}) == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = m.TrackRequest(ctx, req.ID)
+		defer cancel()
+	}
+	// The request might have been cancelled before it was dispatched.
+	if isCancelled(ctx) {
+		slog.DebugContext(ctx, "request cancelled before it was handled")
+		return nil, errCancelledResponse
+	}
+
 	handle, ok := m.handlers[req.Method]
 	if !ok {
 		slog.ErrorContext(ctx, "method handler not found")
@@ -65,6 +110,10 @@ func (m *Mux) Handle(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Req
 	}
 
 	result, err := handle(ctx, conn, req)
+	if isCancelled(ctx) {
+		slog.DebugContext(ctx, "request cancelled")
+		return nil, errCancelledResponse
+	}
 	if err != nil {
 		slog.ErrorContext(ctx, "failed to handle method", slog.Any("error", err))
 		return nil, err
@@ -72,3 +121,54 @@ func (m *Mux) Handle(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Req
 	slog.DebugContext(ctx, "served method", slog.Any("result", result))
 	return result, nil
 }
+
+// TrackRequest registers the request as in-flight until the returned [context.CancelFunc] is called.
+// Requests handled concurrently should be tracked synchronously, in the order they were received,
+// otherwise [messages.CancelRequestMethod] which follows the request could be handled before it's tracked.
+// [Mux.Handle] tracks the requests which were not tracked before.
+func (m *Mux) TrackRequest(ctx context.Context, id jsonrpc2.ID) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancelCause(context.WithValue(ctx, trackedRequestKey{}, id))
+	request := &inFlightRequest{cancel: cancel}
+
+	m.mu.Lock()
+	m.requests[id] = request
+	m.mu.Unlock()
+
+	return ctx, func() {
+		m.mu.Lock()
+		// The client could have reused the ID for a new request in the meantime.
+		if m.requests[id] == request {
+			delete(m.requests, id)
+		}
+		m.mu.Unlock()
+		cancel(context.Canceled)
+	}
+}
+
+func (m *Mux) handleCancelRequest(ctx context.Context, req *jsonrpc2.Request) error {
+	if req.Params == nil {
+		return &jsonrpc2.Error{
+			Code:    jsonrpc2.CodeInvalidParams,
+			Message: "missing request parameters",
+		}
+	}
+	var params messages.CancelRequestParams
+	if err := json.Unmarshal(*req.Params, &params); err != nil {
+		return &jsonrpc2.Error{
+			Code:    jsonrpc2.CodeParseError,
+			Message: fmt.Sprintf("failed to parse request parameters: %v", err),
+		}
+	}
+
+	m.mu.Lock()
+	request, ok := m.requests[params.ID]
+	m.mu.Unlock()
+	if !ok {
+		// The request has already been handled, nothing to do here.
+		slog.DebugContext(ctx, "cancelled request not found", slog.String("cancelledId", params.ID.String()))
+		return nil
+	}
+	slog.DebugContext(ctx, "cancelling request", slog.String("cancelledId", params.ID.String()))
+	request.cancel(errRequestCancelled)
+	return nil
+}
@@ -0,0 +1,117 @@
+package mux
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/jsonrpc2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nobl9/nobl9-language-server/internal/messages"
+)
+
+func TestMux_CancelRequest(t *testing.T) {
+	t.Parallel()
+
+	started := make(chan struct{})
+	m := New(map[rpcMethod]HandlerFunc{
+		messages.HoverMethod: func(ctx context.Context, _ *jsonrpc2.Conn, _ *jsonrpc2.Request) (interface{}, error) {
+			close(started)
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	})
+
+	type response struct {
+		result any
+		err    error
+	}
+	responses := make(chan response, 1)
+	go func() {
+		result, err := m.Handle(context.Background(), nil, newRequest(t, jsonrpc2.ID{Num: 1}, messages.HoverMethod, nil))
+		responses <- response{result: result, err: err}
+	}()
+
+	select {
+	case <-started:
+	case <-time.After(time.Second):
+		require.FailNow(t, "timed out waiting for the request to start")
+	}
+	_, err := m.Handle(context.Background(), nil, newNotification(t, messages.CancelRequestMethod,
+		messages.CancelRequestParams{ID: jsonrpc2.ID{Num: 1}}))
+	require.NoError(t, err)
+
+	select {
+	case resp := <-responses:
+		assert.Nil(t, resp.result)
+		assert.Equal(t, &jsonrpc2.Error{
+			Code:    messages.CodeRequestCancelled,
+			Message: "request cancelled",
+		}, resp.err)
+	case <-time.After(time.Second):
+		require.FailNow(t, "timed out waiting for the request to be cancelled")
+	}
+	assert.Empty(t, m.requests)
+}
+
+func TestMux_CancelRequest_NotFound(t *testing.T) {
+	t.Parallel()
+
+	m := New(map[rpcMethod]HandlerFunc{
+		messages.HoverMethod: func(context.Context, *jsonrpc2.Conn, *jsonrpc2.Request) (interface{}, error) {
+			return "hover", nil
+		},
+	})
+
+	result, err := m.Handle(context.Background(), nil, newRequest(t, jsonrpc2.ID{Str: "foo", IsString: true},
+		messages.HoverMethod, nil))
+	require.NoError(t, err)
+	assert.Equal(t, "hover", result)
+
+	_, err = m.Handle(context.Background(), nil, newNotification(t, messages.CancelRequestMethod,
+		messages.CancelRequestParams{ID: jsonrpc2.ID{Str: "foo", IsString: true}}))
+	require.NoError(t, err)
+	assert.Empty(t, m.requests)
+}
+
+func TestMux_CancelRequest_BeforeHandled(t *testing.T) {
+	t.Parallel()
+
+	m := New(map[rpcMethod]HandlerFunc{
+		messages.HoverMethod: func(context.Context, *jsonrpc2.Conn, *jsonrpc2.Request) (interface{}, error) {
+			return "hover", nil
+		},
+	})
+
+	ctx, done := m.TrackRequest(context.Background(), jsonrpc2.ID{Num: 1})
+	_, err := m.Handle(context.Background(), nil, newNotification(t, messages.CancelRequestMethod,
+		messages.CancelRequestParams{ID: jsonrpc2.ID{Num: 1}}))
+	require.NoError(t, err)
+
+	result, err := m.Handle(ctx, nil, newRequest(t, jsonrpc2.ID{Num: 1}, messages.HoverMethod, nil))
+	done()
+	assert.Nil(t, result)
+	assert.Equal(t, &jsonrpc2.Error{
+		Code:    messages.CodeRequestCancelled,
+		Message: "request cancelled",
+	}, err)
+	assert.Empty(t, m.requests)
+}
+
+func newRequest(t *testing.T, id jsonrpc2.ID, method string, params any) *jsonrpc2.Request {
+	t.Helper()
+	req := &jsonrpc2.Request{ID: id, Method: method}
+	if params != nil {
+		require.NoError(t, req.SetParams(params))
+	}
+	return req
+}
+
+func newNotification(t *testing.T, method string, params any) *jsonrpc2.Request {
+	t.Helper()
+	req := &jsonrpc2.Request{Method: method, Notif: true}
+	require.NoError(t, req.SetParams(params))
+	return req
+}
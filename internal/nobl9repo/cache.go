@@ -7,9 +7,12 @@ import (
 	"time"
 )
 
+// DefaultCacheTTL is the default duration for which the Nobl9 API responses are cached.
+const DefaultCacheTTL = 5 * time.Minute
+
 func newDataCache() *dataCache {
 	return &dataCache{
-		retention: 5 * time.Minute,
+		retention: DefaultCacheTTL,
 		cache:     make(map[string]cacheEntry),
 	}
 }
@@ -43,6 +46,25 @@ func (c *dataCache) Get(ctx context.Context, key string) (data any, hit bool) {
 	return entry.data, true
 }
 
+// SetRetention changes the retention of the entries put into the cache from now on.
+func (c *dataCache) SetRetention(retention time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.retention = retention
+}
+
+// Epoch is the number of retention periods which have passed since the Unix epoch.
+// Every entry put into the cache expires before the epoch after the next one begins.
+func (c *dataCache) Epoch() uint64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	now := time.Now().UnixNano()
+	if c.retention <= 0 {
+		return uint64(now)
+	}
+	return uint64(now / int64(c.retention))
+}
+
 func (c *dataCache) Put(key string, data any) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -4,56 +4,135 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"net/url"
+	"sync"
+	"time"
 
 	"github.com/nobl9/nobl9-go/manifest"
 	"github.com/nobl9/nobl9-go/sdk"
 	v1objects "github.com/nobl9/nobl9-go/sdk/endpoints/objects/v1"
 
-	"github.com/nobl9/nobl9-language-server/internal/version"
+	"github.com/nobl9/nobl9-language-server/internal/messages"
+	"github.com/nobl9/nobl9-language-server/internal/progress"
 )
 
 const envPrefix = "NOBL9_LANGUAGE_SERVER_"
 
-func NewRepo() (*Repo, error) {
-	options := []sdk.ConfigOption{
-		sdk.ConfigOptionEnvPrefix(envPrefix),
-	}
-	conf, err := sdk.ReadConfig(options...)
-	if err != nil {
-		return nil, err
-	}
-	client, err := sdk.NewClient(conf)
+// warmUpKinds are the kinds of objects which are most commonly referenced by other objects.
+var warmUpKinds = []manifest.Kind{
+	manifest.KindService,
+	manifest.KindAlertPolicy,
+	manifest.KindAlertMethod,
+	manifest.KindAgent,
+	manifest.KindDirect,
+}
+
+type progressReporter interface {
+	Begin(ctx context.Context, token messages.ProgressToken, title string, total int) (context.Context, *progress.Progress)
+}
+
+func NewRepo(progressReporter progressReporter) (*Repo, error) {
+	client, err := newClient("")
 	if err != nil {
 		return nil, err
 	}
-	client.SetUserAgent(version.GetUserAgent())
 	return &Repo{
-		client: client,
-		cache:  newDataCache(),
+		defaultClient: client,
+		clients:       make(map[string]*sdk.Client),
+		cache:         newDataCache(),
+		progress:      progressReporter,
 	}, nil
 }
 
 type Repo struct {
-	cache  *dataCache
-	client *sdk.Client
+	cache *dataCache
+	// defaultClient is used unless a different [Scope.ConfigContext] is bound to the context.
+	defaultClient *sdk.Client
+	clients       map[string]*sdk.Client
+	clientsMu     sync.Mutex
+	progress      progressReporter
+}
+
+// WarmUp fills the cache with the names of Projects, the most commonly referenced objects
+// in the default Project and the users' roles.
+// This way the first completions and diagnostics don't have to wait for the API.
+// Errors are logged, they don't stop the warm-up.
+func (r *Repo) WarmUp(ctx context.Context) {
+	steps := []func(ctx context.Context) error{
+		func(ctx context.Context) error {
+			_, err := r.GetAllNames(ctx, manifest.KindProject, "")
+			return err
+		},
+		func(ctx context.Context) error {
+			_, err := r.GetRoles(ctx)
+			return err
+		},
+	}
+	for _, kind := range warmUpKinds {
+		steps = append(steps, func(ctx context.Context) error {
+			_, err := r.GetAllNames(ctx, kind, r.GetDefaultProject(ctx))
+			return err
+		})
+	}
+
+	ctx, p := r.progress.Begin(ctx, nil, "Fetching Nobl9 resources", len(steps))
+	defer p.End(ctx, "")
+	for i, step := range steps {
+		if ctx.Err() != nil {
+			return
+		}
+		p.Report(ctx, i, fmt.Sprintf("Fetching %d/%d resources", i+1, len(steps)))
+		if err := step(ctx); err != nil {
+			slog.ErrorContext(ctx, "failed to warm up cache", slog.Any("error", err))
+		}
+	}
+}
+
+// SetCacheTTL changes the duration for which the Nobl9 API responses are cached.
+func (r *Repo) SetCacheTTL(ttl time.Duration) {
+	r.cache.SetRetention(ttl)
 }
 
-func (r *Repo) GetDefaultProject() string {
-	return r.client.Config.Project
+// CacheEpoch identifies the period in which the cached Nobl9 API responses are valid.
+// It changes at least once per cache TTL, the results computed with the cached responses
+// should be considered outdated once it does.
+func (r *Repo) CacheEpoch() uint64 {
+	return r.cache.Epoch()
+}
+
+// GetDefaultProject returns the [Scope.Project] bound to the context,
+// or the default Project of the configuration context.
+func (r *Repo) GetDefaultProject(ctx context.Context) string {
+	if project := GetScope(ctx).Project; project != "" {
+		return project
+	}
+	client, err := r.getClient(ctx)
+	if err != nil {
+		return r.defaultClient.Config.Project
+	}
+	return client.Config.Project
 }
 
 func (r *Repo) Apply(ctx context.Context, objects []manifest.Object) error {
-	return r.client.Objects().V1().Apply(ctx, objects)
+	client, err := r.getClient(ctx)
+	if err != nil {
+		return err
+	}
+	return client.Objects().V1().Apply(ctx, objects)
 }
 
 func (r *Repo) Delete(ctx context.Context, objects []manifest.Object) error {
-	return r.client.Objects().V1().Delete(ctx, objects)
+	client, err := r.getClient(ctx)
+	if err != nil {
+		return err
+	}
+	return client.Objects().V1().Delete(ctx, objects)
 }
 
 func (r *Repo) GetAllNames(ctx context.Context, kind manifest.Kind, project string) ([]string, error) {
-	cacheKey := fmt.Sprintf("GetAllNames:%s:%s", kind, project)
+	cacheKey := getCacheKey(ctx, fmt.Sprintf("GetAllNames:%s:%s", kind, project))
 	if data, ok := r.cache.Get(ctx, cacheKey); ok {
 		names, _ := data.([]string)
 		return names, nil
@@ -63,7 +142,11 @@ func (r *Repo) GetAllNames(ctx context.Context, kind manifest.Kind, project stri
 	if project != "" {
 		header.Set(sdk.HeaderProject, project)
 	}
-	objects, err := r.client.Objects().V1().Get(
+	client, err := r.getClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	objects, err := client.Objects().V1().Get(
 		ctx,
 		kind,
 		header,
@@ -80,8 +163,35 @@ func (r *Repo) GetAllNames(ctx context.Context, kind manifest.Kind, project stri
 	return names, nil
 }
 
+// GetAllObjects returns the objects of the given kind from all Projects.
+func (r *Repo) GetAllObjects(ctx context.Context, kind manifest.Kind) ([]manifest.Object, error) {
+	cacheKey := getCacheKey(ctx, fmt.Sprintf("GetAllObjects:%s", kind))
+	if data, ok := r.cache.Get(ctx, cacheKey); ok {
+		objects, _ := data.([]manifest.Object)
+		return objects, nil
+	}
+
+	header := http.Header{}
+	header.Set(sdk.HeaderProject, sdk.ProjectsWildcard)
+	client, err := r.getClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	objects, err := client.Objects().V1().Get(
+		ctx,
+		kind,
+		header,
+		url.Values{},
+	)
+	if err != nil {
+		return nil, err
+	}
+	r.cache.Put(cacheKey, objects)
+	return objects, nil
+}
+
 func (r *Repo) GetObject(ctx context.Context, kind manifest.Kind, name, project string) (manifest.Object, error) {
-	cacheKey := fmt.Sprintf("GetObject:%s:%s:%s", kind, name, project)
+	cacheKey := getCacheKey(ctx, fmt.Sprintf("GetObject:%s:%s:%s", kind, name, project))
 	if data, ok := r.cache.Get(ctx, cacheKey); ok {
 		object, _ := data.(manifest.Object)
 		return object, nil
@@ -93,7 +203,11 @@ func (r *Repo) GetObject(ctx context.Context, kind manifest.Kind, name, project
 	} else {
 		header.Set(sdk.HeaderProject, sdk.ProjectsWildcard)
 	}
-	objects, err := r.client.Objects().V1().Get(
+	client, err := r.getClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	objects, err := client.Objects().V1().Get(
 		ctx,
 		kind,
 		header,
@@ -124,7 +238,7 @@ type User struct {
 }
 
 func (r *Repo) GetUser(ctx context.Context, id string) (*User, error) {
-	cacheKey := fmt.Sprintf("GetUser:%s", id)
+	cacheKey := getCacheKey(ctx, fmt.Sprintf("GetUser:%s", id))
 	if data, ok := r.cache.Get(ctx, cacheKey); ok {
 		user, _ := data.(*User)
 		return user, nil
@@ -144,11 +258,15 @@ func (r *Repo) GetUser(ctx context.Context, id string) (*User, error) {
 
 func (r *Repo) GetUsers(ctx context.Context, phrase string) ([]*User, error) {
 	q := url.Values{"phrase": []string{phrase}}
-	req, err := r.client.CreateRequest(ctx, http.MethodGet, "/usrmgmt/v2/users", nil, q, nil)
+	client, err := r.getClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	req, err := client.CreateRequest(ctx, http.MethodGet, "/usrmgmt/v2/users", nil, q, nil)
 	if err != nil {
 		return nil, err
 	}
-	resp, err := r.client.Do(req)
+	resp, err := client.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -170,17 +288,21 @@ type Role struct {
 }
 
 func (r *Repo) GetRoles(ctx context.Context) (*Roles, error) {
-	cacheKey := "GetRoles"
+	cacheKey := getCacheKey(ctx, "GetRoles")
 	if data, ok := r.cache.Get(ctx, cacheKey); ok {
 		roles, _ := data.(*Roles)
 		return roles, nil
 	}
 
-	req, err := r.client.CreateRequest(ctx, http.MethodGet, "/usrmgmt/v2/users/search-filters", nil, nil, nil)
+	client, err := r.getClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	req, err := client.CreateRequest(ctx, http.MethodGet, "/usrmgmt/v2/users/search-filters", nil, nil, nil)
 	if err != nil {
 		return nil, err
 	}
-	resp, err := r.client.Do(req)
+	resp, err := client.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,77 @@
+package nobl9repo
+
+import (
+	"context"
+
+	"github.com/nobl9/nobl9-go/sdk"
+
+	"github.com/nobl9/nobl9-language-server/internal/version"
+)
+
+// Scope selects the Nobl9 configuration context and the default Project used by the [Repo].
+// By default, the [Repo] uses the context and Project resolved from the configuration file
+// and environment variables.
+type Scope struct {
+	// ConfigContext is the name of the context defined in the Nobl9 configuration file.
+	ConfigContext string
+	// Project overrides the default Project of the configuration context.
+	Project string
+}
+
+type scopeKey struct{}
+
+// WithScope binds the [Scope] to the context, the [Repo] uses it for all the calls made with this context.
+func WithScope(ctx context.Context, scope Scope) context.Context {
+	return context.WithValue(ctx, scopeKey{}, scope)
+}
+
+// GetScope returns the [Scope] bound to the context with [WithScope].
+func GetScope(ctx context.Context) Scope {
+	scope, _ := ctx.Value(scopeKey{}).(Scope)
+	return scope
+}
+
+// getClient returns the [sdk.Client] for the [Scope.ConfigContext] bound to the context.
+// Clients are created once per configuration context.
+func (r *Repo) getClient(ctx context.Context) (*sdk.Client, error) {
+	configContext := GetScope(ctx).ConfigContext
+	if configContext == "" {
+		return r.defaultClient, nil
+	}
+	r.clientsMu.Lock()
+	defer r.clientsMu.Unlock()
+	if client, ok := r.clients[configContext]; ok {
+		return client, nil
+	}
+	client, err := newClient(configContext)
+	if err != nil {
+		return nil, err
+	}
+	r.clients[configContext] = client
+	return client, nil
+}
+
+// getCacheKey prefixes the key with the [Scope.ConfigContext],
+// as different contexts might point to different organizations.
+func getCacheKey(ctx context.Context, key string) string {
+	return GetScope(ctx).ConfigContext + ":" + key
+}
+
+func newClient(configContext string) (*sdk.Client, error) {
+	options := []sdk.ConfigOption{
+		sdk.ConfigOptionEnvPrefix(envPrefix),
+	}
+	if configContext != "" {
+		options = append(options, sdk.ConfigOptionUseContext(configContext))
+	}
+	conf, err := sdk.ReadConfig(options...)
+	if err != nil {
+		return nil, err
+	}
+	client, err := sdk.NewClient(conf)
+	if err != nil {
+		return nil, err
+	}
+	client.SetUserAgent(version.GetUserAgent())
+	return client, nil
+}
@@ -116,7 +116,7 @@ var objectReferences = func() map[manifest.Kind]map[string]*Reference {
 		},
 		{
 			Path:      "$.spec.filters.slos[*].project",
-			Kind:      manifest.KindSLO,
+			Kind:      manifest.KindProject,
 			appliesTo: []manifest.Kind{manifest.KindBudgetAdjustment},
 		},
 		{
@@ -190,6 +190,12 @@ var objectReferences = func() map[manifest.Kind]map[string]*Reference {
 			Kind:        manifest.KindAlertPolicy,
 			appliesTo:   []manifest.Kind{manifest.KindSLO},
 		},
+		{
+			Path:        "$.spec.alertPolicies[*]",
+			ProjectPath: "$.metadata.project",
+			Kind:        manifest.KindAlertPolicy,
+			appliesTo:   []manifest.Kind{manifest.KindSLO},
+		},
 		{
 			Path:      "$.spec.objectives[*].composite.components.objectives[*].project",
 			Kind:      manifest.KindProject,
@@ -232,6 +238,20 @@ var objectReferences = func() map[manifest.Kind]map[string]*Reference {
 	return m
 }()
 
+// GetReferencingKinds returns the sorted kinds of objects which can reference objects of the given kind.
+func GetReferencingKinds(kind manifest.Kind) []manifest.Kind {
+	var kinds []manifest.Kind
+	for referencingKind, refs := range objectReferences {
+		for _, ref := range refs {
+			if ref.Kind == kind && !slices.Contains(kinds, referencingKind) {
+				kinds = append(kinds, referencingKind)
+			}
+		}
+	}
+	slices.SortFunc(kinds, func(k1, k2 manifest.Kind) int { return strings.Compare(k1.String(), k2.String()) })
+	return kinds
+}
+
 func IsProjectScoped(kind manifest.Kind) bool {
 	return slices.Contains(projectScopedKinds, kind)
 }
@@ -268,6 +288,10 @@ func calculateReferencedPath(basePath, refPath string) string {
 			nStr += string(ch)
 		}
 	}
+	// The referenced path might not be as deeply nested as the base path.
+	if verbs := strings.Count(refPath, "%d"); verbs < len(indexes) {
+		indexes = indexes[:verbs]
+	}
 	if len(indexes) == 0 {
 		return refPath
 	}
@@ -76,6 +76,29 @@ func TestGet(t *testing.T) {
 				ProjectPath: "$.spec.objectives[2].composite.components.objectives[1].project",
 			},
 		},
+		{
+			kind: manifest.KindSLO,
+			line: &yamlastsimple.Line{
+				Path:            "$.spec.alertPolicies[1]",
+				GeneralizedPath: "$.spec.alertPolicies[*]",
+			},
+			expected: &Reference{
+				Kind:        manifest.KindAlertPolicy,
+				Path:        "$.spec.alertPolicies[*]",
+				ProjectPath: "$.metadata.project",
+			},
+		},
+		{
+			kind: manifest.KindBudgetAdjustment,
+			line: &yamlastsimple.Line{
+				Path:            "$.spec.filters.slos[0].project",
+				GeneralizedPath: "$.spec.filters.slos[*].project",
+			},
+			expected: &Reference{
+				Kind: manifest.KindProject,
+				Path: "$.spec.filters.slos[*].project",
+			},
+		},
 		{
 			kind: manifest.KindService,
 			line: &yamlastsimple.Line{
@@ -135,3 +158,18 @@ func TestReference_FallbackProjectPath(t *testing.T) {
 		})
 	}
 }
+
+func TestGetReferencingKinds(t *testing.T) {
+	tests := map[manifest.Kind][]manifest.Kind{
+		manifest.KindAlertMethod: {manifest.KindAlertPolicy, manifest.KindSLO},
+		manifest.KindAlertPolicy: {manifest.KindAlertSilence, manifest.KindSLO},
+		manifest.KindService:     {manifest.KindReport, manifest.KindSLO},
+		manifest.KindUserGroup:   {manifest.KindRoleBinding},
+		manifest.KindAgent:       nil,
+	}
+	for kind, expected := range tests {
+		t.Run(kind.String(), func(t *testing.T) {
+			assert.Equal(t, expected, GetReferencingKinds(kind))
+		})
+	}
+}
@@ -0,0 +1,3 @@
+// Package progress reports the progress of long-running operations to the client
+// using LSP work done progress notifications.
+package progress
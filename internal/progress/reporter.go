@@ -0,0 +1,200 @@
+package progress
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+
+	"github.com/nobl9/nobl9-language-server/internal/config"
+	"github.com/nobl9/nobl9-language-server/internal/messages"
+)
+
+// ErrCancelled is the cause of the [Reporter.Begin] context cancellation
+// triggered by [messages.WorkDoneProgressCancelMethod].
+var ErrCancelled = errors.New("operation cancelled by the client")
+
+type clientConn interface {
+	Notify(ctx context.Context, method string, params any) error
+	Call(ctx context.Context, method string, params, result any) error
+}
+
+// NewReporter creates a new [Reporter] which sends the progress notifications through the client connection.
+func NewReporter(client clientConn) *Reporter {
+	return &Reporter{
+		client:     client,
+		inProgress: make(map[messages.ProgressToken]context.CancelCauseFunc),
+	}
+}
+
+// Reporter creates [Progress] instances and keeps track of the ones which are still in progress,
+// so that they can be cancelled by the client.
+type Reporter struct {
+	client clientConn
+	// supported is set if the client supports server initiated progress.
+	supported atomic.Bool
+	lastID    atomic.Int64
+	// inProgress maps progress tokens to their operations' context cancellation functions.
+	inProgress map[messages.ProgressToken]context.CancelCauseFunc
+	mu         sync.Mutex
+}
+
+// SetSupported sets whether the client supports server initiated progress.
+func (r *Reporter) SetSupported(supported bool) {
+	r.supported.Store(supported)
+}
+
+// Begin starts reporting the progress of an operation which consists of total steps.
+// If total is zero, the progress is indeterminate and no percentage is reported.
+// If the token was not provided by the client, a new one is created, granted the client supports it.
+// Otherwise, the returned [Progress] does not report anything.
+// The returned context is cancelled with [ErrCancelled] if the client cancels the operation,
+// it must be released by calling [Progress.End].
+func (r *Reporter) Begin(
+	ctx context.Context,
+	token messages.ProgressToken,
+	title string,
+	total int,
+) (context.Context, *Progress) {
+	return r.begin(ctx, token, title, total, true)
+}
+
+// BeginRequested works like [Reporter.Begin], but it never creates a new token.
+// The progress is reported only if the client provided the token along with its request.
+// It suits the requests which clients send repeatedly in the background, like workspace diagnostics.
+func (r *Reporter) BeginRequested(
+	ctx context.Context,
+	token messages.ProgressToken,
+	title string,
+	total int,
+) (context.Context, *Progress) {
+	return r.begin(ctx, token, title, total, false)
+}
+
+func (r *Reporter) begin(
+	ctx context.Context,
+	token messages.ProgressToken,
+	title string,
+	total int,
+	createToken bool,
+) (context.Context, *Progress) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	progress := &Progress{
+		reporter: r,
+		total:    total,
+		cancel:   cancel,
+	}
+	if token == nil && createToken {
+		token = r.createToken(ctx)
+	}
+	if token == nil {
+		return ctx, progress
+	}
+	progress.token = token
+
+	r.mu.Lock()
+	r.inProgress[token] = cancel
+	r.mu.Unlock()
+
+	begin := messages.WorkDoneProgressBegin{
+		Kind:        messages.WorkDoneProgressKindBegin,
+		Title:       title,
+		Cancellable: true,
+	}
+	if total > 0 {
+		begin.Percentage = ptr(0)
+	}
+	r.notify(ctx, token, begin)
+	return ctx, progress
+}
+
+// Cancel cancels the operation identified by the token, if it is still in progress.
+func (r *Reporter) Cancel(ctx context.Context, token messages.ProgressToken) {
+	r.mu.Lock()
+	cancel, ok := r.inProgress[token]
+	r.mu.Unlock()
+	if !ok {
+		slog.DebugContext(ctx, "cancelled progress not found", slog.Any("token", token))
+		return
+	}
+	slog.DebugContext(ctx, "cancelling progress", slog.Any("token", token))
+	cancel(ErrCancelled)
+}
+
+// createToken asks the client to create a new progress token.
+// It returns nil if the client does not support server initiated progress or failed to create the token.
+func (r *Reporter) createToken(ctx context.Context) messages.ProgressToken {
+	if !r.supported.Load() {
+		return nil
+	}
+	token := fmt.Sprintf("%s/%d", config.ServerName, r.lastID.Add(1))
+	if err := r.client.Call(
+		ctx,
+		messages.WorkDoneProgressCreateMethod,
+		messages.WorkDoneProgressCreateParams{Token: token},
+		nil,
+	); err != nil {
+		slog.ErrorContext(ctx, "failed to create progress token", slog.Any("error", err))
+		return nil
+	}
+	return token
+}
+
+func (r *Reporter) notify(ctx context.Context, token messages.ProgressToken, value any) {
+	// Progress has to be reported even if the operation was cancelled.
+	ctx = context.WithoutCancel(ctx)
+	if err := r.client.Notify(ctx, messages.ProgressMethod, messages.ProgressParams{
+		Token: token,
+		Value: value,
+	}); err != nil {
+		slog.ErrorContext(ctx, "failed to report progress", slog.Any("error", err))
+	}
+}
+
+func (r *Reporter) remove(token messages.ProgressToken) {
+	r.mu.Lock()
+	delete(r.inProgress, token)
+	r.mu.Unlock()
+}
+
+// Progress reports the progress of a single operation.
+type Progress struct {
+	reporter *Reporter
+	token    messages.ProgressToken
+	total    int
+	cancel   context.CancelCauseFunc
+}
+
+// Report informs the client that done out of total steps were completed.
+// Example message: "Applying 12/40 objects".
+func (p *Progress) Report(ctx context.Context, done int, message string) {
+	if p.token == nil {
+		return
+	}
+	report := messages.WorkDoneProgressReport{
+		Kind:        messages.WorkDoneProgressKindReport,
+		Cancellable: true,
+		Message:     message,
+	}
+	if p.total > 0 {
+		report.Percentage = ptr(min(done*100/p.total, 100))
+	}
+	p.reporter.notify(ctx, p.token, report)
+}
+
+// End informs the client that the operation has finished and releases its context.
+func (p *Progress) End(ctx context.Context, message string) {
+	defer p.cancel(context.Canceled)
+	if p.token == nil {
+		return
+	}
+	p.reporter.remove(p.token)
+	p.reporter.notify(ctx, p.token, messages.WorkDoneProgressEnd{
+		Kind:    messages.WorkDoneProgressKindEnd,
+		Message: message,
+	})
+}
+
+func ptr[T any](v T) *T { return &v }
@@ -0,0 +1,137 @@
+package progress
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nobl9/nobl9-language-server/internal/messages"
+)
+
+func TestReporter(t *testing.T) {
+	t.Run("client does not support progress", func(t *testing.T) {
+		client := &fakeClient{}
+		reporter := NewReporter(client)
+
+		ctx, p := reporter.Begin(context.Background(), nil, "Applying objects", 2)
+		p.Report(ctx, 1, "Applying 1/2 objects")
+		p.End(ctx, "")
+
+		assert.Empty(t, client.calls)
+		assert.Empty(t, client.notifications)
+		assert.Error(t, ctx.Err())
+	})
+	t.Run("server initiated progress", func(t *testing.T) {
+		client := &fakeClient{}
+		reporter := NewReporter(client)
+		reporter.SetSupported(true)
+
+		ctx, p := reporter.Begin(context.Background(), nil, "Applying objects", 4)
+		p.Report(ctx, 1, "Applying 2/4 objects")
+		p.End(ctx, "Done")
+
+		require.Len(t, client.calls, 1)
+		token := client.calls[0].(messages.WorkDoneProgressCreateParams).Token
+		assert.Equal(t, []messages.ProgressParams{
+			{Token: token, Value: messages.WorkDoneProgressBegin{
+				Kind:        messages.WorkDoneProgressKindBegin,
+				Title:       "Applying objects",
+				Cancellable: true,
+				Percentage:  ptr(0),
+			}},
+			{Token: token, Value: messages.WorkDoneProgressReport{
+				Kind:        messages.WorkDoneProgressKindReport,
+				Cancellable: true,
+				Message:     "Applying 2/4 objects",
+				Percentage:  ptr(25),
+			}},
+			{Token: token, Value: messages.WorkDoneProgressEnd{
+				Kind:    messages.WorkDoneProgressKindEnd,
+				Message: "Done",
+			}},
+		}, client.notifications)
+	})
+	t.Run("requested progress without token", func(t *testing.T) {
+		client := &fakeClient{}
+		reporter := NewReporter(client)
+		reporter.SetSupported(true)
+
+		ctx, p := reporter.BeginRequested(context.Background(), nil, "Validating files", 2)
+		p.Report(ctx, 1, "Validating 2/2 files")
+		p.End(ctx, "")
+
+		assert.Empty(t, client.calls)
+		assert.Empty(t, client.notifications)
+	})
+	t.Run("indeterminate progress", func(t *testing.T) {
+		client := &fakeClient{}
+		reporter := NewReporter(client)
+
+		ctx, p := reporter.Begin(context.Background(), "token", "Applying objects", 0)
+		p.Report(ctx, 0, "Fetching resources")
+		p.End(ctx, "Done")
+
+		assert.Equal(t, []messages.ProgressParams{
+			{Token: "token", Value: messages.WorkDoneProgressBegin{
+				Kind:        messages.WorkDoneProgressKindBegin,
+				Title:       "Applying objects",
+				Cancellable: true,
+			}},
+			{Token: "token", Value: messages.WorkDoneProgressReport{
+				Kind:        messages.WorkDoneProgressKindReport,
+				Cancellable: true,
+				Message:     "Fetching resources",
+			}},
+			{Token: "token", Value: messages.WorkDoneProgressEnd{
+				Kind:    messages.WorkDoneProgressKindEnd,
+				Message: "Done",
+			}},
+		}, client.notifications)
+	})
+	t.Run("client provided token", func(t *testing.T) {
+		client := &fakeClient{}
+		reporter := NewReporter(client)
+
+		_, p := reporter.Begin(context.Background(), "token", "Validating files", 1)
+		p.End(context.Background(), "")
+
+		assert.Empty(t, client.calls)
+		require.Len(t, client.notifications, 2)
+		assert.Equal(t, "token", client.notifications[0].Token)
+	})
+	t.Run("cancel progress", func(t *testing.T) {
+		client := &fakeClient{}
+		reporter := NewReporter(client)
+
+		ctx, p := reporter.Begin(context.Background(), "token", "Indexing files", 1)
+		reporter.Cancel(ctx, "token")
+
+		assert.ErrorIs(t, context.Cause(ctx), ErrCancelled)
+		p.End(ctx, "")
+		assert.ErrorIs(t, context.Cause(ctx), ErrCancelled)
+		assert.Empty(t, reporter.inProgress)
+	})
+}
+
+type fakeClient struct {
+	calls         []any
+	notifications []messages.ProgressParams
+	mu            sync.Mutex
+}
+
+func (f *fakeClient) Notify(_ context.Context, _ string, params any) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.notifications = append(f.notifications, params.(messages.ProgressParams))
+	return nil
+}
+
+func (f *fakeClient) Call(_ context.Context, _ string, params, _ any) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, params)
+	return nil
+}
@@ -0,0 +1,66 @@
+package references
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/nobl9/nobl9-language-server/internal/files"
+	"github.com/nobl9/nobl9-language-server/internal/messages"
+	"github.com/nobl9/nobl9-language-server/internal/workspace"
+)
+
+func NewHandler(files *files.FS, snapshots *workspace.Snapshots) *Handler {
+	return &Handler{files: files, snapshots: snapshots}
+}
+
+type Handler struct {
+	files     *files.FS
+	snapshots *workspace.Snapshots
+}
+
+// Handle returns the locations of all the values which reference the object under the cursor.
+// The cursor can be placed either on the object's name, SLO objective's name or on any reference to them.
+func (h *Handler) Handle(ctx context.Context, params messages.ReferenceParams) (any, error) {
+	file, err := h.files.GetFile(params.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+	ctx = file.AddToLogContext(ctx)
+	if file.Skip {
+		slog.DebugContext(ctx, "skipping file")
+		return nil, nil
+	}
+
+	position := file.GetPositionMapper().FromClientPosition(params.Position)
+	snapshot := h.snapshots.Get()
+	object, value := snapshot.FindValue(file.URI, position)
+	if object == nil || value == nil {
+		slog.DebugContext(ctx, "no value found", slog.Any("position", position))
+		return nil, nil
+	}
+	target, objective, ok := workspace.ResolveTarget(object, value)
+	if !ok {
+		slog.DebugContext(ctx, "value neither defines nor references an object", slog.String("path", value.Path))
+		return nil, nil
+	}
+
+	locations := make([]messages.Location, 0)
+	if params.Context.IncludeDeclaration {
+		for _, definition := range snapshot.FindObjects(target) {
+			if objective == "" {
+				locations = append(locations, snapshot.ToClientLocation(definition.Location()))
+				continue
+			}
+			if location, found := definition.ObjectiveLocation(objective); found {
+				locations = append(locations, snapshot.ToClientLocation(location))
+			}
+		}
+	}
+	for _, ref := range snapshot.FindReferences(target, objective) {
+		locations = append(locations, snapshot.ToClientLocation(messages.Location{
+			URI:   ref.Source.URI,
+			Range: ref.Value.Range,
+		}))
+	}
+	return locations, nil
+}
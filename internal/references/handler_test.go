@@ -0,0 +1,112 @@
+package references
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nobl9/nobl9-language-server/internal/files"
+	"github.com/nobl9/nobl9-language-server/internal/messages"
+	"github.com/nobl9/nobl9-language-server/internal/testutils"
+	"github.com/nobl9/nobl9-language-server/internal/workspace"
+)
+
+var testDir = filepath.Join(testutils.FindModuleRoot(), "internal", "references", "testdata")
+
+func TestHandler_Handle(t *testing.T) {
+	t.Parallel()
+
+	fileSystem := files.NewFS(nil)
+	testutils.RegisterTestFiles(t, fileSystem, testDir)
+	handler := NewHandler(fileSystem, workspace.NewSnapshots(fileSystem))
+
+	sloReferences := []messages.Location{
+		newLocation("dependants.yaml", 7, 9, 20),
+		newLocation("dependants.yaml", 16, 9, 20),
+		newLocation("dependants.yaml", 26, 16, 27),
+		newLocation("dependants.yaml", 35, 16, 27),
+		newLocation("slos.yaml", 28, 19, 30),
+	}
+
+	tests := map[string]struct {
+		uri                string
+		position           messages.Position
+		includeDeclaration bool
+		expected           any
+	}{
+		"service name with declaration": {
+			uri:                getTestFileURI("service.yaml"),
+			position:           messages.Position{Line: 3, Character: 10},
+			includeDeclaration: true,
+			expected: []messages.Location{
+				newLocation("service.yaml", 4, 8, 18),
+				newLocation("dependants.yaml", 38, 16, 26),
+				newLocation("slos.yaml", 7, 11, 21),
+				newLocation("slos.yaml", 19, 11, 21),
+			},
+		},
+		"SLO name": {
+			uri:      getTestFileURI("slos.yaml"),
+			position: messages.Position{Line: 3, Character: 10},
+			expected: sloReferences,
+		},
+		"SLO reference": {
+			uri:      getTestFileURI("slos.yaml"),
+			position: messages.Position{Line: 27, Character: 22},
+			expected: sloReferences,
+		},
+		"SLO objective name with declaration": {
+			uri:                getTestFileURI("slos.yaml"),
+			position:           messages.Position{Line: 9, Character: 13},
+			includeDeclaration: true,
+			expected: []messages.Location{
+				newLocation("slos.yaml", 10, 12, 16),
+				newLocation("dependants.yaml", 17, 19, 23),
+				newLocation("slos.yaml", 29, 25, 29),
+			},
+		},
+		"object without references": {
+			uri:      getTestFileURI("slos.yaml"),
+			position: messages.Position{Line: 33, Character: 10},
+			expected: []messages.Location{},
+		},
+		"not a reference": {
+			uri:      getTestFileURI("slos.yaml"),
+			position: messages.Position{Line: 7, Character: 22},
+			expected: nil,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			result, err := handler.Handle(context.Background(), messages.ReferenceParams{
+				TextDocumentPositionParams: messages.TextDocumentPositionParams{
+					TextDocument: messages.TextDocumentIdentifier{URI: tc.uri},
+					Position:     tc.position,
+				},
+				Context: messages.ReferenceContext{IncludeDeclaration: tc.includeDeclaration},
+			})
+			require.NoError(t, err)
+			if tc.expected == nil {
+				assert.Nil(t, result)
+				return
+			}
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func newLocation(name string, line, start, end int) messages.Location {
+	return messages.Location{
+		URI:   getTestFileURI(name),
+		Range: messages.NewLineRange(line, start, end),
+	}
+}
+
+func getTestFileURI(name string) string {
+	return filepath.Join(testDir, name)
+}
@@ -0,0 +1,200 @@
+package rename
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/nobl9/govy/pkg/rules"
+	"github.com/nobl9/nobl9-go/manifest"
+	"github.com/pkg/errors"
+
+	"github.com/nobl9/nobl9-language-server/internal/files"
+	"github.com/nobl9/nobl9-language-server/internal/messages"
+	"github.com/nobl9/nobl9-language-server/internal/objectref"
+	"github.com/nobl9/nobl9-language-server/internal/workspace"
+)
+
+type clientNotifier interface {
+	Notify(ctx context.Context, method string, params any) error
+}
+
+type objectsRepo interface {
+	GetDefaultProject(ctx context.Context) string
+	GetObject(ctx context.Context, kind manifest.Kind, name, project string) (manifest.Object, error)
+}
+
+func NewHandler(
+	files *files.FS,
+	snapshots *workspace.Snapshots,
+	repo objectsRepo,
+	notifier clientNotifier,
+) *Handler {
+	return &Handler{
+		files:       files,
+		snapshots:   snapshots,
+		objectsRepo: repo,
+		notifier:    notifier,
+	}
+}
+
+type Handler struct {
+	files       *files.FS
+	snapshots   *workspace.Snapshots
+	objectsRepo objectsRepo
+	notifier    clientNotifier
+}
+
+// HandlePrepareRename checks if the value under the cursor can be renamed.
+// Only objects' and SLO objectives' names, or references to them, can be renamed.
+func (h *Handler) HandlePrepareRename(ctx context.Context, params messages.PrepareRenameParams) (any, error) {
+	target, err := h.findTarget(ctx, params.TextDocumentPositionParams)
+	if err != nil || target == nil {
+		return nil, err
+	}
+	return messages.PrepareRenameResult{
+		Range:       target.snapshot.ToClientRange(params.TextDocument.URI, target.value.Range),
+		Placeholder: target.value.Value,
+	}, nil
+}
+
+// HandleRename renames the object (or SLO objective) under the cursor
+// along with all the references to it across the workspace.
+func (h *Handler) HandleRename(ctx context.Context, params messages.RenameParams) (any, error) {
+	target, err := h.findTarget(ctx, params.TextDocumentPositionParams)
+	if err != nil || target == nil {
+		return nil, err
+	}
+	if params.NewName == "" {
+		return nil, errors.New("new name must not be empty")
+	}
+	// Both objects' and SLO objectives' names must be valid RFC-1123 labels.
+	if rules.StringDNSLabel().Validate(params.NewName) != nil {
+		return nil, errors.Errorf("invalid name %q, it must be at most 63 characters long, "+
+			"consist of lower case alphanumeric characters or '-' and start and end with an alphanumeric character",
+			params.NewName)
+	}
+	if err = target.checkConflicts(params.NewName); err != nil {
+		return nil, err
+	}
+
+	changes := make(map[files.URI][]messages.TextEdit)
+	addEdit := func(location messages.Location) {
+		location = target.snapshot.ToClientLocation(location)
+		changes[location.URI] = append(changes[location.URI], messages.TextEdit{
+			Range:   location.Range,
+			NewText: params.NewName,
+		})
+	}
+	for _, definition := range target.snapshot.FindObjects(target.id) {
+		if target.objective == "" {
+			if v := definition.GetValue("$.metadata.name"); v != nil {
+				addEdit(messages.Location{URI: definition.URI, Range: v.Range})
+			}
+			continue
+		}
+		if location, found := definition.ObjectiveLocation(target.objective); found {
+			addEdit(location)
+		}
+	}
+	for _, ref := range target.snapshot.FindReferences(target.id, target.objective) {
+		addEdit(messages.Location{URI: ref.Source.URI, Range: ref.Value.Range})
+	}
+
+	if target.objective == "" {
+		h.warnIfExistsRemotely(ctx, target.id)
+	}
+	return messages.WorkspaceEdit{Changes: changes}, nil
+}
+
+// renameTarget is the object (or SLO objective) which is being renamed.
+type renameTarget struct {
+	snapshot  *workspace.Snapshot
+	value     *workspace.Value
+	id        workspace.ObjectID
+	objective string
+}
+
+func (h *Handler) findTarget(
+	ctx context.Context,
+	params messages.TextDocumentPositionParams,
+) (*renameTarget, error) {
+	file, err := h.files.GetFile(params.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+	ctx = file.AddToLogContext(ctx)
+	if file.Skip {
+		slog.DebugContext(ctx, "skipping file")
+		return nil, nil
+	}
+
+	position := file.GetPositionMapper().FromClientPosition(params.Position)
+	snapshot := h.snapshots.Get()
+	object, value := snapshot.FindValue(file.URI, position)
+	if object == nil || value == nil {
+		slog.DebugContext(ctx, "no value found", slog.Any("position", position))
+		return nil, nil
+	}
+	id, objective, ok := workspace.ResolveTarget(object, value)
+	if !ok {
+		slog.DebugContext(ctx, "value neither defines nor references an object", slog.String("path", value.Path))
+		return nil, nil
+	}
+	return &renameTarget{
+		snapshot:  snapshot,
+		value:     value,
+		id:        id,
+		objective: objective,
+	}, nil
+}
+
+// checkConflicts verifies that there's no object (or SLO objective) with the new name already defined.
+func (r *renameTarget) checkConflicts(newName string) error {
+	if r.objective == "" {
+		newID := workspace.NewObjectID(r.id.Kind, newName, r.id.Project)
+		if len(r.snapshot.FindObjects(newID)) > 0 {
+			return errors.Errorf("%s %s already exists", r.id.Kind, formatName(newID))
+		}
+		return nil
+	}
+	for _, slo := range r.snapshot.FindObjects(r.id) {
+		if _, found := slo.ObjectiveLocation(newName); found {
+			return errors.Errorf("%s %s already has an objective named %q", r.id.Kind, formatName(r.id), newName)
+		}
+	}
+	return nil
+}
+
+// warnIfExistsRemotely notifies the user if the renamed object already exists in the Nobl9 platform.
+// Objects cannot be renamed in Nobl9, applying the renamed object will create a new one instead.
+// Project scoped objects without a project are looked up in the default project, just like when they're applied.
+func (h *Handler) warnIfExistsRemotely(ctx context.Context, id workspace.ObjectID) {
+	if id.Project == "" && objectref.IsProjectScoped(id.Kind) {
+		id.Project = h.objectsRepo.GetDefaultProject(ctx)
+	}
+	object, err := h.objectsRepo.GetObject(ctx, id.Kind, id.Name, id.Project)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to fetch renamed object", slog.Any("error", err))
+		return
+	}
+	if object == nil {
+		return
+	}
+	message := messages.ShowMessageParams{
+		Type: messages.MessageTypeWarning,
+		Message: fmt.Sprintf("%s %s already exists in Nobl9. Objects cannot be renamed, "+
+			"applying the changes will create a new %s and the existing one has to be deleted separately.",
+			id.Kind, formatName(id), id.Kind),
+	}
+	if err = h.notifier.Notify(ctx, messages.ShowMessageMethod, message); err != nil {
+		slog.ErrorContext(ctx, "failed to notify about existing object", slog.Any("error", err))
+	}
+}
+
+func formatName(id workspace.ObjectID) string {
+	if id.Project == "" {
+		return fmt.Sprintf("%q", id.Name)
+	}
+	return fmt.Sprintf("%q in project %q", id.Name, id.Project)
+}
@@ -0,0 +1,273 @@
+package rename
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/nobl9/nobl9-go/manifest"
+	v1alphaService "github.com/nobl9/nobl9-go/manifest/v1alpha/service"
+	v1alphaSLO "github.com/nobl9/nobl9-go/manifest/v1alpha/slo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nobl9/nobl9-language-server/internal/files"
+	"github.com/nobl9/nobl9-language-server/internal/messages"
+	"github.com/nobl9/nobl9-language-server/internal/testutils"
+	"github.com/nobl9/nobl9-language-server/internal/workspace"
+)
+
+var testDir = filepath.Join(testutils.FindModuleRoot(), "internal", "rename", "testdata")
+
+func TestHandler_HandlePrepareRename(t *testing.T) {
+	t.Parallel()
+
+	fileSystem := files.NewFS(nil)
+	testutils.RegisterTestFiles(t, fileSystem, testDir)
+	handler := NewHandler(fileSystem, workspace.NewSnapshots(fileSystem), mockObjectsRepo{}, &mockNotifier{})
+
+	tests := map[string]struct {
+		uri      string
+		position messages.Position
+		expected any
+	}{
+		"object name": {
+			uri:      getTestFileURI("slos.yaml"),
+			position: messages.Position{Line: 3, Character: 10},
+			expected: messages.PrepareRenameResult{
+				Range:       messages.NewLineRange(4, 8, 19),
+				Placeholder: "api-latency",
+			},
+		},
+		"quoted object name": {
+			uri:      getTestFileURI("services.yaml"),
+			position: messages.Position{Line: 17, Character: 10},
+			expected: messages.PrepareRenameResult{
+				Range:       messages.NewLineRange(18, 9, 12),
+				Placeholder: "web",
+			},
+		},
+		"reference": {
+			uri:      getTestFileURI("dependants.yaml"),
+			position: messages.Position{Line: 6, Character: 12},
+			expected: messages.PrepareRenameResult{
+				Range:       messages.NewLineRange(7, 9, 20),
+				Placeholder: "api-latency",
+			},
+		},
+		"not a name nor a reference": {
+			uri:      getTestFileURI("slos.yaml"),
+			position: messages.Position{Line: 7, Character: 22},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			result, err := handler.HandlePrepareRename(context.Background(), messages.PrepareRenameParams{
+				TextDocumentPositionParams: messages.TextDocumentPositionParams{
+					TextDocument: messages.TextDocumentIdentifier{URI: tc.uri},
+					Position:     tc.position,
+				},
+			})
+			require.NoError(t, err)
+			if tc.expected == nil {
+				assert.Nil(t, result)
+				return
+			}
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestHandler_HandleRename(t *testing.T) {
+	t.Parallel()
+
+	fileSystem := files.NewFS(nil)
+	testutils.RegisterTestFiles(t, fileSystem, testDir)
+
+	tests := map[string]struct {
+		uri             string
+		position        messages.Position
+		newName         string
+		expected        any
+		expectedMessage *messages.ShowMessageParams
+		err             string
+	}{
+		"service in its project only": {
+			uri:      getTestFileURI("services.yaml"),
+			position: messages.Position{Line: 3, Character: 10},
+			newName:  "new-name",
+			expected: messages.WorkspaceEdit{
+				Changes: map[files.URI][]messages.TextEdit{
+					getTestFileURI("services.yaml"): {
+						newTextEdit(4, 8, 18, "new-name"),
+					},
+					getTestFileURI("dependants.yaml"): {
+						newTextEdit(38, 16, 26, "new-name"),
+					},
+					getTestFileURI("slos.yaml"): {
+						newTextEdit(7, 11, 21, "new-name"),
+						newTextEdit(19, 11, 21, "new-name"),
+					},
+				},
+			},
+		},
+		"SLO from reference, exists remotely": {
+			uri:      getTestFileURI("dependants.yaml"),
+			position: messages.Position{Line: 6, Character: 12},
+			newName:  "new-name",
+			expected: messages.WorkspaceEdit{
+				Changes: map[files.URI][]messages.TextEdit{
+					getTestFileURI("slos.yaml"): {
+						newTextEdit(4, 8, 19, "new-name"),
+						newTextEdit(28, 19, 30, "new-name"),
+					},
+					getTestFileURI("dependants.yaml"): {
+						newTextEdit(7, 9, 20, "new-name"),
+						newTextEdit(16, 9, 20, "new-name"),
+						newTextEdit(26, 16, 27, "new-name"),
+						newTextEdit(35, 16, 27, "new-name"),
+					},
+				},
+			},
+			expectedMessage: &messages.ShowMessageParams{
+				Type: messages.MessageTypeWarning,
+				Message: `SLO "api-latency" in project "team-a" already exists in Nobl9. ` +
+					`Objects cannot be renamed, applying the changes will create a new SLO ` +
+					`and the existing one has to be deleted separately.`,
+			},
+		},
+		"SLO objective": {
+			uri:      getTestFileURI("slos.yaml"),
+			position: messages.Position{Line: 9, Character: 13},
+			newName:  "new-name",
+			expected: messages.WorkspaceEdit{
+				Changes: map[files.URI][]messages.TextEdit{
+					getTestFileURI("slos.yaml"): {
+						newTextEdit(10, 12, 16, "new-name"),
+						newTextEdit(29, 25, 29, "new-name"),
+					},
+					getTestFileURI("dependants.yaml"): {
+						newTextEdit(17, 19, 23, "new-name"),
+					},
+				},
+			},
+		},
+		"object already exists": {
+			uri:      getTestFileURI("services.yaml"),
+			position: messages.Position{Line: 3, Character: 10},
+			newName:  "web",
+			err:      `Service "web" in project "team-a" already exists`,
+		},
+		"objective already exists": {
+			uri:      getTestFileURI("slos.yaml"),
+			position: messages.Position{Line: 21, Character: 13},
+			newName:  "composite",
+			err:      `SLO "composite" in project "team-a" already has an objective named "composite"`,
+		},
+		"empty name": {
+			uri:      getTestFileURI("services.yaml"),
+			position: messages.Position{Line: 3, Character: 10},
+			err:      "new name must not be empty",
+		},
+		"invalid name": {
+			uri:      getTestFileURI("services.yaml"),
+			position: messages.Position{Line: 3, Character: 10},
+			newName:  "New_Name",
+			err: `invalid name "New_Name", it must be at most 63 characters long, ` +
+				`consist of lower case alphanumeric characters or '-' and start and end with an alphanumeric character`,
+		},
+		"object without project exists only in another project": {
+			uri:      getTestFileURI("services.yaml"),
+			position: messages.Position{Line: 24, Character: 10},
+			newName:  "new-name",
+			expected: messages.WorkspaceEdit{
+				Changes: map[files.URI][]messages.TextEdit{
+					getTestFileURI("services.yaml"): {newTextEdit(25, 8, 10, "new-name")},
+				},
+			},
+		},
+		"not a name nor a reference": {
+			uri:      getTestFileURI("slos.yaml"),
+			position: messages.Position{Line: 7, Character: 22},
+			newName:  "new-name",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			notifier := &mockNotifier{}
+			handler := NewHandler(fileSystem, workspace.NewSnapshots(fileSystem), mockObjectsRepo{}, notifier)
+			result, err := handler.HandleRename(context.Background(), messages.RenameParams{
+				TextDocumentPositionParams: messages.TextDocumentPositionParams{
+					TextDocument: messages.TextDocumentIdentifier{URI: tc.uri},
+					Position:     tc.position,
+				},
+				NewName: tc.newName,
+			})
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			if tc.expected == nil {
+				assert.Nil(t, result)
+			} else {
+				assert.Equal(t, tc.expected, result)
+			}
+			if tc.expectedMessage == nil {
+				assert.Empty(t, notifier.messages)
+			} else {
+				assert.Equal(t, []messages.ShowMessageParams{*tc.expectedMessage}, notifier.messages)
+			}
+		})
+	}
+}
+
+func newTextEdit(line, start, end int, text string) messages.TextEdit {
+	return messages.TextEdit{
+		Range:   messages.NewLineRange(line, start, end),
+		NewText: text,
+	}
+}
+
+func getTestFileURI(name string) string {
+	return filepath.Join(testDir, name)
+}
+
+type mockObjectsRepo struct{}
+
+func (m mockObjectsRepo) GetDefaultProject(context.Context) string { return "team-a" }
+
+func (m mockObjectsRepo) GetObject(
+	_ context.Context,
+	kind manifest.Kind,
+	name, project string,
+) (manifest.Object, error) {
+	switch {
+	case kind == manifest.KindSLO && name == "api-latency" && project == "team-a":
+		return v1alphaSLO.New(v1alphaSLO.Metadata{Name: name, Project: project}, v1alphaSLO.Spec{}), nil
+	// Empty project matches the objects in any project.
+	case kind == manifest.KindService && name == "db" && (project == "" || project == "team-b"):
+		return v1alphaService.New(v1alphaService.Metadata{Name: name, Project: "team-b"}, v1alphaService.Spec{}), nil
+	default:
+		return nil, nil
+	}
+}
+
+type mockNotifier struct {
+	mu       sync.Mutex
+	messages []messages.ShowMessageParams
+}
+
+func (m *mockNotifier) Notify(_ context.Context, method string, params any) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if method == messages.ShowMessageMethod {
+		m.messages = append(m.messages, params.(messages.ShowMessageParams))
+	}
+	return nil
+}
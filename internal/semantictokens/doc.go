@@ -0,0 +1,3 @@
+// Package semantictokens provides semantic highlighting of Nobl9 objects,
+// like object kinds, names and references to other objects.
+package semantictokens
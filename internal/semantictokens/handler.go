@@ -0,0 +1,66 @@
+package semantictokens
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/nobl9/nobl9-language-server/internal/files"
+	"github.com/nobl9/nobl9-language-server/internal/messages"
+	"github.com/nobl9/nobl9-language-server/internal/workspace"
+)
+
+func NewHandler(files *files.FS, docs docsProvider) *Handler {
+	return &Handler{
+		files: files,
+		docs:  docs,
+	}
+}
+
+type Handler struct {
+	files *files.FS
+	docs  docsProvider
+}
+
+// HandleFull returns semantic tokens for the whole document.
+func (h *Handler) HandleFull(ctx context.Context, params messages.SemanticTokensParams) (any, error) {
+	tokens, err := h.getTokens(ctx, params.TextDocument.URI)
+	if err != nil || tokens == nil {
+		return nil, err
+	}
+	return &messages.SemanticTokens{Data: encodeTokens(tokens)}, nil
+}
+
+// HandleRange returns semantic tokens which overlap with the requested range.
+func (h *Handler) HandleRange(ctx context.Context, params messages.SemanticTokensRangeParams) (any, error) {
+	tokens, err := h.getTokens(ctx, params.TextDocument.URI)
+	if err != nil || tokens == nil {
+		return nil, err
+	}
+	inRange := make([]semanticToken, 0, len(tokens))
+	for _, tk := range tokens {
+		if tk.isInRange(params.Range) {
+			inRange = append(inRange, tk)
+		}
+	}
+	return &messages.SemanticTokens{Data: encodeTokens(inRange)}, nil
+}
+
+// getTokens returns the file's tokens with their positions converted to the client's encoding.
+func (h *Handler) getTokens(ctx context.Context, uri files.URI) ([]semanticToken, error) {
+	file, err := h.files.GetFile(uri)
+	if err != nil {
+		return nil, err
+	}
+	ctx = file.AddToLogContext(ctx)
+	if file.Skip {
+		slog.DebugContext(ctx, "skipping file")
+		return nil, nil
+	}
+	objects := workspace.NewSnapshot([]*files.File{file}).FileObjects(file.URI)
+	tokens := buildTokens(objects, h.docs)
+	mapper := file.GetPositionMapper()
+	for i := range tokens {
+		tokens[i] = tokens[i].toClient(mapper)
+	}
+	return tokens, nil
+}
@@ -0,0 +1,144 @@
+package semantictokens
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nobl9/nobl9-language-server/internal/files"
+	"github.com/nobl9/nobl9-language-server/internal/messages"
+	"github.com/nobl9/nobl9-language-server/internal/sdkdocs"
+	"github.com/nobl9/nobl9-language-server/internal/testutils"
+)
+
+var testDir = filepath.Join(testutils.FindModuleRoot(), "internal", "semantictokens", "testdata")
+
+// decodedToken is a human-readable representation of a single encoded token.
+type decodedToken struct {
+	Line      int
+	Character int
+	Length    int
+	Type      string
+	Modifiers []string
+}
+
+func TestHandler_HandleFull(t *testing.T) {
+	t.Parallel()
+
+	handler := newTestHandler(t)
+
+	result, err := handler.HandleFull(context.Background(), messages.SemanticTokensParams{
+		TextDocument: messages.TextDocumentIdentifier{URI: getTestFileURI("slo.yaml")},
+	})
+	require.NoError(t, err)
+	require.IsType(t, &messages.SemanticTokens{}, result)
+	assert.Equal(t, []decodedToken{
+		{Line: 0, Character: 12, Length: 10, Type: "enumMember"},
+		{Line: 1, Character: 6, Length: 3, Type: "type"},
+		{Line: 3, Character: 8, Length: 11, Type: "class", Modifiers: []string{"declaration"}},
+		{Line: 4, Character: 11, Length: 7, Type: "class"},
+		{Line: 7, Character: 11, Length: 10, Type: "class"},
+		{Line: 8, Character: 19, Length: 11, Type: "enumMember"},
+		{Line: 10, Character: 7, Length: 9, Type: "class"},
+		{Line: 13, Character: 12, Length: 9, Type: "class"},
+		{Line: 16, Character: 8, Length: 11, Type: "property", Modifiers: []string{"deprecated"}},
+		{Line: 16, Character: 21, Length: 3, Type: "enumMember"},
+		{Line: 18, Character: 12, Length: 4, Type: "class", Modifiers: []string{"declaration"}},
+		{Line: 19, Character: 10, Length: 3, Type: "enumMember"},
+		{Line: 22, Character: 12, Length: 10, Type: "enumMember"},
+		{Line: 23, Character: 6, Length: 11, Type: "type"},
+		{Line: 25, Character: 8, Length: 7, Type: "class", Modifiers: []string{"declaration"}},
+		{Line: 26, Character: 11, Length: 7, Type: "class"},
+		{Line: 29, Character: 4, Length: 3, Type: "property", Modifiers: []string{"secret"}},
+	}, decodeTokens(result.(*messages.SemanticTokens).Data))
+}
+
+func TestHandler_HandleRange(t *testing.T) {
+	t.Parallel()
+
+	handler := newTestHandler(t)
+
+	result, err := handler.HandleRange(context.Background(), messages.SemanticTokensRangeParams{
+		TextDocument: messages.TextDocumentIdentifier{URI: getTestFileURI("slo.yaml")},
+		Range:        messages.NewRange(8, 15, 11, 10),
+	})
+	require.NoError(t, err)
+	require.IsType(t, &messages.SemanticTokens{}, result)
+	assert.Equal(t, []decodedToken{
+		{Line: 7, Character: 11, Length: 10, Type: "class"},
+		{Line: 8, Character: 19, Length: 11, Type: "enumMember"},
+		{Line: 10, Character: 7, Length: 9, Type: "class"},
+	}, decodeTokens(result.(*messages.SemanticTokens).Data))
+}
+
+func TestHandler_HandleFull_PositionEncoding(t *testing.T) {
+	t.Parallel()
+
+	tests := map[messages.PositionEncodingKind]int{
+		messages.PositionEncodingUTF8:  47,
+		messages.PositionEncodingUTF16: 43,
+		messages.PositionEncodingUTF32: 42,
+	}
+	for encoding, character := range tests {
+		t.Run(string(encoding), func(t *testing.T) {
+			t.Parallel()
+
+			handler := newTestHandler(t)
+			handler.files.SetPositionEncoding(encoding)
+
+			result, err := handler.HandleFull(context.Background(), messages.SemanticTokensParams{
+				TextDocument: messages.TextDocumentIdentifier{URI: getTestFileURI("multi-byte.yaml")},
+			})
+			require.NoError(t, err)
+			require.IsType(t, &messages.SemanticTokens{}, result)
+			assert.Contains(t, decodeTokens(result.(*messages.SemanticTokens).Data), decodedToken{
+				Line:      2,
+				Character: character,
+				Length:    6,
+				Type:      "class",
+				Modifiers: []string{"declaration"},
+			})
+		})
+	}
+}
+
+func newTestHandler(t *testing.T) *Handler {
+	t.Helper()
+	fileSystem := files.NewFS(nil)
+	testutils.RegisterTestFiles(t, fileSystem, testDir)
+	docs, err := sdkdocs.New()
+	require.NoError(t, err)
+	return NewHandler(fileSystem, docs)
+}
+
+func decodeTokens(data []int) []decodedToken {
+	tokens := make([]decodedToken, 0, len(data)/5)
+	var line, character int
+	for i := 0; i+4 < len(data); i += 5 {
+		if data[i] > 0 {
+			character = 0
+		}
+		line += data[i]
+		character += data[i+1]
+		tk := decodedToken{
+			Line:      line,
+			Character: character,
+			Length:    data[i+2],
+			Type:      tokenTypes[data[i+3]],
+		}
+		for j, name := range tokenModifierNames {
+			if data[i+4]&(1<<j) != 0 {
+				tk.Modifiers = append(tk.Modifiers, name)
+			}
+		}
+		tokens = append(tokens, tk)
+	}
+	return tokens
+}
+
+func getTestFileURI(name string) string {
+	return filepath.Join(testDir, name)
+}
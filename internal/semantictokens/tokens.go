@@ -0,0 +1,213 @@
+package semantictokens
+
+import (
+	"cmp"
+	"slices"
+	"unicode/utf8"
+
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/token"
+	"github.com/nobl9/nobl9-go/manifest"
+
+	"github.com/nobl9/nobl9-language-server/internal/files"
+	"github.com/nobl9/nobl9-language-server/internal/messages"
+	"github.com/nobl9/nobl9-language-server/internal/sdkdocs"
+	"github.com/nobl9/nobl9-language-server/internal/workspace"
+)
+
+type tokenType int
+
+// Token types are the indexes of [tokenTypes].
+const (
+	// tokenTypeKind is used for object kind values.
+	tokenTypeKind tokenType = iota
+	// tokenTypeObject is used for object names, SLO objective names and references to them.
+	tokenTypeObject
+	// tokenTypeEnum is used for values which are one of the predefined values.
+	tokenTypeEnum
+	// tokenTypeProperty is used for keys which have at least one modifier.
+	tokenTypeProperty
+)
+
+var tokenTypes = []string{"type", "class", "enumMember", "property"}
+
+type tokenModifiers int
+
+// Token modifiers are the bit positions of [tokenModifierNames].
+const (
+	// tokenModifierDeclaration is used for object and SLO objective names.
+	tokenModifierDeclaration tokenModifiers = 1 << iota
+	// tokenModifierDeprecated is used for deprecated keys.
+	tokenModifierDeprecated
+	// tokenModifierSecret is used for keys which hold secret values.
+	tokenModifierSecret
+)
+
+var tokenModifierNames = []string{"declaration", "deprecated", "secret"}
+
+// GetLegend returns the [messages.SemanticTokensLegend] which describes the tokens returned by the server.
+func GetLegend() messages.SemanticTokensLegend {
+	return messages.SemanticTokensLegend{
+		TokenTypes:     tokenTypes,
+		TokenModifiers: tokenModifierNames,
+	}
+}
+
+type docsProvider interface {
+	GetProperty(kind manifest.Kind, path string) *sdkdocs.PropertyDoc
+}
+
+// semanticToken is a single token positioned within a line, line and character are 0-based.
+type semanticToken struct {
+	line      int
+	character int
+	length    int
+	typ       tokenType
+	modifiers tokenModifiers
+}
+
+// buildTokens creates sorted [semanticToken] list for all the objects.
+func buildTokens(objects []*workspace.Object, docs docsProvider) []semanticToken {
+	tokens := make([]semanticToken, 0)
+	for _, object := range objects {
+		walkKeys(object.Node.Node.Node, "$", func(key *token.Token, path string) {
+			modifiers := getKeyModifiers(docs.GetProperty(object.ID.Kind, path))
+			if modifiers == 0 {
+				return
+			}
+			character := key.Position.Column - 1
+			if key.Type == token.DoubleQuoteType || key.Type == token.SingleQuoteType {
+				character++
+			}
+			tokens = append(tokens, semanticToken{
+				line:      key.Position.Line - 1,
+				character: character,
+				length:    utf8.RuneCountInString(key.Value),
+				typ:       tokenTypeProperty,
+				modifiers: modifiers,
+			})
+		})
+		for _, value := range object.Values {
+			typ, modifiers, ok := classifyValue(object, value, docs)
+			if !ok {
+				continue
+			}
+			tokens = append(tokens, semanticToken{
+				line:      value.Range.Start.Line,
+				character: value.Range.Start.Character,
+				length:    value.Range.End.Character - value.Range.Start.Character,
+				typ:       typ,
+				modifiers: modifiers,
+			})
+		}
+	}
+	slices.SortFunc(tokens, func(t1, t2 semanticToken) int {
+		if t1.line != t2.line {
+			return cmp.Compare(t1.line, t2.line)
+		}
+		return cmp.Compare(t1.character, t2.character)
+	})
+	return tokens
+}
+
+// classifyValue returns the token type and modifiers for the [workspace.Value].
+// If the value has no special meaning, ok is false.
+func classifyValue(
+	object *workspace.Object,
+	value *workspace.Value,
+	docs docsProvider,
+) (typ tokenType, modifiers tokenModifiers, ok bool) {
+	switch {
+	case value.Value == "":
+		return 0, 0, false
+	case value.Path == "$.kind":
+		return tokenTypeKind, 0, true
+	case value.Path == "$.metadata.name", object.IsObjectiveName(value):
+		return tokenTypeObject, tokenModifierDeclaration, true
+	case workspace.ResolveReference(object, value) != nil:
+		return tokenTypeObject, 0, true
+	}
+	if prop := docs.GetProperty(object.ID.Kind, value.GeneralizedPath); prop != nil &&
+		slices.Contains(prop.Values, value.Value) {
+		return tokenTypeEnum, 0, true
+	}
+	return 0, 0, false
+}
+
+func getKeyModifiers(prop *sdkdocs.PropertyDoc) tokenModifiers {
+	var modifiers tokenModifiers
+	if prop == nil {
+		return modifiers
+	}
+	if prop.IsDeprecated {
+		modifiers |= tokenModifierDeprecated
+	}
+	if prop.IsSecret {
+		modifiers |= tokenModifierSecret
+	}
+	return modifiers
+}
+
+// walkKeys traverses the [ast.Node] and calls the provided function for every mapping key
+// along with its generalized path.
+func walkKeys(node ast.Node, path string, fn func(key *token.Token, path string)) {
+	switch v := node.(type) {
+	case *ast.MappingNode:
+		for _, value := range v.Values {
+			walkKeys(value, path, fn)
+		}
+	case *ast.MappingValueNode:
+		key := v.Key.GetToken()
+		if key == nil || key.Position == nil {
+			return
+		}
+		keyPath := path + "." + key.Value
+		fn(key, keyPath)
+		walkKeys(v.Value, keyPath, fn)
+	case *ast.SequenceNode:
+		for _, value := range v.Values {
+			walkKeys(value, path+"[*]", fn)
+		}
+	case *ast.TagNode:
+		walkKeys(v.Value, path, fn)
+	}
+}
+
+// encodeTokens encodes the sorted tokens in the relative format defined by LSP.
+func encodeTokens(tokens []semanticToken) []int {
+	data := make([]int, 0, len(tokens)*5)
+	var prevLine, prevCharacter int
+	for _, tk := range tokens {
+		deltaLine := tk.line - prevLine
+		deltaCharacter := tk.character
+		if deltaLine == 0 {
+			deltaCharacter -= prevCharacter
+		}
+		data = append(data, deltaLine, deltaCharacter, tk.length, int(tk.typ), int(tk.modifiers))
+		prevLine, prevCharacter = tk.line, tk.character
+	}
+	return data
+}
+
+// toClient converts the token's position and length to the client's encoding.
+func (t semanticToken) toClient(mapper *files.PositionMapper) semanticToken {
+	start := mapper.ToClientPosition(messages.Position{Line: t.line, Character: t.character})
+	end := mapper.ToClientPosition(messages.Position{Line: t.line, Character: t.character + t.length})
+	t.character = start.Character
+	t.length = end.Character - start.Character
+	return t
+}
+
+// isInRange checks if the token overlaps with the [messages.Range].
+func (t semanticToken) isInRange(rng messages.Range) bool {
+	switch {
+	case t.line < rng.Start.Line || t.line > rng.End.Line:
+		return false
+	case t.line == rng.Start.Line && t.character+t.length <= rng.Start.Character:
+		return false
+	case t.line == rng.End.Line && t.character >= rng.End.Character:
+		return false
+	default:
+		return true
+	}
+}
@@ -0,0 +1,44 @@
+package server
+
+import (
+	"sync"
+
+	"github.com/nobl9/nobl9-language-server/internal/messages"
+)
+
+func newFileEventsQueue() *fileEventsQueue {
+	return &fileEventsQueue{ready: make(chan struct{}, 1)}
+}
+
+// fileEventsQueue collects the watched files changes reported by the client
+// until they're applied to the workspace index in the background.
+// The events are kept in the order they were received in.
+type fileEventsQueue struct {
+	events []messages.FileEvent
+	// ready receives a value whenever new events are pushed.
+	ready chan struct{}
+	mu    sync.Mutex
+}
+
+// Push adds the events to the queue without blocking.
+func (q *fileEventsQueue) Push(events []messages.FileEvent) {
+	if len(events) == 0 {
+		return
+	}
+	q.mu.Lock()
+	q.events = append(q.events, events...)
+	q.mu.Unlock()
+	select {
+	case q.ready <- struct{}{}:
+	default:
+	}
+}
+
+// Pop removes and returns all the queued events.
+func (q *fileEventsQueue) Pop() []messages.FileEvent {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	events := q.events
+	q.events = nil
+	return events
+}
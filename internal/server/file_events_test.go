@@ -0,0 +1,27 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nobl9/nobl9-language-server/internal/messages"
+)
+
+func TestFileEventsQueue(t *testing.T) {
+	queue := newFileEventsQueue()
+	assert.Empty(t, queue.Pop())
+
+	queue.Push([]messages.FileEvent{{URI: "file:///dir", Type: messages.FileChangeTypeDeleted}})
+	queue.Push(nil)
+	queue.Push([]messages.FileEvent{{URI: "file:///dir/foo.yaml", Type: messages.FileChangeTypeCreated}})
+
+	require.Len(t, queue.ready, 1)
+	<-queue.ready
+	assert.Equal(t, []messages.FileEvent{
+		{URI: "file:///dir", Type: messages.FileChangeTypeDeleted},
+		{URI: "file:///dir/foo.yaml", Type: messages.FileChangeTypeCreated},
+	}, queue.Pop())
+	assert.Empty(t, queue.Pop())
+}
@@ -1,32 +1,71 @@
 package server
 
 import (
+	"context"
+	"time"
+
 	"github.com/pkg/errors"
 
+	"github.com/nobl9/nobl9-language-server/internal/capabilities"
 	"github.com/nobl9/nobl9-language-server/internal/codeactions"
 	"github.com/nobl9/nobl9-language-server/internal/completion"
+	"github.com/nobl9/nobl9-language-server/internal/config"
+	"github.com/nobl9/nobl9-language-server/internal/definition"
 	"github.com/nobl9/nobl9-language-server/internal/diagnostics"
 	"github.com/nobl9/nobl9-language-server/internal/files"
+	"github.com/nobl9/nobl9-language-server/internal/folding"
+	"github.com/nobl9/nobl9-language-server/internal/formatting"
 	"github.com/nobl9/nobl9-language-server/internal/hover"
+	"github.com/nobl9/nobl9-language-server/internal/inlayhints"
 	"github.com/nobl9/nobl9-language-server/internal/messages"
 	"github.com/nobl9/nobl9-language-server/internal/nobl9repo"
+	"github.com/nobl9/nobl9-language-server/internal/progress"
+	"github.com/nobl9/nobl9-language-server/internal/references"
+	"github.com/nobl9/nobl9-language-server/internal/rename"
 	"github.com/nobl9/nobl9-language-server/internal/sdkdocs"
+	"github.com/nobl9/nobl9-language-server/internal/semantictokens"
+	"github.com/nobl9/nobl9-language-server/internal/symbols"
+	"github.com/nobl9/nobl9-language-server/internal/workspace"
 )
 
 type handlersRegistry struct {
-	Diagnostics    paramsOnlyHandlerFunc[messages.TextDocumentItem]
-	Completion     paramsOnlyHandlerFunc[messages.CompletionParams]
-	Hover          paramsOnlyHandlerFunc[messages.HoverParams]
-	CodeAction     paramsOnlyHandlerFunc[messages.CodeActionParams]
-	ExecuteCommand paramsOnlyHandlerFunc[messages.ExecuteCommandParams]
+	Diagnostics         paramsOnlyHandlerFunc[messages.TextDocumentItem]
+	DocumentDiagnostic  paramsOnlyHandlerFunc[messages.DocumentDiagnosticParams]
+	WorkspaceDiagnostic paramsOnlyHandlerFunc[messages.WorkspaceDiagnosticParams]
+	Completion          paramsOnlyHandlerFunc[messages.CompletionParams]
+	Hover               paramsOnlyHandlerFunc[messages.HoverParams]
+	CodeAction          paramsOnlyHandlerFunc[messages.CodeActionParams]
+	ExecuteCommand      paramsOnlyHandlerFunc[messages.ExecuteCommandParams]
+	Definition          paramsOnlyHandlerFunc[messages.DocumentDefinitionParams]
+	References          paramsOnlyHandlerFunc[messages.ReferenceParams]
+	DocumentSymbol      paramsOnlyHandlerFunc[messages.DocumentSymbolParams]
+	WorkspaceSymbol     paramsOnlyHandlerFunc[messages.WorkspaceSymbolParams]
+	Rename              paramsOnlyHandlerFunc[messages.RenameParams]
+	PrepareRename       paramsOnlyHandlerFunc[messages.PrepareRenameParams]
+	Formatting          paramsOnlyHandlerFunc[messages.DocumentFormattingParams]
+	RangeFormatting     paramsOnlyHandlerFunc[messages.DocumentRangeFormattingParams]
+	FoldingRange        paramsOnlyHandlerFunc[messages.FoldingRangeParams]
+	SemanticTokens      paramsOnlyHandlerFunc[messages.SemanticTokensParams]
+	SemanticRange       paramsOnlyHandlerFunc[messages.SemanticTokensRangeParams]
+	InlayHint           paramsOnlyHandlerFunc[messages.InlayHintParams]
+	// WarmUpCache is not a handler, it prefetches the Nobl9 resources once the connection is initialized.
+	WarmUpCache func(ctx context.Context)
+	// SetCacheTTL and SetDiagnosticsSettings apply the client's [config.Settings].
+	SetCacheTTL            func(ttl time.Duration)
+	SetDiagnosticsSettings func(settings config.DiagnosticsSettings)
+	// InvalidateDiagnostics marks the pulled diagnostics as outdated, it is called whenever the settings change.
+	InvalidateDiagnostics func()
 }
 
 func newHandlersRegistry(
 	filesystem *files.FS,
 	notifier *rpcConnectionNotifier,
+	progressReporter *progress.Reporter,
+	clientCapabilities *capabilities.Store,
+	scopes fileScopes,
 ) (*handlersRegistry, error) {
 	// Common dependencies.
-	objectsRepo, err := nobl9repo.NewRepo()
+	objectsRepo, err := nobl9repo.NewRepo(progressReporter)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to setup Nobl9 API repository")
 	}
@@ -35,27 +74,70 @@ func newHandlersRegistry(
 		return nil, errors.Wrap(err, "failed to setup SDK docs provider")
 	}
 
+	snapshots := workspace.NewSnapshots(filesystem)
+	objectsResolver := workspace.NewObjectsResolver(filesystem, objectsRepo, scopes)
+
 	// Diagnostics.
-	diagnosticsProvider := diagnostics.NewProvider(sdkDocs, objectsRepo)
-	diagnosticsHandler := diagnostics.NewHandler(filesystem, diagnosticsProvider)
+	diagnosticsProvider := diagnostics.NewProvider(sdkDocs, objectsRepo, objectsResolver, scopes)
+	diagnosticsHandler := diagnostics.NewHandler(filesystem, diagnosticsProvider, progressReporter, clientCapabilities)
 	// Completion.
 	completionHandler := completion.NewHandler(filesystem,
 		completion.NewValuesCompletionProvider(sdkDocs),
 		completion.NewKeysCompletionProvider(sdkDocs),
-		completion.NewReferencesCompletionProvider(objectsRepo),
-		completion.NewSnippetsProvider(),
+		completion.NewReferencesCompletionProvider(objectsRepo, objectsResolver),
+		completion.NewSnippetsProvider(clientCapabilities),
 	)
 	// Hover.
-	hoverProvider := hover.NewProvider(sdkDocs, objectsRepo)
-	hoverHandler := hover.NewHandler(filesystem, hoverProvider)
+	hoverProvider := hover.NewProvider(sdkDocs, objectsRepo, objectsResolver)
+	hoverHandler := hover.NewHandler(filesystem, hoverProvider, clientCapabilities)
 	// Code actions.
-	codeActionsHandler := codeactions.NewHandler(filesystem, objectsRepo, notifier)
+	codeActionsHandler := codeactions.NewHandler(filesystem, objectsRepo, notifier, progressReporter)
+	// Definition.
+	definitionHandler := definition.NewHandler(filesystem, snapshots)
+	// References.
+	referencesHandler := references.NewHandler(filesystem, snapshots)
+	// Symbols.
+	symbolsHandler := symbols.NewHandler(filesystem, snapshots)
+	// Rename.
+	renameHandler := rename.NewHandler(filesystem, snapshots, objectsRepo, notifier)
+	// Formatting.
+	formattingHandler := formatting.NewHandler(filesystem, sdkDocs)
+	// Folding.
+	foldingHandler := folding.NewHandler(filesystem)
+	// Semantic tokens.
+	semanticTokensHandler := semantictokens.NewHandler(filesystem, sdkDocs)
+	// Inlay hints.
+	inlayHintsHandler := inlayhints.NewHandler(filesystem, objectsRepo)
 
 	return &handlersRegistry{
-		Diagnostics:    diagnosticsHandler.Handle,
-		Completion:     completionHandler.Handle,
-		Hover:          hoverHandler.Handle,
-		CodeAction:     codeActionsHandler.HandleCodeAction,
-		ExecuteCommand: codeActionsHandler.HandleExecuteCommand,
+		Diagnostics:            diagnosticsHandler.Handle,
+		DocumentDiagnostic:     diagnosticsHandler.HandleDocumentDiagnostic,
+		WorkspaceDiagnostic:    diagnosticsHandler.HandleWorkspaceDiagnostic,
+		Completion:             completionHandler.Handle,
+		Hover:                  hoverHandler.Handle,
+		CodeAction:             codeActionsHandler.HandleCodeAction,
+		ExecuteCommand:         codeActionsHandler.HandleExecuteCommand,
+		Definition:             definitionHandler.Handle,
+		References:             referencesHandler.Handle,
+		DocumentSymbol:         symbolsHandler.HandleDocumentSymbol,
+		WorkspaceSymbol:        symbolsHandler.HandleWorkspaceSymbol,
+		Rename:                 renameHandler.HandleRename,
+		PrepareRename:          renameHandler.HandlePrepareRename,
+		Formatting:             formattingHandler.HandleFormatting,
+		RangeFormatting:        formattingHandler.HandleRangeFormatting,
+		FoldingRange:           foldingHandler.Handle,
+		SemanticTokens:         semanticTokensHandler.HandleFull,
+		SemanticRange:          semanticTokensHandler.HandleRange,
+		InlayHint:              inlayHintsHandler.Handle,
+		WarmUpCache:            objectsRepo.WarmUp,
+		SetCacheTTL:            objectsRepo.SetCacheTTL,
+		SetDiagnosticsSettings: diagnosticsProvider.SetSettings,
+		InvalidateDiagnostics:  diagnosticsHandler.Invalidate,
 	}, nil
 }
+
+// fileScopes bind the [nobl9repo.Scope] of the file's workspace folder to the context.
+type fileScopes interface {
+	WithFileScope(ctx context.Context, uri files.URI) context.Context
+	Generation() uint64
+}
@@ -0,0 +1,15 @@
+//go:build unix
+
+package server
+
+import (
+	"errors"
+	"syscall"
+)
+
+// isProcessRunning checks if the process exists by sending it the null signal.
+func isProcessRunning(pid int) bool {
+	err := syscall.Kill(pid, 0)
+	// EPERM means the process exists, but it belongs to a different user.
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
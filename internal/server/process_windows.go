@@ -0,0 +1,26 @@
+//go:build windows
+
+package server
+
+import (
+	"errors"
+	"syscall"
+)
+
+// stillActive is the exit code reported for processes which have not terminated yet.
+const stillActive = 259
+
+// isProcessRunning checks if the process exists and has not reported its exit code yet.
+func isProcessRunning(pid int) bool {
+	handle, err := syscall.OpenProcess(syscall.PROCESS_QUERY_INFORMATION, false, uint32(pid)) // #nosec G115
+	if err != nil {
+		// Access denied means the process exists, but we're not allowed to query it.
+		return errors.Is(err, syscall.ERROR_ACCESS_DENIED)
+	}
+	defer func() { _ = syscall.CloseHandle(handle) }()
+	var exitCode uint32
+	if err = syscall.GetExitCodeProcess(handle, &exitCode); err != nil {
+		return true
+	}
+	return exitCode == stillActive
+}
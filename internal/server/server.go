@@ -3,81 +3,204 @@ package server
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	v1alphaParser "github.com/nobl9/nobl9-go/manifest/v1alpha/parser"
 	"github.com/sourcegraph/jsonrpc2"
 
+	"github.com/nobl9/nobl9-language-server/internal/capabilities"
 	"github.com/nobl9/nobl9-language-server/internal/codeactions"
 	"github.com/nobl9/nobl9-language-server/internal/config"
+	"github.com/nobl9/nobl9-language-server/internal/diagnostics"
 	"github.com/nobl9/nobl9-language-server/internal/files"
 	"github.com/nobl9/nobl9-language-server/internal/logging"
 	"github.com/nobl9/nobl9-language-server/internal/messages"
 	"github.com/nobl9/nobl9-language-server/internal/mux"
+	"github.com/nobl9/nobl9-language-server/internal/nobl9repo"
+	"github.com/nobl9/nobl9-language-server/internal/progress"
 	"github.com/nobl9/nobl9-language-server/internal/recovery"
+	"github.com/nobl9/nobl9-language-server/internal/semantictokens"
+	"github.com/nobl9/nobl9-language-server/internal/workspace"
 )
 
 const languageID = "yaml"
 
-func New(ctx context.Context, lspVersion string, filePatterns []string) (*Server, error) {
+const (
+	// diagnosticsDebounce is the time we wait for subsequent changes of a document
+	// before evaluating its diagnostics.
+	diagnosticsDebounce = 200 * time.Millisecond
+	// maxConcurrentDiagnostics is the maximum number of documents diagnosed in parallel.
+	maxConcurrentDiagnostics = 4
+	// watchedFilesGlobPattern matches the files the client is asked to watch for changes.
+	watchedFilesGlobPattern = "**/*.{yaml,yml}"
+)
+
+func New(
+	ctx context.Context,
+	lspVersion string,
+	filePatterns []string,
+	warmUpCache bool,
+) (*Server, error) {
 	span, _ := logging.StartSpan(ctx, "server_start")
 	defer span.Finish()
 
 	var conn *jsonrpc2.Conn
 	filesystem := files.NewFS(filePatterns)
 	notifier := &rpcConnectionNotifier{conn: conn}
-	registry, err := newHandlersRegistry(filesystem, notifier)
+	progressReporter := progress.NewReporter(notifier)
+	clientCapabilities := capabilities.NewStore()
+	folders := &workspaceFolders{}
+	registry, err := newHandlersRegistry(filesystem, notifier, progressReporter, clientCapabilities, folders)
 	if err != nil {
 		return nil, err
 	}
 
 	// TODO: make sure it sits in the right place.
 	v1alphaParser.UseStrictDecodingMode = true
-	return &Server{
-		lspVersion:      lspVersion,
-		files:           filesystem,
-		documentUpdates: make(chan documentUpdateEvent, 10),
-		handlers:        registry,
-		conn:            conn,
-		notifier:        notifier,
-	}, nil
+	s := &Server{
+		lspVersion:  lspVersion,
+		warmUpCache: warmUpCache,
+		defaults: settingsDefaults{
+			logLevel:     logging.GetLogLevel(),
+			filePatterns: filePatterns,
+		},
+		files:        filesystem,
+		handlers:     registry,
+		conn:         conn,
+		notifier:     notifier,
+		progress:     progressReporter,
+		capabilities: clientCapabilities,
+		tracer:       newTracer(notifier),
+		indexer:      workspace.NewIndexer(filesystem, progressReporter),
+		folders:      folders,
+		// Only the latest pending settings update is kept.
+		settingsUpdates: make(chan messages.DidChangeConfigurationParams, 1),
+		fileEvents:      newFileEventsQueue(),
+		exitNotify:      make(chan error, 1),
+	}
+	s.diagnostics = diagnostics.NewScheduler(s.handleDiagnostics, diagnosticsDebounce, maxConcurrentDiagnostics)
+	return s, nil
 }
 
 type Server struct {
-	lspVersion      string
+	lspVersion  string
+	warmUpCache bool
+	// defaults are the settings used when the client does not provide its own.
+	defaults   settingsDefaults
+	settingsMu sync.Mutex
+	// settingsUpdates queues the changed settings, they're applied by [Server.processSettingsUpdates].
+	settingsUpdates chan messages.DidChangeConfigurationParams
 	initialized     atomic.Bool
-	conn            *jsonrpc2.Conn
-	files           *files.FS
-	handlers        *handlersRegistry
-	documentUpdates chan documentUpdateEvent
-	notifier        *rpcConnectionNotifier
-
-	runDiagnosticsLoopOnce sync.Once
+	// shutdown is set once the client requested [messages.ShutdownMethod],
+	// from then on only [messages.ExitMethod] is accepted.
+	shutdown    atomic.Bool
+	conn        *jsonrpc2.Conn
+	files       *files.FS
+	handlers    *handlersRegistry
+	diagnostics *diagnostics.Scheduler
+	notifier    *rpcConnectionNotifier
+	progress    *progress.Reporter
+	// capabilities are the client capabilities received with [messages.InitializeMethod].
+	capabilities *capabilities.Store
+	tracer       *tracer
+	indexer      *workspace.Indexer
+	// fileEvents are applied to the index by [Server.processFileEvents].
+	fileEvents *fileEventsQueue
+	// pullDiagnostics is true if the client requests diagnostics itself,
+	// otherwise they're pushed to the client whenever a document changes.
+	pullDiagnostics atomic.Bool
+	// refreshTimer debounces [messages.DiagnosticRefreshMethod] requests sent by [Server.diagnoseOpenedFiles].
+	refreshTimer *time.Timer
+	refreshMu    sync.Mutex
+	// folders are the workspace folders provided by the client.
+	folders *workspaceFolders
+
+	backgroundTasksOnce sync.Once
+	exitNotify          chan error
+	exitOnce            sync.Once
 }
 
-type documentUpdateEvent struct {
-	Item messages.TextDocumentItem
+// ExitNotify returns a channel which receives a single value once the server should terminate.
+// The error is nil if the client followed the shutdown sequence,
+// otherwise it describes why the server is terminating.
+func (s *Server) ExitNotify() <-chan error {
+	return s.exitNotify
 }
 
 func (s *Server) GetHandlers() map[string]mux.HandlerFunc {
-	return map[string]mux.HandlerFunc{
-		messages.InitializeMethod:     s.handleInitialize,
-		messages.InitializedMethod:    s.handleInitialized,
-		messages.ShutdownMethod:       s.handleShutdown,
-		messages.DidOpenMethod:        handleParamsOnly(s.handleDidOpen),
-		messages.DidCloseMethod:       handleParamsOnly(s.handleDidClose),
-		messages.DidSaveMethod:        handleParamsOnly(s.handleDidSave),
-		messages.DidChangeMethod:      handleParamsOnly(s.handleDidChange),
-		messages.CompletionMethod:     handleParamsOnly(s.handlers.Completion),
-		messages.HoverMethod:          handleParamsOnly(s.handlers.Hover),
-		messages.CodeActionMethod:     handleParamsOnly(s.handlers.CodeAction),
-		messages.ExecuteCommandMethod: handleParamsOnly(s.handlers.ExecuteCommand),
-		messages.SetTraceMethod:       handleParamsOnly(s.handleSetTrace),
-		messages.LogTraceMethod:       handleParamsOnly(s.handleLogTrace),
-		messages.CancelRequestMethod:  handleParamsOnly(s.handleCancelRequest),
+	handlers := map[string]mux.HandlerFunc{
+		messages.InitializeMethod:                s.handleInitialize,
+		messages.InitializedMethod:               s.handleInitialized,
+		messages.ShutdownMethod:                  s.handleShutdown,
+		messages.ExitMethod:                      s.handleExit,
+		messages.DidOpenMethod:                   handleParamsOnly(s.handleDidOpen),
+		messages.DidCloseMethod:                  handleParamsOnly(s.handleDidClose),
+		messages.DidSaveMethod:                   handleParamsOnly(s.handleDidSave),
+		messages.DidChangeMethod:                 handleParamsOnly(s.handleDidChange),
+		messages.DidChangeConfigurationMethod:    handleParamsOnly(s.handleDidChangeConfiguration),
+		messages.DidChangeWatchedFilesMethod:     handleParamsOnly(s.handleDidChangeWatchedFiles),
+		messages.DidChangeWorkspaceFoldersMethod: handleParamsOnly(s.handleDidChangeWorkspaceFolders),
+		messages.CompletionMethod:                handleParamsOnly(s.handlers.Completion),
+		messages.HoverMethod:                     handleParamsOnly(s.handlers.Hover),
+		messages.CodeActionMethod:                handleParamsOnly(s.handlers.CodeAction),
+		messages.ExecuteCommandMethod:            handleParamsOnly(s.handlers.ExecuteCommand),
+		messages.DefinitionMethod:                handleParamsOnly(s.handlers.Definition),
+		messages.ReferencesMethod:                handleParamsOnly(s.handlers.References),
+		messages.DocumentSymbolMethod:            handleParamsOnly(s.handlers.DocumentSymbol),
+		messages.WorkspaceSymbolMethod:           handleParamsOnly(s.handlers.WorkspaceSymbol),
+		messages.RenameMethod:                    handleParamsOnly(s.handlers.Rename),
+		messages.PrepareRenameMethod:             handleParamsOnly(s.handlers.PrepareRename),
+		messages.DocumentFormattingMethod:        handleParamsOnly(s.handlers.Formatting),
+		messages.RangeFormattingMethod:           handleParamsOnly(s.handlers.RangeFormatting),
+		messages.FoldingRangeMethod:              handleParamsOnly(s.handlers.FoldingRange),
+		messages.SemanticTokensFullMethod:        handleParamsOnly(s.handlers.SemanticTokens),
+		messages.SemanticTokensRangeMethod:       handleParamsOnly(s.handlers.SemanticRange),
+		messages.InlayHintMethod:                 handleParamsOnly(s.handlers.InlayHint),
+		messages.DocumentDiagnosticMethod:        handleParamsOnly(s.handlers.DocumentDiagnostic),
+		messages.WorkspaceDiagnosticMethod:       handleParamsOnly(s.handlers.WorkspaceDiagnostic),
+		messages.SetTraceMethod:                  handleParamsOnly(s.handleSetTrace),
+		messages.WorkDoneProgressCancelMethod:    handleParamsOnly(s.handleWorkDoneProgressCancel),
+	}
+	for method, handler := range handlers {
+		handlers[method] = s.checkLifecycle(method, s.tracer.Wrap(method, s.withFileScope(handler)))
+	}
+	return handlers
+}
+
+// checkLifecycle wraps the [mux.HandlerFunc] and rejects the requests
+// which are not allowed in the current lifecycle state of the [Server].
+// Requests sent before [messages.InitializeMethod] fail with [messages.CodeServerNotInitialized],
+// while requests sent after [messages.ShutdownMethod] fail with [jsonrpc2.CodeInvalidRequest].
+// Notifications are dropped in both cases, except for [messages.ExitMethod].
+func (s *Server) checkLifecycle(method string, handler mux.HandlerFunc) mux.HandlerFunc {
+	return func(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) (any, error) {
+		var err *jsonrpc2.Error
+		switch {
+		case method == messages.ExitMethod:
+		case s.shutdown.Load():
+			err = &jsonrpc2.Error{
+				Code:    jsonrpc2.CodeInvalidRequest,
+				Message: "server is shutting down",
+			}
+		case method != messages.InitializeMethod && !s.initialized.Load():
+			err = &jsonrpc2.Error{
+				Code:    messages.CodeServerNotInitialized,
+				Message: "server is not initialized",
+			}
+		}
+		if err == nil {
+			return handler(ctx, conn, req)
+		}
+		if req.Notif {
+			slog.DebugContext(ctx, "dropping notification", slog.String("reason", err.Message))
+			return nil, nil
+		}
+		return nil, err
 	}
 }
 
@@ -86,24 +209,49 @@ func (s *Server) handleInitialize(
 	conn *jsonrpc2.Conn,
 	req *jsonrpc2.Request,
 ) (any, error) {
-	// TODO: Figure out If we need to do anything with the client capabilities here.
-	_, err := parseRequestParameters[messages.InitializeParams](req.Params)
+	params, err := parseRequestParameters[messages.InitializeParams](req.Params)
 	if err != nil {
 		return nil, err
 	}
 
+	positionEncoding := params.Capabilities.GetPositionEncoding()
 	resp := messages.InitializeResponse{
 		Capabilities: messages.ServerCapabilities{
-			TextDocumentSync: messages.TextDocumentSyncKindFull,
+			PositionEncoding: positionEncoding,
+			TextDocumentSync: messages.TextDocumentSyncKindIncremental,
 			CompletionProvider: &messages.CompletionProvider{
 				ResolveProvider:   false,
 				TriggerCharacters: []string{":"},
 			},
-			HoverProvider:      true,
-			CodeActionProvider: true,
+			HoverProvider:              true,
+			CodeActionProvider:         true,
+			DefinitionProvider:         true,
+			ReferencesProvider:         true,
+			DocumentSymbolProvider:     true,
+			WorkspaceSymbolProvider:    true,
+			RenameProvider:             &messages.RenameOptions{PrepareProvider: true},
+			DocumentFormattingProvider: true,
+			RangeFormattingProvider:    true,
+			FoldingRangeProvider:       true,
+			SemanticTokensProvider: &messages.SemanticTokensOptions{
+				Legend: semantictokens.GetLegend(),
+				Range:  true,
+				Full:   true,
+			},
+			InlayHintProvider: true,
+			DiagnosticProvider: &messages.DiagnosticOptions{
+				InterFileDependencies: true,
+				WorkspaceDiagnostics:  true,
+			},
 			ExecuteCommandProvider: &messages.ExecuteCommandProvider{
 				Commands: codeactions.GetCommandNames(),
 			},
+			Workspace: &messages.ServerCapabilitiesWorkspace{
+				WorkspaceFolders: messages.WorkspaceFoldersServerCapabilities{
+					Supported:           true,
+					ChangeNotifications: true,
+				},
+			},
 		},
 		ServerInfo: messages.ServerInfo{
 			Name:    config.ServerName,
@@ -113,6 +261,15 @@ func (s *Server) handleInitialize(
 	if s.initialized.CompareAndSwap(false, true) {
 		s.conn = conn
 		s.notifier.conn = conn
+		s.folders.Set(getWorkspaceFolders(params))
+		s.pullDiagnostics.Store(params.Capabilities.SupportsPullDiagnostics())
+		s.files.SetPositionEncoding(positionEncoding)
+		s.capabilities.Set(params.Capabilities)
+		s.progress.SetSupported(params.Capabilities.SupportsWorkDoneProgress())
+		s.tracer.SetValue(params.Trace)
+		if params.ProcessID != nil {
+			go s.watchParentProcess(*params.ProcessID)
+		}
 	} else {
 		slog.ErrorContext(ctx, "connection already initialized")
 	}
@@ -120,14 +277,141 @@ func (s *Server) handleInitialize(
 }
 
 func (s *Server) handleInitialized(_ context.Context, _ *jsonrpc2.Conn, _ *jsonrpc2.Request) (interface{}, error) {
-	s.runDiagnosticsLoopOnce.Do(func() {
-		go s.runDiagnosticsLoop()
+	s.backgroundTasksOnce.Do(func() {
+		go s.setupWorkspace()
 	})
 	return nil, nil
 }
 
+// setupWorkspace loads the client's settings before indexing the workspace and warming up the cache,
+// as they might change which files are indexed and which Nobl9 configuration contexts are used.
+func (s *Server) setupWorkspace() {
+	ctx := context.Background()
+	defer func() { recovery.LogPanic(ctx, recover()) }()
+
+	s.registerCapabilities(ctx)
+	s.loadInitialSettings(ctx)
+	// The updates are applied only after the initial settings, so that they're not overwritten.
+	go s.processSettingsUpdates()
+	go s.processFileEvents()
+	if s.warmUpCache {
+		go s.warmUpObjectsCache()
+	}
+	s.indexWorkspace()
+	// References to the objects defined in the workspace can be resolved now.
+	s.refreshDiagnostics(ctx)
+}
+
+// registerCapabilities dynamically registers for the notifications
+// which the client only sends once asked to, granted it supports it.
+func (s *Server) registerCapabilities(ctx context.Context) {
+	caps := s.capabilities.Get()
+	var registrations []messages.Registration
+	if caps.SupportsDidChangeConfigurationRegistration() {
+		registrations = append(registrations, messages.Registration{
+			ID:     messages.DidChangeConfigurationMethod,
+			Method: messages.DidChangeConfigurationMethod,
+			RegisterOptions: messages.DidChangeConfigurationRegistrationOptions{
+				Section: config.SettingsSection,
+			},
+		})
+	}
+	if caps.SupportsDidChangeWatchedFilesRegistration() {
+		registrations = append(registrations, messages.Registration{
+			ID:     messages.DidChangeWatchedFilesMethod,
+			Method: messages.DidChangeWatchedFilesMethod,
+			RegisterOptions: messages.DidChangeWatchedFilesRegistrationOptions{
+				Watchers: []messages.FileSystemWatcher{{GlobPattern: watchedFilesGlobPattern}},
+			},
+		})
+	}
+	if len(registrations) == 0 {
+		return
+	}
+	if err := s.notifier.Call(ctx, messages.RegisterCapabilityMethod, messages.RegistrationParams{
+		Registrations: registrations,
+	}, nil); err != nil {
+		slog.ErrorContext(ctx, "failed to register capabilities", slog.Any("error", err))
+	}
+}
+
+// warmUpObjectsCache prefetches the most commonly used Nobl9 resources for every scope in use.
+func (s *Server) warmUpObjectsCache() {
+	ctx := context.Background()
+	defer func() { recovery.LogPanic(ctx, recover()) }()
+
+	for _, scope := range s.folders.Scopes() {
+		s.handlers.WarmUpCache(nobl9repo.WithScope(ctx, scope))
+	}
+}
+
+// indexWorkspace reads all Nobl9 files from the workspace folders (if provided by the client).
+func (s *Server) indexWorkspace() {
+	for _, root := range s.folders.URIs() {
+		s.indexWorkspaceRoot(root)
+	}
+}
+
+func (s *Server) indexWorkspaceRoot(root files.URI) {
+	ctx := logging.ContextAttr(context.Background(), slog.String("rootUri", root))
+	defer func() { recovery.LogPanic(ctx, recover()) }()
+
+	if err := s.indexer.Index(ctx, root); err != nil {
+		slog.ErrorContext(ctx, "failed to index workspace", slog.Any("error", err))
+	}
+}
+
+// handleDidChangeWatchedFiles keeps the workspace index up to date with the files changed outside the editor.
+// Notifications are handled synchronously, reading the files is left to [Server.processFileEvents].
+func (s *Server) handleDidChangeWatchedFiles(
+	_ context.Context,
+	params messages.DidChangeWatchedFilesParams,
+) (interface{}, error) {
+	s.fileEvents.Push(params.Changes)
+	return nil, nil
+}
+
+// processFileEvents applies the queued watched files changes to the index.
+// Diagnostics are refreshed afterwards, as the references might now resolve to different objects.
+func (s *Server) processFileEvents() {
+	for range s.fileEvents.ready {
+		s.updateIndex(s.fileEvents.Pop())
+	}
+}
+
+func (s *Server) updateIndex(events []messages.FileEvent) {
+	ctx := context.Background()
+	defer func() { recovery.LogPanic(ctx, recover()) }()
+
+	if len(events) == 0 {
+		return
+	}
+	s.indexer.Update(ctx, s.folders.URIs(), events)
+	s.refreshDiagnostics(ctx)
+}
+
+// handleShutdown stops all background work, the connection is kept open until [messages.ExitMethod] is received.
 func (s *Server) handleShutdown(_ context.Context, _ *jsonrpc2.Conn, _ *jsonrpc2.Request) (interface{}, error) {
-	return nil, s.conn.Close()
+	s.shutdown.Store(true)
+	s.diagnostics.Stop()
+	return nil, nil
+}
+
+// handleExit terminates the server.
+// If the client did not request [messages.ShutdownMethod] beforehand, the server exits with an error.
+func (s *Server) handleExit(ctx context.Context, _ *jsonrpc2.Conn, _ *jsonrpc2.Request) (interface{}, error) {
+	if !s.shutdown.Load() {
+		slog.WarnContext(ctx, "exit requested without prior shutdown")
+		s.exit(errors.New("exit requested without prior shutdown"))
+		return nil, nil
+	}
+	s.exit(nil)
+	return nil, nil
+}
+
+// exit notifies [Server.ExitNotify] listener, only the first call has any effect.
+func (s *Server) exit(err error) {
+	s.exitOnce.Do(func() { s.exitNotify <- err })
 }
 
 func (s *Server) handleDidOpen(ctx context.Context, params messages.DidOpenParams) (interface{}, error) {
@@ -142,9 +426,7 @@ func (s *Server) handleDidOpen(ctx context.Context, params messages.DidOpenParam
 	); err != nil {
 		return nil, err
 	}
-	s.documentUpdates <- documentUpdateEvent{
-		Item: params.TextDocument,
-	}
+	s.diagnoseOpenedFiles()
 	return nil, nil
 }
 
@@ -154,50 +436,61 @@ func (s *Server) handleDidSave(_ context.Context, _ messages.DidSaveParams) (int
 }
 
 func (s *Server) handleDidClose(_ context.Context, params messages.DidCloseParams) (interface{}, error) {
-	return nil, s.files.CloseFile(params.TextDocument.URI)
+	s.diagnostics.Cancel(params.TextDocument.URI)
+	if err := s.files.CloseFile(params.TextDocument.URI); err != nil {
+		return nil, err
+	}
+	s.diagnoseOpenedFiles()
+	return nil, nil
 }
 
 func (s *Server) handleDidChange(ctx context.Context, params messages.DidChangeParams) (interface{}, error) {
-	if len(params.ContentChanges) != 1 {
-		return nil, nil
-	}
-	if err := s.files.UpdateFile(
+	if err := s.files.ChangeFile(
 		ctx,
 		params.TextDocument.URI,
-		params.ContentChanges[0].Text,
+		params.ContentChanges,
 		params.TextDocument.Version,
 	); err != nil {
 		return nil, err
 	}
-	s.documentUpdates <- documentUpdateEvent{
-		Item: messages.TextDocumentItem{
-			URI:        params.TextDocument.URI,
-			LanguageID: languageID,
-			Version:    params.TextDocument.Version,
-			Text:       params.ContentChanges[0].Text,
-		},
-	}
+	s.diagnoseOpenedFiles()
 	return nil, nil
 }
 
-func (s *Server) runDiagnosticsLoop() {
-	for update := range s.documentUpdates {
-		s.handleSingleUpdateDiagnostics(update)
+// diagnoseOpenedFiles re-evaluates the diagnostics of all the opened files whenever any of them changes,
+// since they might reference or duplicate each other's objects.
+// If the client pulls the diagnostics itself, it is asked to do so once the changes settle down.
+func (s *Server) diagnoseOpenedFiles() {
+	if !s.pullDiagnostics.Load() {
+		s.scheduleOpenedFilesDiagnostics()
+		return
+	}
+	s.refreshMu.Lock()
+	defer s.refreshMu.Unlock()
+	if s.refreshTimer != nil {
+		s.refreshTimer.Stop()
 	}
+	s.refreshTimer = time.AfterFunc(diagnosticsDebounce, func() {
+		ctx := context.Background()
+		defer func() { recovery.LogPanic(ctx, recover()) }()
+		s.refreshDiagnostics(ctx)
+	})
 }
 
-func (s *Server) handleSingleUpdateDiagnostics(update documentUpdateEvent) {
-	ctx := logging.ContextAttr(context.Background(),
-		slog.String("uri", update.Item.URI),
-		slog.String("language", update.Item.LanguageID),
-		slog.Int("version", update.Item.Version))
+// handleDiagnostics evaluates and publishes diagnostics for the document.
+// It is run by [diagnostics.Scheduler], the context is cancelled if the document has changed in the meantime.
+func (s *Server) handleDiagnostics(ctx context.Context, item messages.TextDocumentItem) {
+	ctx = logging.ContextAttr(ctx,
+		slog.String("uri", item.URI),
+		slog.String("language", item.LanguageID),
+		slog.Int("version", item.Version))
 	span, ctx := logging.StartSpan(ctx, "handle_diagnostics")
 	defer span.Finish()
 	defer func() { recovery.LogPanic(ctx, recover()) }()
 
 	slog.DebugContext(ctx, "evaluating diagnostics")
 
-	params, err := s.handlers.Diagnostics(ctx, update.Item)
+	params, err := s.handlers.Diagnostics(ctx, item)
 	if err != nil {
 		slog.ErrorContext(ctx, "failed to diagnose file", slog.Any("error", err))
 	}
@@ -212,17 +505,16 @@ func (s *Server) handleSingleUpdateDiagnostics(update documentUpdateEvent) {
 }
 
 func (s *Server) handleSetTrace(ctx context.Context, params messages.SetTraceParams) (interface{}, error) {
-	// TODO: handleSetTrace
-	return nil, nil
-}
-
-func (s *Server) handleLogTrace(ctx context.Context, params messages.LogTraceParams) (interface{}, error) {
-	// TODO: handleLogTrace
+	slog.DebugContext(ctx, "setting trace value", slog.String("value", string(params.Value)))
+	s.tracer.SetValue(params.Value)
 	return nil, nil
 }
 
-func (s *Server) handleCancelRequest(ctx context.Context, params messages.CancelRequestParams) (interface{}, error) {
-	// TODO: handleCancelRequest
+func (s *Server) handleWorkDoneProgressCancel(
+	ctx context.Context,
+	params messages.WorkDoneProgressCancelParams,
+) (interface{}, error) {
+	s.progress.Cancel(ctx, params.Token)
 	return nil, nil
 }
 
@@ -232,6 +524,10 @@ func (r rpcConnectionNotifier) Notify(ctx context.Context, method string, params
 	return r.conn.Notify(ctx, method, params)
 }
 
+func (r rpcConnectionNotifier) Call(ctx context.Context, method string, params, result interface{}) error {
+	return r.conn.Call(ctx, method, params, result)
+}
+
 type paramsOnlyHandlerFunc[T any] func(ctx context.Context, params T) (interface{}, error)
 
 // handleParamsOnly wraps generic [paramsOnlyHandlerFunc] function call with [mux.HandlerFunc].
@@ -0,0 +1,279 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/nobl9/nobl9-language-server/internal/config"
+	"github.com/nobl9/nobl9-language-server/internal/files"
+	"github.com/nobl9/nobl9-language-server/internal/logging"
+	"github.com/nobl9/nobl9-language-server/internal/messages"
+	"github.com/nobl9/nobl9-language-server/internal/nobl9repo"
+	"github.com/nobl9/nobl9-language-server/internal/recovery"
+)
+
+// settingsDefaults are set with the command line flags,
+// they're used whenever the client does not provide its own value in [config.Settings].
+type settingsDefaults struct {
+	logLevel     slog.Level
+	filePatterns []string
+}
+
+// handleDidChangeConfiguration applies the changed settings.
+// If the client supports [messages.ConfigurationMethod], the settings are pulled from the client,
+// otherwise they're read from the notification itself.
+func (s *Server) handleDidChangeConfiguration(
+	ctx context.Context,
+	params messages.DidChangeConfigurationParams,
+) (interface{}, error) {
+	// Notifications are handled synchronously, we can't wait for the client's response here.
+	// Since each update carries (or pulls) the complete settings, only the latest pending one is kept.
+	select {
+	case s.settingsUpdates <- params:
+	default:
+		select {
+		case <-s.settingsUpdates:
+		default:
+		}
+		s.settingsUpdates <- params
+	}
+	return nil, nil
+}
+
+// processSettingsUpdates applies the queued settings updates one at a time,
+// so that they can't be applied out of order.
+func (s *Server) processSettingsUpdates() {
+	for params := range s.settingsUpdates {
+		s.updateSettings(params)
+	}
+}
+
+func (s *Server) updateSettings(params messages.DidChangeConfigurationParams) {
+	ctx := context.Background()
+	defer func() { recovery.LogPanic(ctx, recover()) }()
+
+	var (
+		settings *config.Settings
+		err      error
+	)
+	if s.capabilities.Get().SupportsConfiguration() {
+		settings, err = s.pullAllSettings(ctx)
+	} else {
+		settings, err = parsePushedSettings(params.Settings)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to read settings", slog.Any("error", err))
+		return
+	}
+	if settings == nil {
+		slog.DebugContext(ctx, "no settings provided")
+		return
+	}
+	if filePatternsChanged := s.applySettings(ctx, *settings); filePatternsChanged {
+		s.indexWorkspace()
+	}
+	// Diagnostics are refreshed once the workspace is indexed again (if needed).
+	s.refreshDiagnostics(ctx)
+}
+
+// loadInitialSettings pulls the settings, granted the client supports it.
+// The diagnostics are not refreshed, the caller is expected to do it once the workspace is indexed.
+func (s *Server) loadInitialSettings(ctx context.Context) {
+	if !s.capabilities.Get().SupportsConfiguration() {
+		return
+	}
+	settings, err := s.pullAllSettings(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to pull settings", slog.Any("error", err))
+		return
+	}
+	s.applySettings(ctx, *settings)
+}
+
+// loadFolderSettings pulls the settings of the workspace folders and applies their scopes,
+// granted the client supports it.
+func (s *Server) loadFolderSettings(ctx context.Context, folders []messages.WorkspaceFolder) {
+	if len(folders) == 0 || !s.capabilities.Get().SupportsConfiguration() {
+		return
+	}
+	settings, err := s.pullSettings(ctx, folders)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to pull workspace folders settings", slog.Any("error", err))
+		return
+	}
+	s.setFolderScopes(folders, settings[1:])
+}
+
+// pullAllSettings pulls the settings of the workspace folders and applies their scopes.
+// It returns the global settings.
+func (s *Server) pullAllSettings(ctx context.Context) (*config.Settings, error) {
+	folders := s.folders.Get()
+	settings, err := s.pullSettings(ctx, folders)
+	if err != nil {
+		return nil, err
+	}
+	s.setFolderScopes(folders, settings[1:])
+	return &settings[0], nil
+}
+
+func (s *Server) setFolderScopes(folders []messages.WorkspaceFolder, settings []config.Settings) {
+	for i, folder := range folders {
+		s.folders.SetScope(folder.URI, settings[i])
+	}
+	s.handlers.InvalidateDiagnostics()
+}
+
+// pullSettings requests the [config.SettingsSection] from the client.
+// The first element of the result holds the global settings,
+// it is followed by the settings of each of the provided workspace folders.
+func (s *Server) pullSettings(ctx context.Context, folders []messages.WorkspaceFolder) ([]config.Settings, error) {
+	items := make([]messages.ConfigurationItem, 0, len(folders)+1)
+	items = append(items, messages.ConfigurationItem{Section: config.SettingsSection})
+	for _, folder := range folders {
+		items = append(items, messages.ConfigurationItem{ScopeURI: folder.URI, Section: config.SettingsSection})
+	}
+	var result []json.RawMessage
+	if err := s.notifier.Call(ctx, messages.ConfigurationMethod, messages.ConfigurationParams{
+		Items: items,
+	}, &result); err != nil {
+		return nil, err
+	}
+	settings := make([]config.Settings, len(items))
+	for i := range min(len(result), len(items)) {
+		if len(result[i]) == 0 || string(result[i]) == "null" {
+			continue
+		}
+		if err := json.Unmarshal(result[i], &settings[i]); err != nil {
+			return nil, fmt.Errorf("failed to decode %s settings: %w", config.SettingsSection, err)
+		}
+	}
+	return settings, nil
+}
+
+// parsePushedSettings reads the [config.SettingsSection] from the [messages.DidChangeConfigurationParams].
+// It returns nil if the section is not present.
+func parsePushedSettings(data json.RawMessage) (*config.Settings, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	var settings map[string]json.RawMessage
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return nil, fmt.Errorf("failed to decode settings: %w", err)
+	}
+	section, ok := settings[config.SettingsSection]
+	if !ok {
+		return nil, nil
+	}
+	var nobl9Settings config.Settings
+	if err := json.Unmarshal(section, &nobl9Settings); err != nil {
+		return nil, fmt.Errorf("failed to decode %s settings: %w", config.SettingsSection, err)
+	}
+	return &nobl9Settings, nil
+}
+
+// applySettings applies the settings on top of the [settingsDefaults].
+// Invalid settings are reported to the user and their defaults are used instead.
+// Diagnostics of the opened files have to be refreshed by the caller afterwards.
+// It returns true if the file patterns have changed, in which case the workspace has to be indexed again.
+func (s *Server) applySettings(ctx context.Context, settings config.Settings) (filePatternsChanged bool) {
+	problems, filePatternsChanged := s.setSettings(ctx, settings)
+	if len(problems) > 0 {
+		message := fmt.Sprintf("Invalid %s settings, using defaults instead: %s",
+			config.SettingsSection, strings.Join(problems, "; "))
+		slog.WarnContext(ctx, message)
+		if err := s.notifier.Notify(ctx, messages.ShowMessageMethod, messages.ShowMessageParams{
+			Type:    messages.MessageTypeWarning,
+			Message: message,
+		}); err != nil {
+			slog.ErrorContext(ctx, "failed to notify about invalid settings", slog.Any("error", err))
+		}
+	}
+	return filePatternsChanged
+}
+
+// setSettings sets the server's state according to the settings, it does not communicate with the client.
+// It returns the problems found with the invalid settings.
+func (s *Server) setSettings(
+	ctx context.Context,
+	settings config.Settings,
+) (problems []string, filePatternsChanged bool) {
+	s.settingsMu.Lock()
+	defer s.settingsMu.Unlock()
+
+	slog.DebugContext(ctx, "applying settings", slog.Any("settings", settings))
+
+	logLevel := s.defaults.logLevel
+	if settings.LogLevel != "" {
+		var level logging.Level
+		if err := level.UnmarshalText([]byte(settings.LogLevel)); err != nil {
+			problems = append(problems, fmt.Sprintf("logLevel: %v", err))
+		} else {
+			logLevel = level.Level
+		}
+	}
+	logging.SetLogLevel(logLevel)
+
+	filePatterns := s.defaults.filePatterns
+	if len(settings.FilePatterns) > 0 {
+		normalized, err := files.NormalizeFilePatterns(settings.FilePatterns)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("filePatterns: %v", err))
+		} else {
+			filePatterns = normalized
+		}
+	}
+	if !slices.Equal(filePatterns, s.files.GetFilePatterns()) {
+		s.files.SetFilePatterns(ctx, filePatterns)
+		filePatternsChanged = true
+	}
+
+	cacheTTL := nobl9repo.DefaultCacheTTL
+	if settings.CacheTTL != "" {
+		ttl, err := time.ParseDuration(settings.CacheTTL)
+		switch {
+		case err != nil:
+			problems = append(problems, fmt.Sprintf("cacheTTL: %v", err))
+		case ttl < 0:
+			problems = append(problems, "cacheTTL: must not be negative")
+		default:
+			cacheTTL = ttl
+		}
+	}
+	s.handlers.SetCacheTTL(cacheTTL)
+	s.handlers.SetDiagnosticsSettings(settings.Diagnostics)
+	s.folders.SetDefaultScope(settings)
+	s.handlers.InvalidateDiagnostics()
+	return problems, filePatternsChanged
+}
+
+// refreshDiagnostics evaluates the diagnostics of the opened files again.
+// If the client pulls the diagnostics itself, it is asked to do so, granted it supports it.
+func (s *Server) refreshDiagnostics(ctx context.Context) {
+	if !s.pullDiagnostics.Load() {
+		s.scheduleOpenedFilesDiagnostics()
+		return
+	}
+	if !s.capabilities.Get().SupportsDiagnosticRefresh() {
+		return
+	}
+	if err := s.notifier.Call(ctx, messages.DiagnosticRefreshMethod, nil, nil); err != nil {
+		slog.ErrorContext(ctx, "failed to refresh diagnostics", slog.Any("error", err))
+	}
+}
+
+// scheduleOpenedFilesDiagnostics schedules the diagnostics of all the opened files to be published.
+func (s *Server) scheduleOpenedFilesDiagnostics() {
+	for _, file := range s.files.GetOpenedFiles() {
+		s.diagnostics.Schedule(messages.TextDocumentItem{
+			URI:        file.URI,
+			LanguageID: languageID,
+			Version:    file.Version,
+			Text:       file.Content,
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nobl9/nobl9-language-server/internal/config"
+	"github.com/nobl9/nobl9-language-server/internal/messages"
+)
+
+func TestParsePushedSettings(t *testing.T) {
+	tests := map[string]struct {
+		in       string
+		expected *config.Settings
+		err      string
+	}{
+		"no settings": {
+			in: "",
+		},
+		"no nobl9 section": {
+			in: `{"yaml":{"format":{"enable":true}}}`,
+		},
+		"nobl9 section": {
+			in: `{"nobl9":{"logLevel":"DEBUG","filePatterns":["foo/*"],"cacheTTL":"1m",` +
+				`"diagnostics":{"references":false},"project":"foo","context":"staging"}}`,
+			expected: &config.Settings{
+				LogLevel:     "DEBUG",
+				FilePatterns: []string{"foo/*"},
+				CacheTTL:     "1m",
+				Diagnostics:  config.DiagnosticsSettings{References: ptr(false)},
+				Project:      "foo",
+				Context:      "staging",
+			},
+		},
+		"invalid section": {
+			in:  `{"nobl9":{"filePatterns":"foo"}}`,
+			err: "failed to decode nobl9 settings",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			settings, err := parsePushedSettings(json.RawMessage(tc.in))
+			if tc.err != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, settings)
+		})
+	}
+}
+
+func TestHandleDidChangeConfiguration(t *testing.T) {
+	s := &Server{settingsUpdates: make(chan messages.DidChangeConfigurationParams, 1)}
+	for _, settings := range []string{`{"nobl9":{"project":"foo"}}`, `{"nobl9":{"project":"bar"}}`} {
+		_, err := s.handleDidChangeConfiguration(context.Background(), messages.DidChangeConfigurationParams{
+			Settings: json.RawMessage(settings),
+		})
+		require.NoError(t, err)
+	}
+	require.Len(t, s.settingsUpdates, 1)
+	assert.JSONEq(t, `{"nobl9":{"project":"bar"}}`, string((<-s.settingsUpdates).Settings))
+}
+
+func ptr[T any](v T) *T { return &v }
@@ -0,0 +1,132 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync/atomic"
+	"unicode/utf8"
+
+	"github.com/sourcegraph/jsonrpc2"
+
+	"github.com/nobl9/nobl9-language-server/internal/logging"
+	"github.com/nobl9/nobl9-language-server/internal/messages"
+	"github.com/nobl9/nobl9-language-server/internal/mux"
+)
+
+// maxTracePayloadLength is the maximum number of bytes of the request parameters
+// and result included in the verbose trace.
+const maxTracePayloadLength = 1000
+
+type traceNotifier interface {
+	Notify(ctx context.Context, method string, params any) error
+}
+
+func newTracer(notifier traceNotifier) *tracer {
+	t := &tracer{notifier: notifier}
+	t.value.Store(messages.TraceValueOff)
+	return t
+}
+
+// tracer forwards the timings of the handled requests to the client through [messages.LogTraceMethod].
+// The amount of details depends on the [messages.TraceValueKind] set by the client:
+//   - [messages.TraceValueOff] disables the trace.
+//   - [messages.TraceValueMessages] reports the request duration.
+//   - [messages.TraceValueVerbose] additionally includes the spans tree along with the request parameters
+//     and result summaries.
+type tracer struct {
+	notifier traceNotifier
+	value    atomic.Value
+}
+
+// SetValue sets the [messages.TraceValueKind], unknown values turn the trace off.
+func (t *tracer) SetValue(value messages.TraceValueKind) {
+	switch value {
+	case messages.TraceValueMessages, messages.TraceValueVerbose:
+	default:
+		value = messages.TraceValueOff
+	}
+	t.value.Store(value)
+}
+
+// GetValue returns the current [messages.TraceValueKind].
+func (t *tracer) GetValue() messages.TraceValueKind {
+	return t.value.Load().(messages.TraceValueKind)
+}
+
+// Wrap wraps the [mux.HandlerFunc] and reports its execution to the client, if the trace is on.
+func (t *tracer) Wrap(method string, handler mux.HandlerFunc) mux.HandlerFunc {
+	return func(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) (any, error) {
+		if t.GetValue() == messages.TraceValueOff {
+			return handler(ctx, conn, req)
+		}
+		span, ctx := logging.StartSpan(ctx, method)
+		result, err := handler(ctx, conn, req)
+		span.Finish()
+
+		// The trace value might have changed while the request was being handled.
+		value := t.GetValue()
+		if value == messages.TraceValueOff {
+			return result, err
+		}
+		params := messages.LogTraceParams{
+			Message: traceMessage(req, span.Record(), err),
+		}
+		if value == messages.TraceValueVerbose {
+			params.Verbose = span.Record().String()
+			params.Verbose += "\n\nParams: " + summarizePayload(req.Params)
+			if !req.Notif {
+				params.Verbose += "\n\nResult: " + summarizePayload(result)
+			}
+		}
+		if notifyErr := t.notifier.Notify(ctx, messages.LogTraceMethod, params); notifyErr != nil {
+			slog.ErrorContext(ctx, "failed to send trace", slog.Any("error", notifyErr))
+		}
+		return result, err
+	}
+}
+
+// traceMessage creates the [messages.LogTraceParams.Message].
+// Example: "Handled request 'textDocument/hover - (12)' in 3.25ms."
+func traceMessage(req *jsonrpc2.Request, record *logging.SpanRecord, err error) string {
+	var msg string
+	if req.Notif {
+		msg = fmt.Sprintf("Handled notification '%s' in %s.", req.Method, record.Duration())
+	} else {
+		msg = fmt.Sprintf("Handled request '%s - (%s)' in %s.", req.Method, req.ID, record.Duration())
+	}
+	if err != nil {
+		msg += fmt.Sprintf(" Request failed: %v.", err)
+	}
+	return msg
+}
+
+// summarizePayload encodes the payload to JSON and truncates it to [maxTracePayloadLength].
+func summarizePayload(payload any) string {
+	var data []byte
+	switch v := payload.(type) {
+	case *json.RawMessage:
+		if v == nil {
+			return "null"
+		}
+		data = *v
+	default:
+		var err error
+		data, err = json.Marshal(v)
+		if err != nil {
+			return fmt.Sprintf("failed to encode payload: %v", err)
+		}
+	}
+	summary := strings.TrimSpace(string(data))
+	if len(summary) <= maxTracePayloadLength {
+		return summary
+	}
+	// Don't cut the summary in the middle of a multi-byte character.
+	end := maxTracePayloadLength
+	for end > 0 && !utf8.RuneStart(summary[end]) {
+		end--
+	}
+	return fmt.Sprintf("%s... (truncated, %d bytes in total)", summary[:end], len(summary))
+}
@@ -0,0 +1,99 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/sourcegraph/jsonrpc2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nobl9/nobl9-language-server/internal/logging"
+	"github.com/nobl9/nobl9-language-server/internal/messages"
+)
+
+func TestTracer_Wrap(t *testing.T) {
+	params := json.RawMessage(`{"textDocument":{"uri":"file:///foo.yaml"}}`)
+	req := &jsonrpc2.Request{
+		Method: messages.HoverMethod,
+		ID:     jsonrpc2.ID{Num: 12},
+		Params: &params,
+	}
+	handler := func(ctx context.Context, _ *jsonrpc2.Conn, _ *jsonrpc2.Request) (any, error) {
+		span, _ := logging.StartSpan(ctx, "provide_hover")
+		span.Finish()
+		return "result", nil
+	}
+
+	t.Run("trace is off", func(t *testing.T) {
+		notifier := &fakeTraceNotifier{}
+		tracer := newTracer(notifier)
+
+		result, err := tracer.Wrap(messages.HoverMethod, handler)(context.Background(), nil, req)
+		require.NoError(t, err)
+		assert.Equal(t, "result", result)
+		assert.Empty(t, notifier.params)
+	})
+	t.Run("messages", func(t *testing.T) {
+		notifier := &fakeTraceNotifier{}
+		tracer := newTracer(notifier)
+		tracer.SetValue(messages.TraceValueMessages)
+
+		result, err := tracer.Wrap(messages.HoverMethod, handler)(context.Background(), nil, req)
+		require.NoError(t, err)
+		assert.Equal(t, "result", result)
+		require.Len(t, notifier.params, 1)
+		trace := notifier.params[0]
+		assert.True(t, strings.HasPrefix(trace.Message, "Handled request 'textDocument/hover - (12)' in "))
+		assert.Empty(t, trace.Verbose)
+	})
+	t.Run("verbose", func(t *testing.T) {
+		notifier := &fakeTraceNotifier{}
+		tracer := newTracer(notifier)
+		tracer.SetValue(messages.TraceValueVerbose)
+
+		_, err := tracer.Wrap(messages.HoverMethod, handler)(context.Background(), nil, req)
+		require.NoError(t, err)
+		require.Len(t, notifier.params, 1)
+		lines := strings.Split(notifier.params[0].Verbose, "\n")
+		require.Greater(t, len(lines), 2)
+		assert.True(t, strings.HasPrefix(lines[0], "textDocument/hover "))
+		assert.True(t, strings.HasPrefix(lines[1], "  provide_hover "))
+		assert.Contains(t, notifier.params[0].Verbose, "\n\nParams: "+string(params))
+		assert.Contains(t, notifier.params[0].Verbose, "\n\nResult: \"result\"")
+	})
+	t.Run("unknown value turns the trace off", func(t *testing.T) {
+		tracer := newTracer(&fakeTraceNotifier{})
+		tracer.SetValue(messages.TraceValueVerbose)
+		tracer.SetValue("foo")
+		assert.Equal(t, messages.TraceValueOff, tracer.GetValue())
+	})
+}
+
+func TestSummarizePayload(t *testing.T) {
+	assert.Equal(t, "null", summarizePayload((*json.RawMessage)(nil)))
+	assert.Equal(t, `{"foo":"bar"}`, summarizePayload(map[string]string{"foo": "bar"}))
+
+	summary := summarizePayload(strings.Repeat("a", 2*maxTracePayloadLength))
+	assert.Len(t, summary, maxTracePayloadLength+len("... (truncated, 2002 bytes in total)"))
+	assert.True(t, strings.HasSuffix(summary, "... (truncated, 2002 bytes in total)"))
+
+	// The limit falls in the middle of a multi-byte character.
+	summary = summarizePayload(strings.Repeat("ä€", maxTracePayloadLength))
+	assert.True(t, utf8.ValidString(summary))
+	assert.True(t, strings.HasSuffix(summary, "ä... (truncated, 5002 bytes in total)"))
+}
+
+type fakeTraceNotifier struct {
+	params []messages.LogTraceParams
+}
+
+func (f *fakeTraceNotifier) Notify(_ context.Context, method string, params any) error {
+	if method == messages.LogTraceMethod {
+		f.params = append(f.params, params.(messages.LogTraceParams))
+	}
+	return nil
+}
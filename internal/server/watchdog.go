@@ -0,0 +1,31 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/nobl9/nobl9-language-server/internal/recovery"
+)
+
+// parentProcessCheckInterval is the interval at which we check if the client's process is still running.
+const parentProcessCheckInterval = 5 * time.Second
+
+// watchParentProcess terminates the server once the client's process is no longer running.
+// Without it, the server would be left orphaned if the editor crashed without sending [messages.ExitMethod].
+func (s *Server) watchParentProcess(pid int) {
+	ctx := context.Background()
+	defer func() { recovery.LogPanic(ctx, recover()) }()
+
+	ticker := time.NewTicker(parentProcessCheckInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		if isProcessRunning(pid) {
+			continue
+		}
+		slog.WarnContext(ctx, "parent process is no longer running", slog.Int("pid", pid))
+		s.exit(fmt.Errorf("parent process %d is no longer running", pid))
+		return
+	}
+}
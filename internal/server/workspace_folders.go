@@ -0,0 +1,264 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/sourcegraph/jsonrpc2"
+
+	"github.com/nobl9/nobl9-language-server/internal/config"
+	"github.com/nobl9/nobl9-language-server/internal/files"
+	"github.com/nobl9/nobl9-language-server/internal/messages"
+	"github.com/nobl9/nobl9-language-server/internal/mux"
+	"github.com/nobl9/nobl9-language-server/internal/nobl9repo"
+	"github.com/nobl9/nobl9-language-server/internal/recovery"
+)
+
+// workspaceFolders keeps track of the workspace folders opened by the client
+// along with the [nobl9repo.Scope] configured for each of them.
+// Files which don't belong to any folder use the default scope.
+type workspaceFolders struct {
+	folders      []*workspaceFolder
+	defaultScope nobl9repo.Scope
+	// generation is incremented whenever the folders or their scopes change.
+	generation atomic.Uint64
+	mu         sync.RWMutex
+}
+
+type workspaceFolder struct {
+	messages.WorkspaceFolder
+	scope nobl9repo.Scope
+}
+
+// Set replaces all the folders.
+func (w *workspaceFolders) Set(folders []messages.WorkspaceFolder) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.generation.Add(1)
+	w.folders = make([]*workspaceFolder, 0, len(folders))
+	for _, folder := range folders {
+		w.folders = append(w.folders, &workspaceFolder{WorkspaceFolder: folder})
+	}
+}
+
+// Change adds and removes the folders.
+// It returns the URIs of the removed folders which are not nested in any of the remaining folders.
+func (w *workspaceFolders) Change(event messages.WorkspaceFoldersChangeEvent) (removed []files.URI) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.generation.Add(1)
+	for _, folder := range event.Removed {
+		w.folders = slices.DeleteFunc(w.folders, func(f *workspaceFolder) bool { return f.URI == folder.URI })
+	}
+	for _, folder := range event.Added {
+		if !slices.ContainsFunc(w.folders, func(f *workspaceFolder) bool { return f.URI == folder.URI }) {
+			w.folders = append(w.folders, &workspaceFolder{WorkspaceFolder: folder})
+		}
+	}
+	for _, folder := range event.Removed {
+		if w.findFolder(folder.URI) == nil {
+			removed = append(removed, folder.URI)
+		}
+	}
+	return removed
+}
+
+// Get returns all the folders.
+func (w *workspaceFolders) Get() []messages.WorkspaceFolder {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	folders := make([]messages.WorkspaceFolder, 0, len(w.folders))
+	for _, folder := range w.folders {
+		folders = append(folders, folder.WorkspaceFolder)
+	}
+	return folders
+}
+
+// URIs returns the URIs of all the folders.
+func (w *workspaceFolders) URIs() []files.URI {
+	folders := w.Get()
+	uris := make([]files.URI, 0, len(folders))
+	for _, folder := range folders {
+		uris = append(uris, folder.URI)
+	}
+	return uris
+}
+
+// SetDefaultScope sets the scope used for the files which don't belong to any folder,
+// as well as for the folders which don't configure their own [config.Settings.Project] or [config.Settings.Context].
+func (w *workspaceFolders) SetDefaultScope(settings config.Settings) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.generation.Add(1)
+	w.defaultScope = newScope(settings)
+}
+
+// SetScope sets the scope of the folder.
+func (w *workspaceFolders) SetScope(uri files.URI, settings config.Settings) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.generation.Add(1)
+	for _, folder := range w.folders {
+		if folder.URI == uri {
+			folder.scope = newScope(settings)
+		}
+	}
+}
+
+// Generation identifies the state of the folders and their scopes.
+// It changes whenever the scope of any file might have changed.
+func (w *workspaceFolders) Generation() uint64 {
+	return w.generation.Load()
+}
+
+// Scopes returns all distinct scopes in use.
+func (w *workspaceFolders) Scopes() []nobl9repo.Scope {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	scopes := []nobl9repo.Scope{w.defaultScope}
+	for _, folder := range w.folders {
+		if scope := w.mergeScope(folder.scope); !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes
+}
+
+// WithFileScope binds the scope of the innermost folder containing the file to the context.
+func (w *workspaceFolders) WithFileScope(ctx context.Context, uri files.URI) context.Context {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	scope := w.defaultScope
+	if folder := w.findFolder(uri); folder != nil {
+		scope = w.mergeScope(folder.scope)
+	}
+	return nobl9repo.WithScope(ctx, scope)
+}
+
+func (w *workspaceFolders) mergeScope(scope nobl9repo.Scope) nobl9repo.Scope {
+	if scope.ConfigContext == "" {
+		scope.ConfigContext = w.defaultScope.ConfigContext
+	}
+	if scope.Project == "" {
+		scope.Project = w.defaultScope.Project
+	}
+	return scope
+}
+
+// findFolder returns the innermost folder containing the URI.
+func (w *workspaceFolders) findFolder(uri files.URI) *workspaceFolder {
+	var found *workspaceFolder
+	for _, folder := range w.folders {
+		if !isWithinFolder(folder.URI, uri) {
+			continue
+		}
+		if found == nil || len(folder.URI) > len(found.URI) {
+			found = folder
+		}
+	}
+	return found
+}
+
+func isWithinFolder(folderURI, uri files.URI) bool {
+	folderURI, uri = files.CanonicalURI(folderURI), files.CanonicalURI(uri)
+	return uri == folderURI || strings.HasPrefix(uri, strings.TrimSuffix(folderURI, "/")+"/")
+}
+
+func newScope(settings config.Settings) nobl9repo.Scope {
+	return nobl9repo.Scope{
+		ConfigContext: settings.Context,
+		Project:       settings.Project,
+	}
+}
+
+// getWorkspaceFolders returns the workspace folders provided by the client.
+// If the client does not support workspace folders, [messages.InitializeParams.RootURI] is used instead.
+func getWorkspaceFolders(params messages.InitializeParams) []messages.WorkspaceFolder {
+	if len(params.WorkspaceFolders) > 0 {
+		return params.WorkspaceFolders
+	}
+	if params.RootURI != "" {
+		return []messages.WorkspaceFolder{{
+			URI:  params.RootURI,
+			Name: path.Base(params.RootURI),
+		}}
+	}
+	return nil
+}
+
+// handleDidChangeWorkspaceFolders drops the index of the removed folders
+// and sets up the added ones the same way the initial folders are.
+func (s *Server) handleDidChangeWorkspaceFolders(
+	_ context.Context,
+	params messages.DidChangeWorkspaceFoldersParams,
+) (interface{}, error) {
+	for _, uri := range s.folders.Change(params.Event) {
+		s.files.RemoveIndexedPath(uri)
+	}
+	// The files of the removed folders might now fall back to a different scope.
+	s.handlers.InvalidateDiagnostics()
+	// Notifications are handled synchronously, we can't wait for the client's response here.
+	go s.setupWorkspaceFolders(params.Event.Added)
+	return nil, nil
+}
+
+// setupWorkspaceFolders loads the settings of the folders and indexes them.
+// Diagnostics are refreshed afterwards, as the references might now resolve to different objects.
+func (s *Server) setupWorkspaceFolders(folders []messages.WorkspaceFolder) {
+	ctx := context.Background()
+	defer func() { recovery.LogPanic(ctx, recover()) }()
+
+	s.loadFolderSettings(ctx, folders)
+	for _, folder := range folders {
+		s.indexWorkspaceRoot(folder.URI)
+	}
+	s.refreshDiagnostics(ctx)
+}
+
+// withFileScope binds the [nobl9repo.Scope] of the request document's workspace folder to the context.
+// The document is read from the textDocument parameter of the textDocument/* requests or,
+// for commands, from the first argument.
+// Notifications are not bound to any scope, the diagnostics they trigger bind it themselves.
+func (s *Server) withFileScope(handler mux.HandlerFunc) mux.HandlerFunc {
+	return func(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) (any, error) {
+		return handler(s.folders.WithFileScope(ctx, getRequestDocumentURI(req)), conn, req)
+	}
+}
+
+const textDocumentMethodPrefix = "textDocument/"
+
+// getRequestDocumentURI decodes only the document URI from the request parameters.
+func getRequestDocumentURI(req *jsonrpc2.Request) files.URI {
+	if req.Params == nil || req.Notif {
+		return ""
+	}
+	switch {
+	case strings.HasPrefix(req.Method, textDocumentMethodPrefix):
+		var params struct {
+			TextDocument struct {
+				URI files.URI `json:"uri"`
+			} `json:"textDocument"`
+		}
+		if err := json.Unmarshal(*req.Params, &params); err != nil {
+			return ""
+		}
+		return params.TextDocument.URI
+	case req.Method == messages.ExecuteCommandMethod:
+		var params struct {
+			Arguments []json.RawMessage `json:"arguments"`
+		}
+		if err := json.Unmarshal(*req.Params, &params); err != nil || len(params.Arguments) == 0 {
+			return ""
+		}
+		var uri files.URI
+		_ = json.Unmarshal(params.Arguments[0], &uri)
+		return uri
+	default:
+		return ""
+	}
+}
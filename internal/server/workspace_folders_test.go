@@ -0,0 +1,114 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/sourcegraph/jsonrpc2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nobl9/nobl9-language-server/internal/config"
+	"github.com/nobl9/nobl9-language-server/internal/messages"
+	"github.com/nobl9/nobl9-language-server/internal/nobl9repo"
+)
+
+func TestWorkspaceFolders(t *testing.T) {
+	folders := &workspaceFolders{}
+	folders.Set([]messages.WorkspaceFolder{
+		{URI: "file:///staging", Name: "staging"},
+		{URI: "file:///production", Name: "production"},
+		{URI: "file:///production/nested", Name: "nested"},
+	})
+	folders.SetDefaultScope(config.Settings{Context: "default", Project: "default"})
+	folders.SetScope("file:///staging", config.Settings{Context: "staging"})
+	folders.SetScope("file:///production", config.Settings{Context: "production", Project: "prod"})
+
+	t.Run("file scope", func(t *testing.T) {
+		for uri, expected := range map[string]nobl9repo.Scope{
+			"file:///staging/slo.yaml":           {ConfigContext: "staging", Project: "default"},
+			"file:///st%61ging/slo.yaml":         {ConfigContext: "staging", Project: "default"},
+			"file:///production/slo.yaml":        {ConfigContext: "production", Project: "prod"},
+			"file:///production/nested/slo.yaml": {ConfigContext: "default", Project: "default"},
+			"file:///production-2/slo.yaml":      {ConfigContext: "default", Project: "default"},
+			"":                                   {ConfigContext: "default", Project: "default"},
+		} {
+			ctx := folders.WithFileScope(context.Background(), uri)
+			assert.Equal(t, expected, nobl9repo.GetScope(ctx), uri)
+		}
+	})
+	t.Run("scopes", func(t *testing.T) {
+		assert.Equal(t, []nobl9repo.Scope{
+			{ConfigContext: "default", Project: "default"},
+			{ConfigContext: "staging", Project: "default"},
+			{ConfigContext: "production", Project: "prod"},
+		}, folders.Scopes())
+	})
+	t.Run("change", func(t *testing.T) {
+		folders := &workspaceFolders{}
+		folders.Set([]messages.WorkspaceFolder{
+			{URI: "file:///staging"},
+			{URI: "file:///production"},
+			{URI: "file:///production/nested"},
+		})
+		removed := folders.Change(messages.WorkspaceFoldersChangeEvent{
+			Added:   []messages.WorkspaceFolder{{URI: "file:///dev"}, {URI: "file:///staging"}},
+			Removed: []messages.WorkspaceFolder{{URI: "file:///staging"}, {URI: "file:///production/nested"}},
+		})
+		assert.Equal(t, []string{"file:///production", "file:///dev", "file:///staging"}, folders.URIs())
+		assert.Empty(t, removed)
+
+		generation := folders.Generation()
+		removed = folders.Change(messages.WorkspaceFoldersChangeEvent{
+			Removed: []messages.WorkspaceFolder{{URI: "file:///dev"}},
+		})
+		assert.Equal(t, []string{"file:///dev"}, removed)
+		assert.Greater(t, folders.Generation(), generation)
+	})
+}
+
+func TestGetRequestDocumentURI(t *testing.T) {
+	tests := map[string]struct {
+		method   string
+		notif    bool
+		params   string
+		expected string
+	}{
+		"text document": {
+			method:   messages.HoverMethod,
+			params:   `{"textDocument":{"uri":"file:///foo.yaml"},"position":{"line":1,"character":2}}`,
+			expected: "file:///foo.yaml",
+		},
+		"command": {
+			method:   messages.ExecuteCommandMethod,
+			params:   `{"command":"APPLY","arguments":["file:///foo.yaml"]}`,
+			expected: "file:///foo.yaml",
+		},
+		"command without arguments": {
+			method: messages.ExecuteCommandMethod,
+			params: `{"command":"APPLY"}`,
+		},
+		"notification": {
+			method: messages.DidOpenMethod,
+			notif:  true,
+			params: `{"textDocument":{"uri":"file:///foo.yaml","text":"foo"}}`,
+		},
+		"other method": {
+			method: messages.WorkspaceSymbolMethod,
+			params: `{"query":"foo","textDocument":{"uri":"file:///foo.yaml"}}`,
+		},
+		"no params": {
+			method: messages.HoverMethod,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := &jsonrpc2.Request{Method: tc.method, Notif: tc.notif}
+			if tc.params != "" {
+				params := json.RawMessage(tc.params)
+				req.Params = &params
+			}
+			assert.Equal(t, tc.expected, getRequestDocumentURI(req))
+		})
+	}
+}
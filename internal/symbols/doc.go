@@ -0,0 +1,2 @@
+// Package symbols provides document and workspace symbols for Nobl9 objects.
+package symbols
@@ -0,0 +1,231 @@
+package symbols
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/nobl9/nobl9-language-server/internal/files"
+	"github.com/nobl9/nobl9-language-server/internal/messages"
+	"github.com/nobl9/nobl9-language-server/internal/yamlastsimple"
+)
+
+// buildDocumentSymbols creates a [messages.DocumentSymbol] for every object in the file.
+// Named list elements, like SLO objectives or AlertPolicy alert methods,
+// are added as the object's children.
+func buildDocumentSymbols(file files.SimpleObjectFile) []messages.DocumentSymbol {
+	symbols := make([]messages.DocumentSymbol, 0, len(file))
+	for _, node := range file {
+		if symbol, ok := buildObjectSymbol(node); ok {
+			symbols = append(symbols, symbol)
+		}
+	}
+	return symbols
+}
+
+// toClientSymbols converts the symbols' ranges, including their children, to the client's position encoding.
+func toClientSymbols(symbols []messages.DocumentSymbol, mapper *files.PositionMapper) {
+	for i := range symbols {
+		symbols[i].Range = mapper.ToClientRange(symbols[i].Range)
+		symbols[i].SelectionRange = mapper.ToClientRange(symbols[i].SelectionRange)
+		toClientSymbols(symbols[i].Children, mapper)
+	}
+}
+
+func buildObjectSymbol(node *files.SimpleObjectNode) (messages.DocumentSymbol, bool) {
+	first, last := -1, -1
+	for i, line := range node.Doc.Lines {
+		if line.IsType(yamlastsimple.LineTypeEmpty |
+			yamlastsimple.LineTypeComment |
+			yamlastsimple.LineTypeDocSeparator) {
+			continue
+		}
+		if first == -1 {
+			first = i
+		}
+		last = i
+	}
+	if first == -1 {
+		return messages.DocumentSymbol{}, false
+	}
+
+	symbol := messages.DocumentSymbol{
+		Name:           node.Kind.String(),
+		Kind:           messages.SymbolKindObject,
+		Range:          getLinesRange(node.Doc, first, last),
+		SelectionRange: getKeyRange(node.Doc, first),
+	}
+	if node.Kind == 0 {
+		symbol.Name = "Unknown object"
+	}
+	var name, project string
+	for i, line := range node.Doc.Lines {
+		switch line.GeneralizedPath {
+		case "$.metadata.name":
+			name = getScalarValue(line)
+			if name != "" {
+				symbol.SelectionRange = getValueRange(node.Doc, i)
+			}
+		case "$.metadata.project":
+			project = getScalarValue(line)
+		}
+	}
+	if name != "" {
+		symbol.Detail = symbol.Name
+		symbol.Name = name
+		if project != "" {
+			symbol.Name = project + "/" + name
+		}
+	}
+	symbol.Children = buildListElementSymbols(node.Doc)
+	return symbol, true
+}
+
+// listElementSymbol is a [messages.DocumentSymbol] along with the path prefix of the list element.
+type listElementSymbol struct {
+	prefix string
+	symbol messages.DocumentSymbol
+}
+
+// buildListElementSymbols creates a [messages.DocumentSymbol] for each list element which has a name.
+// Example:
+//
+//	objectives:
+//	  - name: good
+//	    target: 0.9
+//
+// Elements nested in other named elements become their children.
+func buildListElementSymbols(doc *yamlastsimple.Document) []messages.DocumentSymbol {
+	var elements []*listElementSymbol
+	for i, line := range doc.Lines {
+		prefix, ok := getNamedListElementPrefix(line)
+		if !ok {
+			continue
+		}
+		name := getScalarValue(line)
+		if name == "" {
+			continue
+		}
+		first, last := findPathLinesSpan(doc, prefix)
+		elements = append(elements, &listElementSymbol{
+			prefix: prefix,
+			symbol: messages.DocumentSymbol{
+				Name:           name,
+				Detail:         getListName(prefix),
+				Kind:           messages.SymbolKindField,
+				Range:          getLinesRange(doc, first, last),
+				SelectionRange: getValueRange(doc, i),
+			},
+		})
+	}
+	return nestListElementSymbols(elements)
+}
+
+// nestListElementSymbols arranges the symbols in a tree based on their path prefixes.
+func nestListElementSymbols(elements []*listElementSymbol) []messages.DocumentSymbol {
+	var symbols []messages.DocumentSymbol
+	for i := 0; i < len(elements); {
+		parent := elements[i]
+		j := i + 1
+		for ; j < len(elements) && isPathChild(parent.prefix, elements[j].prefix); j++ {
+		}
+		parent.symbol.Children = nestListElementSymbols(elements[i+1 : j])
+		symbols = append(symbols, parent.symbol)
+		i = j
+	}
+	return symbols
+}
+
+const (
+	nameSuffix         = ".name"
+	metadataNameSuffix = ".metadata.name"
+)
+
+// getNamedListElementPrefix returns the path of the list element which is named by the given line.
+// Example: $.spec.objectives[0].name -> $.spec.objectives[0]
+func getNamedListElementPrefix(line *yamlastsimple.Line) (string, bool) {
+	if !line.HasMapValue() {
+		return "", false
+	}
+	var suffix string
+	switch {
+	case strings.HasSuffix(line.GeneralizedPath, "[*]"+metadataNameSuffix):
+		suffix = metadataNameSuffix
+	case strings.HasSuffix(line.GeneralizedPath, "[*]"+nameSuffix):
+		suffix = nameSuffix
+	default:
+		return "", false
+	}
+	return strings.TrimSuffix(line.Path, suffix), true
+}
+
+// getListName returns the name of the list the element belongs to.
+// Example: $.spec.objectives[0] -> objectives
+func getListName(prefix string) string {
+	if i := strings.LastIndex(prefix, "["); i != -1 {
+		prefix = prefix[:i]
+	}
+	return prefix[strings.LastIndex(prefix, ".")+1:]
+}
+
+// findPathLinesSpan returns the first and last line indexes which belong to the given path.
+func findPathLinesSpan(doc *yamlastsimple.Document, path string) (first, last int) {
+	first, last = -1, -1
+	for i, line := range doc.Lines {
+		if line.Path != path && !isPathChild(path, line.Path) {
+			continue
+		}
+		if first == -1 {
+			first = i
+		}
+		last = i
+	}
+	return first, last
+}
+
+func isPathChild(parent, path string) bool {
+	return strings.HasPrefix(path, parent+".") || strings.HasPrefix(path, parent+"[")
+}
+
+// getLinesRange returns a [messages.Range] which spans over whole lines, from first to last (inclusive).
+func getLinesRange(doc *yamlastsimple.Document, first, last int) messages.Range {
+	return messages.Range{
+		Start: messages.Position{Line: doc.Offset + first},
+		End:   messages.Position{Line: doc.Offset + last + 1},
+	}
+}
+
+func getKeyRange(doc *yamlastsimple.Document, i int) messages.Range {
+	start, end := doc.Lines[i].GetKeyPos()
+	return messages.Range{
+		Start: messages.Position{Line: doc.Offset + i, Character: start},
+		End:   messages.Position{Line: doc.Offset + i, Character: end},
+	}
+}
+
+// getValueRange returns a [messages.Range] of the line's scalar value, excluding comments and quotes.
+func getValueRange(doc *yamlastsimple.Document, i int) messages.Range {
+	line := doc.Lines[i]
+	start, _ := line.GetValuePos()
+	value := getScalarValue(line)
+	mapValue := line.GetMapValue()
+	start += utf8.RuneCountInString(mapValue[:max(strings.Index(mapValue, value), 0)])
+	end := start + utf8.RuneCountInString(value)
+	return messages.Range{
+		Start: messages.Position{Line: doc.Offset + i, Character: start},
+		End:   messages.Position{Line: doc.Offset + i, Character: end},
+	}
+}
+
+// getScalarValue returns the mapping value stripped of comments and quotes.
+func getScalarValue(line *yamlastsimple.Line) string {
+	value := line.GetMapValue()
+	if i := strings.Index(value, " #"); i != -1 {
+		value = value[:i]
+	}
+	value = strings.TrimSpace(value)
+	if len(value) >= 2 &&
+		(value[0] == '"' && value[len(value)-1] == '"' || value[0] == '\'' && value[len(value)-1] == '\'') {
+		value = value[1 : len(value)-1]
+	}
+	return value
+}
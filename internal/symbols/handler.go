@@ -0,0 +1,42 @@
+package symbols
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/nobl9/nobl9-language-server/internal/files"
+	"github.com/nobl9/nobl9-language-server/internal/messages"
+	"github.com/nobl9/nobl9-language-server/internal/workspace"
+)
+
+func NewHandler(files *files.FS, snapshots *workspace.Snapshots) *Handler {
+	return &Handler{files: files, snapshots: snapshots}
+}
+
+type Handler struct {
+	files     *files.FS
+	snapshots *workspace.Snapshots
+}
+
+// HandleDocumentSymbol returns a hierarchical outline of all the objects defined in the file.
+func (h *Handler) HandleDocumentSymbol(ctx context.Context, params messages.DocumentSymbolParams) (any, error) {
+	file, err := h.files.GetFile(params.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+	ctx = file.AddToLogContext(ctx)
+	if file.Skip {
+		slog.DebugContext(ctx, "skipping file")
+		return nil, nil
+	}
+	symbols := buildDocumentSymbols(file.SimpleAST)
+	toClientSymbols(symbols, file.GetPositionMapper())
+	return symbols, nil
+}
+
+// HandleWorkspaceSymbol returns all the objects defined in the workspace which match the query.
+// Both opened and indexed files are searched.
+func (h *Handler) HandleWorkspaceSymbol(_ context.Context, params messages.WorkspaceSymbolParams) (any, error) {
+	snapshot := h.snapshots.Get()
+	return findWorkspaceSymbols(snapshot, params.Query), nil
+}
@@ -0,0 +1,194 @@
+package symbols
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nobl9/nobl9-language-server/internal/files"
+	"github.com/nobl9/nobl9-language-server/internal/messages"
+	"github.com/nobl9/nobl9-language-server/internal/testutils"
+	"github.com/nobl9/nobl9-language-server/internal/workspace"
+)
+
+var testDir = filepath.Join(testutils.FindModuleRoot(), "internal", "symbols", "testdata")
+
+func TestHandler_HandleDocumentSymbol(t *testing.T) {
+	t.Parallel()
+
+	fileSystem := files.NewFS(nil)
+	testutils.RegisterTestFiles(t, fileSystem, testDir)
+	handler := NewHandler(fileSystem, workspace.NewSnapshots(fileSystem))
+
+	tests := map[string]struct {
+		uri      string
+		expected []messages.DocumentSymbol
+	}{
+		"multiple documents": {
+			uri: getTestFileURI("slos.yaml"),
+			expected: []messages.DocumentSymbol{
+				{
+					Name:           "default",
+					Detail:         "Project",
+					Kind:           messages.SymbolKindObject,
+					Range:          newLinesRange(1, 4),
+					SelectionRange: messages.NewLineRange(4, 8, 15),
+				},
+				{
+					Name:           "default/api-server",
+					Detail:         "SLO",
+					Kind:           messages.SymbolKindObject,
+					Range:          newLinesRange(7, 19),
+					SelectionRange: messages.NewLineRange(10, 9, 19),
+					Children: []messages.DocumentSymbol{
+						{
+							Name:           "good",
+							Detail:         "objectives",
+							Kind:           messages.SymbolKindField,
+							Range:          newLinesRange(15, 17),
+							SelectionRange: messages.NewLineRange(16, 12, 16),
+						},
+						{
+							Name:           "excellent",
+							Detail:         "objectives",
+							Kind:           messages.SymbolKindField,
+							Range:          newLinesRange(18, 19),
+							SelectionRange: messages.NewLineRange(18, 12, 21),
+						},
+					},
+				},
+				{
+					Name:           "Service",
+					Kind:           messages.SymbolKindObject,
+					Range:          newLinesRange(21, 24),
+					SelectionRange: messages.NewLineRange(21, 0, 10),
+				},
+			},
+		},
+		"list document": {
+			uri: getTestFileURI("list.yaml"),
+			expected: []messages.DocumentSymbol{
+				{
+					Name:           "default/slow-burn",
+					Detail:         "AlertPolicy",
+					Kind:           messages.SymbolKindObject,
+					Range:          newLinesRange(1, 10),
+					SelectionRange: messages.NewLineRange(4, 10, 19),
+					Children: []messages.DocumentSymbol{
+						{
+							Name:           "email",
+							Detail:         "alertMethods",
+							Kind:           messages.SymbolKindField,
+							Range:          newLinesRange(8, 10),
+							SelectionRange: messages.NewLineRange(9, 16, 21),
+						},
+					},
+				},
+				{
+					Name:           "default/email",
+					Detail:         "AlertMethod",
+					Kind:           messages.SymbolKindObject,
+					Range:          newLinesRange(11, 15),
+					SelectionRange: messages.NewLineRange(14, 10, 15),
+				},
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			result, err := handler.HandleDocumentSymbol(context.Background(), messages.DocumentSymbolParams{
+				TextDocument: messages.TextDocumentIdentifier{URI: tc.uri},
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestHandler_HandleWorkspaceSymbol(t *testing.T) {
+	t.Parallel()
+
+	fileSystem := files.NewFS(nil)
+	testutils.RegisterTestFiles(t, fileSystem, testDir)
+	handler := NewHandler(fileSystem, workspace.NewSnapshots(fileSystem))
+
+	project := newTestSymbolInformation("default", "Project", getTestFileURI("slos.yaml"), messages.NewLineRange(4, 8, 15))
+	slo := newTestSymbolInformation("api-server", "SLO (project: default)",
+		getTestFileURI("slos.yaml"), messages.NewLineRange(10, 9, 19))
+	service := newTestSymbolInformation("Service", "Service", getTestFileURI("slos.yaml"), messages.NewPointRange(21, 0))
+	alertPolicy := newTestSymbolInformation("slow-burn", "AlertPolicy (project: default)",
+		getTestFileURI("list.yaml"), messages.NewLineRange(4, 10, 19))
+	alertMethod := newTestSymbolInformation("email", "AlertMethod (project: default)",
+		getTestFileURI("list.yaml"), messages.NewLineRange(14, 10, 15))
+
+	tests := map[string]struct {
+		query    string
+		expected []messages.SymbolInformation
+	}{
+		"empty query": {
+			query:    "",
+			expected: []messages.SymbolInformation{alertPolicy, alertMethod, project, slo, service},
+		},
+		"name": {
+			query:    "email",
+			expected: []messages.SymbolInformation{alertMethod},
+		},
+		"case insensitive name": {
+			query:    "API",
+			expected: []messages.SymbolInformation{slo, alertPolicy},
+		},
+		"fuzzy name": {
+			query:    "srv",
+			expected: []messages.SymbolInformation{service, slo},
+		},
+		"kind and name": {
+			query:    "slo",
+			expected: []messages.SymbolInformation{alertPolicy, slo},
+		},
+		"display name": {
+			query:    "no name",
+			expected: []messages.SymbolInformation{service},
+		},
+		"no matches": {
+			query:    "foo",
+			expected: []messages.SymbolInformation{},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			result, err := handler.HandleWorkspaceSymbol(context.Background(), messages.WorkspaceSymbolParams{
+				Query: tc.query,
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func newTestSymbolInformation(name, container, uri string, rng messages.Range) messages.SymbolInformation {
+	return messages.SymbolInformation{
+		Name:          name,
+		Kind:          messages.SymbolKindObject,
+		Location:      messages.Location{URI: uri, Range: rng},
+		ContainerName: &container,
+	}
+}
+
+// newLinesRange creates a [messages.Range] spanning from the first to the last line (inclusive, 1-based).
+func newLinesRange(first, last int) messages.Range {
+	return messages.Range{
+		Start: messages.Position{Line: first - 1},
+		End:   messages.Position{Line: last},
+	}
+}
+
+func getTestFileURI(name string) string {
+	return filepath.Join(testDir, name)
+}
@@ -0,0 +1,107 @@
+package symbols
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/nobl9/nobl9-language-server/internal/messages"
+	"github.com/nobl9/nobl9-language-server/internal/workspace"
+)
+
+type scoredSymbol struct {
+	symbol messages.SymbolInformation
+	score  int
+}
+
+// findWorkspaceSymbols returns a [messages.SymbolInformation] for every object whose name,
+// display name or kind fuzzy matches the query.
+// The best matches come first.
+func findWorkspaceSymbols(snapshot *workspace.Snapshot, query string) []messages.SymbolInformation {
+	var scored []scoredSymbol
+	for _, object := range snapshot.Objects() {
+		score, ok := matchObject(object, query)
+		if !ok {
+			continue
+		}
+		symbol := newSymbolInformation(object)
+		symbol.Location = snapshot.ToClientLocation(symbol.Location)
+		scored = append(scored, scoredSymbol{
+			symbol: symbol,
+			score:  score,
+		})
+	}
+	// Objects are already sorted by their location, stable sort preserves that order.
+	slices.SortStableFunc(scored, func(s1, s2 scoredSymbol) int {
+		return cmp.Compare(s2.score, s1.score)
+	})
+	symbols := make([]messages.SymbolInformation, 0, len(scored))
+	for _, s := range scored {
+		symbols = append(symbols, s.symbol)
+	}
+	return symbols
+}
+
+func matchObject(object *workspace.Object, query string) (int, bool) {
+	bestScore, matched := 0, false
+	for _, target := range []string{
+		object.ID.Name,
+		object.GetValueString("$.metadata.displayName"),
+		object.ID.Kind.String(),
+	} {
+		if target == "" {
+			continue
+		}
+		if score, ok := fuzzyMatch(query, target); ok && (!matched || score > bestScore) {
+			bestScore, matched = score, true
+		}
+	}
+	return bestScore, matched
+}
+
+func newSymbolInformation(object *workspace.Object) messages.SymbolInformation {
+	name := object.ID.Name
+	if name == "" {
+		name = object.ID.Kind.String()
+	}
+	container := object.ID.Kind.String()
+	if object.ID.Project != "" {
+		container = fmt.Sprintf("%s (project: %s)", container, object.ID.Project)
+	}
+	return messages.SymbolInformation{
+		Name:          name,
+		Kind:          messages.SymbolKindObject,
+		Location:      object.Location(),
+		ContainerName: &container,
+	}
+}
+
+// fuzzyMatch reports whether all the query characters appear in the target in the same order.
+// Matching is case-insensitive.
+// The returned score is higher for consecutive characters and matches at the start of the target.
+func fuzzyMatch(query, target string) (int, bool) {
+	queryRunes := []rune(strings.ToLower(query))
+	if len(queryRunes) == 0 {
+		return 0, true
+	}
+	score, qi, prev := 0, 0, -2
+	for ti, r := range []rune(strings.ToLower(target)) {
+		if r != queryRunes[qi] {
+			continue
+		}
+		score++
+		if ti == prev+1 {
+			score += 2
+		}
+		if ti == 0 {
+			score += 3
+		}
+		prev = ti
+		qi++
+		if qi == len(queryRunes) {
+			return score, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,4 @@
+// Package workspace provides a view over all Nobl9 objects defined in the files
+// known to the server and the references between them.
+// It is the foundation for navigation features like go-to-definition or find references.
+package workspace
@@ -0,0 +1,172 @@
+package workspace
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/nobl9/nobl9-language-server/internal/files"
+	"github.com/nobl9/nobl9-language-server/internal/logging"
+	"github.com/nobl9/nobl9-language-server/internal/messages"
+	"github.com/nobl9/nobl9-language-server/internal/progress"
+)
+
+// maxIndexedFileSize is the maximum size of a file which will be indexed.
+// Nobl9 configuration files are rarely bigger than a few hundred kilobytes.
+const maxIndexedFileSize = 5 * 1024 * 1024
+
+type progressReporter interface {
+	Begin(ctx context.Context, token messages.ProgressToken, title string, total int) (context.Context, *progress.Progress)
+}
+
+// NewIndexer creates a new [Indexer] which stores the indexed files in the provided [files.FS].
+func NewIndexer(files *files.FS, progressReporter progressReporter) *Indexer {
+	return &Indexer{
+		files:    files,
+		progress: progressReporter,
+	}
+}
+
+// Indexer reads all Nobl9 configuration files from the workspace directory.
+// Whether a file is a Nobl9 configuration file is decided by the [files.FS].
+type Indexer struct {
+	files    *files.FS
+	progress progressReporter
+}
+
+// Index walks the directory tree rooted at the provided URI and indexes every YAML file.
+// Hidden directories, like .git, are not traversed.
+// The progress is reported to the client once all the YAML files are found.
+func (i *Indexer) Index(ctx context.Context, rootURI files.URI) error {
+	span, ctx := logging.StartSpan(ctx, "workspace_index")
+	defer span.Finish()
+
+	root, err := files.FilePathFromURI(rootURI)
+	if err != nil {
+		return err
+	}
+	ctx = logging.ContextAttr(ctx, slog.String("root", root))
+	slog.DebugContext(ctx, "indexing workspace")
+
+	var paths []string
+	err = filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			slog.DebugContext(ctx, "failed to access path", slog.String("path", path), slog.Any("error", err))
+			return nil
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if entry.IsDir() {
+			if path != root && strings.HasPrefix(entry.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if isYAMLFile(path) {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to index workspace %s", root)
+	}
+
+	ctx, p := i.progress.Begin(ctx, nil, "Indexing Nobl9 files", len(paths))
+	indexed := 0
+	defer func() { p.End(ctx, fmt.Sprintf("Indexed %d files", indexed)) }()
+	for n, path := range paths {
+		if err = context.Cause(ctx); err != nil {
+			return errors.Wrapf(err, "failed to index workspace %s", root)
+		}
+		p.Report(ctx, n, fmt.Sprintf("Indexing %d/%d files", n+1, len(paths)))
+		ok, err := i.indexFile(ctx, path)
+		if err != nil {
+			slog.DebugContext(ctx, "failed to index file", slog.String("path", path), slog.Any("error", err))
+			continue
+		}
+		if ok {
+			indexed++
+		}
+	}
+	slog.InfoContext(ctx, "indexed workspace", slog.Int("indexedFiles", indexed))
+	return nil
+}
+
+// Update applies the file changes reported by the client to the index.
+// Just like with [Indexer.Index], only the YAML files from the workspace roots are indexed
+// and the hidden directories are skipped.
+func (i *Indexer) Update(ctx context.Context, rootURIs []files.URI, events []messages.FileEvent) {
+	roots := make([]string, 0, len(rootURIs))
+	for _, uri := range rootURIs {
+		root, err := files.FilePathFromURI(uri)
+		if err != nil {
+			slog.ErrorContext(ctx, "invalid workspace root", slog.String("uri", uri), slog.Any("error", err))
+			continue
+		}
+		roots = append(roots, root)
+	}
+	for _, event := range events {
+		path, err := files.FilePathFromURI(event.URI)
+		if err != nil {
+			slog.DebugContext(ctx, "invalid file URI", slog.String("uri", event.URI), slog.Any("error", err))
+			continue
+		}
+		if event.Type == messages.FileChangeTypeDeleted {
+			i.files.RemoveIndexedPath(files.URIFromFilePath(path))
+			continue
+		}
+		if !isYAMLFile(path) || !isWorkspaceFile(roots, path) {
+			continue
+		}
+		if _, err = i.indexFile(ctx, path); err != nil {
+			slog.DebugContext(ctx, "failed to index file", slog.String("path", path), slog.Any("error", err))
+		}
+	}
+}
+
+func (i *Indexer) indexFile(ctx context.Context, path string) (bool, error) {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return false, err
+	}
+	if !info.Mode().IsRegular() || info.Size() > maxIndexedFileSize {
+		return false, nil
+	}
+	data, err := os.ReadFile(path) // #nosec G304
+	if err != nil {
+		return false, err
+	}
+	return i.files.IndexFile(ctx, files.URIFromFilePath(path), string(data))
+}
+
+// isWorkspaceFile returns true if the path is inside one of the roots and not inside a hidden directory.
+func isWorkspaceFile(roots []string, path string) bool {
+	for _, root := range roots {
+		rel, err := filepath.Rel(root, path)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		dirs := strings.Split(filepath.Dir(rel), string(filepath.Separator))
+		if !slices.ContainsFunc(dirs, func(dir string) bool { return strings.HasPrefix(dir, ".") && dir != "." }) {
+			return true
+		}
+	}
+	return false
+}
+
+func isYAMLFile(path string) bool {
+	switch filepath.Ext(path) {
+	case ".yaml", ".yml":
+		return true
+	default:
+		return false
+	}
+}
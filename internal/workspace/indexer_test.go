@@ -0,0 +1,96 @@
+package workspace
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nobl9/nobl9-language-server/internal/files"
+	"github.com/nobl9/nobl9-language-server/internal/messages"
+	"github.com/nobl9/nobl9-language-server/internal/progress"
+)
+
+func TestIndexer_Index(t *testing.T) {
+	root := t.TempDir()
+	nobl9Content := "apiVersion: n9/v1alpha\nkind: Project\nmetadata:\n  name: default\n"
+	for path, content := range map[string]string{
+		"project.yaml":            nobl9Content,
+		"nested/dir/project.yml":  nobl9Content,
+		"other.yaml":              "foo: bar\n",
+		"project.txt":             nobl9Content,
+		".hidden/project.yaml":    nobl9Content,
+		"nested/.git/config.yaml": nobl9Content,
+	} {
+		path = filepath.Join(root, path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o750))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+
+	fileSystem := files.NewFS(nil)
+	err := NewIndexer(fileSystem, progress.NewReporter(nil)).Index(context.Background(), files.URIFromFilePath(root))
+	require.NoError(t, err)
+
+	var uris []files.URI
+	for _, file := range fileSystem.GetFiles() {
+		uris = append(uris, file.URI)
+		assert.Equal(t, nobl9Content, file.Content)
+		assert.NoError(t, file.Err)
+	}
+	slices.Sort(uris)
+	assert.Equal(t, []files.URI{
+		files.URIFromFilePath(filepath.Join(root, "nested", "dir", "project.yml")),
+		files.URIFromFilePath(filepath.Join(root, "project.yaml")),
+	}, uris)
+}
+
+func TestIndexer_Index_Cancelled(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "project.yaml"), []byte("apiVersion: n9/v1alpha"), 0o600))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := NewIndexer(files.NewFS(nil), progress.NewReporter(nil)).Index(ctx, files.URIFromFilePath(root))
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestIndexer_Update(t *testing.T) {
+	root := t.TempDir()
+	nobl9Content := "apiVersion: n9/v1alpha\nkind: Project\nmetadata:\n  name: default\n"
+	for path, content := range map[string]string{
+		"project.yaml":         nobl9Content,
+		"created.yaml":         nobl9Content,
+		"other.yaml":           "foo: bar\n",
+		".hidden/project.yaml": nobl9Content,
+	} {
+		path = filepath.Join(root, path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o750))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+	outsideRoot := filepath.Join(t.TempDir(), "project.yaml")
+	require.NoError(t, os.WriteFile(outsideRoot, []byte(nobl9Content), 0o600))
+
+	fileSystem := files.NewFS(nil)
+	indexer := NewIndexer(fileSystem, progress.NewReporter(nil))
+	rootURI := files.URIFromFilePath(root)
+	require.NoError(t, indexer.Index(context.Background(), rootURI))
+	require.NoError(t, os.Remove(filepath.Join(root, "project.yaml")))
+
+	indexer.Update(context.Background(), []files.URI{rootURI}, []messages.FileEvent{
+		{URI: files.URIFromFilePath(filepath.Join(root, "project.yaml")), Type: messages.FileChangeTypeDeleted},
+		{URI: files.URIFromFilePath(filepath.Join(root, "created.yaml")), Type: messages.FileChangeTypeCreated},
+		{URI: files.URIFromFilePath(filepath.Join(root, "other.yaml")), Type: messages.FileChangeTypeChanged},
+		{URI: files.URIFromFilePath(filepath.Join(root, ".hidden", "project.yaml")), Type: messages.FileChangeTypeCreated},
+		{URI: files.URIFromFilePath(outsideRoot), Type: messages.FileChangeTypeCreated},
+	})
+
+	var uris []files.URI
+	for _, file := range fileSystem.GetFiles() {
+		uris = append(uris, file.URI)
+	}
+	assert.Equal(t, []files.URI{files.URIFromFilePath(filepath.Join(root, "created.yaml"))}, uris)
+}
@@ -0,0 +1,208 @@
+package workspace
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/token"
+	"github.com/nobl9/nobl9-go/manifest"
+	"github.com/pkg/errors"
+
+	"github.com/nobl9/nobl9-language-server/internal/files"
+	"github.com/nobl9/nobl9-language-server/internal/messages"
+	"github.com/nobl9/nobl9-language-server/internal/objectref"
+)
+
+// ObjectID uniquely identifies a Nobl9 object.
+// Project is only set for project scoped kinds.
+type ObjectID struct {
+	Kind    manifest.Kind
+	Name    string
+	Project string
+}
+
+// NewObjectID creates a new [ObjectID], it drops the project for kinds which are not project scoped.
+func NewObjectID(kind manifest.Kind, name, project string) ObjectID {
+	if !objectref.IsProjectScoped(kind) {
+		project = ""
+	}
+	return ObjectID{Kind: kind, Name: name, Project: project}
+}
+
+// Object is a single Nobl9 object defined in a file along with all of its scalar values.
+type Object struct {
+	ID ObjectID
+	// URI and Node are not set for the objects created with [NewRemoteObject].
+	URI  files.URI
+	Node *files.ObjectNode
+	// Values are all the scalar values of the object, ordered as they appear in the file.
+	Values []*Value
+
+	valuesByPath map[string]*Value
+}
+
+// Value is a single scalar value of an [Object].
+type Value struct {
+	// Path is the actual path of the value within the object, e.g. $.spec.alertPolicies[0].
+	Path string
+	// GeneralizedPath is the [Value.Path] with list indices replaced by wildcards, e.g. $.spec.alertPolicies[*].
+	GeneralizedPath string
+	Value           string
+	// Range spans over the value itself, excluding quotes.
+	Range messages.Range
+}
+
+// GetValue returns the [Value] for the given path or nil if it does not exist.
+func (o *Object) GetValue(path string) *Value {
+	return o.valuesByPath[path]
+}
+
+// GetValueString returns the value for the given path or an empty string if it does not exist.
+func (o *Object) GetValueString(path string) string {
+	if v := o.GetValue(path); v != nil {
+		return v.Value
+	}
+	return ""
+}
+
+// FindValue returns the [Value] which spans over the given position.
+func (o *Object) FindValue(pos messages.Position) *Value {
+	for _, v := range o.Values {
+		if v.Range.Start.Line != pos.Line {
+			continue
+		}
+		if pos.Character >= v.Range.Start.Character && pos.Character <= v.Range.End.Character {
+			return v
+		}
+	}
+	return nil
+}
+
+// Location returns the location of the object's name.
+// If the name is not defined, it points to the first line of the object.
+func (o *Object) Location() messages.Location {
+	if v := o.GetValue("$.metadata.name"); v != nil {
+		return messages.Location{URI: o.URI, Range: v.Range}
+	}
+	return messages.Location{URI: o.URI, Range: messages.NewPointRange(o.Node.Node.StartLine, 0)}
+}
+
+// ObjectiveLocation returns the location of the SLO objective's name.
+func (o *Object) ObjectiveLocation(objective string) (messages.Location, bool) {
+	for _, v := range o.Values {
+		if v.GeneralizedPath == objectiveNamePath && v.Value == objective {
+			return messages.Location{URI: o.URI, Range: v.Range}, true
+		}
+	}
+	return messages.Location{}, false
+}
+
+// IsObjectiveName returns true if the [Value] holds the name of an SLO objective.
+func (o *Object) IsObjectiveName(v *Value) bool {
+	return o.ID.Kind == manifest.KindSLO && v.GeneralizedPath == objectiveNamePath
+}
+
+const objectiveNamePath = "$.spec.objectives[*].name"
+
+func newObject(uri files.URI, node *files.ObjectNode) *Object {
+	object := &Object{
+		URI:          uri,
+		Node:         node,
+		valuesByPath: make(map[string]*Value),
+	}
+	walkValues(node.Node.Node, "$", "$", func(v *Value) {
+		object.Values = append(object.Values, v)
+		object.valuesByPath[v.Path] = v
+	})
+	object.ID = NewObjectID(
+		node.Kind,
+		object.GetValueString("$.metadata.name"),
+		object.GetValueString("$.metadata.project"),
+	)
+	return object
+}
+
+// NewRemoteObject creates an [Object] from the [manifest.Object] which is not defined in any file,
+// e.g. one fetched from the Nobl9 API.
+// It allows resolving the object's references, its values have no [Value.Range].
+func NewRemoteObject(object manifest.Object) (*Object, error) {
+	data, err := json.Marshal(object)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode object")
+	}
+	var decoded any
+	if err = json.Unmarshal(data, &decoded); err != nil {
+		return nil, errors.Wrap(err, "failed to decode object")
+	}
+	remote := &Object{valuesByPath: make(map[string]*Value)}
+	walkDecodedValues(decoded, "$", "$", func(v *Value) {
+		remote.Values = append(remote.Values, v)
+		remote.valuesByPath[v.Path] = v
+	})
+	remote.ID = NewObjectID(
+		object.GetKind(),
+		object.GetName(),
+		remote.GetValueString("$.metadata.project"),
+	)
+	return remote, nil
+}
+
+// walkDecodedValues works like [walkValues] but traverses a decoded JSON value.
+// Object keys are visited in sorted order.
+func walkDecodedValues(value any, path, generalizedPath string, fn func(v *Value)) {
+	switch v := value.(type) {
+	case map[string]any:
+		for _, key := range slices.Sorted(maps.Keys(v)) {
+			walkDecodedValues(v[key], path+"."+key, generalizedPath+"."+key, fn)
+		}
+	case []any:
+		for i, item := range v {
+			walkDecodedValues(item, path+"["+strconv.Itoa(i)+"]", generalizedPath+"[*]", fn)
+		}
+	case string, float64, bool:
+		fn(&Value{
+			Path:            path,
+			GeneralizedPath: generalizedPath,
+			Value:           fmt.Sprint(v),
+		})
+	}
+}
+
+// walkValues traverses the [ast.Node] and calls the provided function for every scalar node.
+func walkValues(node ast.Node, path, generalizedPath string, fn func(v *Value)) {
+	switch v := node.(type) {
+	case *ast.MappingNode:
+		for _, value := range v.Values {
+			walkValues(value, path, generalizedPath, fn)
+		}
+	case *ast.MappingValueNode:
+		key := v.Key.GetToken().Value
+		walkValues(v.Value, path+"."+key, generalizedPath+"."+key, fn)
+	case *ast.SequenceNode:
+		for i, value := range v.Values {
+			walkValues(value, path+"["+strconv.Itoa(i)+"]", generalizedPath+"[*]", fn)
+		}
+	case *ast.TagNode:
+		walkValues(v.Value, path, generalizedPath, fn)
+	case *ast.StringNode, *ast.IntegerNode, *ast.FloatNode, *ast.BoolNode:
+		tk := v.GetToken()
+		if tk == nil || tk.Position == nil {
+			return
+		}
+		start := tk.Position.Column - 1
+		if tk.Type == token.DoubleQuoteType || tk.Type == token.SingleQuoteType {
+			start++
+		}
+		fn(&Value{
+			Path:            path,
+			GeneralizedPath: generalizedPath,
+			Value:           tk.Value,
+			Range:           messages.NewLineRange(tk.Position.Line, start, start+utf8.RuneCountInString(tk.Value)),
+		})
+	}
+}
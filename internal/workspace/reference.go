@@ -0,0 +1,115 @@
+package workspace
+
+import (
+	"github.com/nobl9/nobl9-go/manifest"
+
+	"github.com/nobl9/nobl9-language-server/internal/objectref"
+	"github.com/nobl9/nobl9-language-server/internal/yamlastsimple"
+)
+
+// Reference is a single reference from one [Object] to another.
+type Reference struct {
+	// Source is the object which defines the reference.
+	Source *Object
+	// Value is the referencing value.
+	Value *Value
+	// Target identifies the referenced object.
+	Target ObjectID
+	// Objective is set if the reference points to an SLO objective,
+	// in which case [Reference.Target] is the SLO defining the objective.
+	Objective string
+}
+
+const metricSourceNamePath = "$.spec.indicator.metricSource.name"
+
+// ResolveReference returns the [Reference] defined by the given [Value] of the [Object].
+// If the value does not reference any other object it returns nil.
+func ResolveReference(object *Object, value *Value) *Reference {
+	if value == nil || value.Value == "" {
+		return nil
+	}
+	ref := objectref.Get(object.ID.Kind, &yamlastsimple.Line{
+		Path:            value.Path,
+		GeneralizedPath: value.GeneralizedPath,
+	})
+	if ref == nil {
+		return nil
+	}
+	kind := ref.Kind
+	if object.ID.Kind == manifest.KindSLO && ref.Path == metricSourceNamePath {
+		kind = getMetricSourceKind(object)
+	}
+	if kind == 0 && ref.SLOPath == "" {
+		return nil
+	}
+	if ref.SLOPath != "" {
+		sloName := object.GetValueString(ref.SLOPath)
+		if sloName == "" {
+			return nil
+		}
+		return &Reference{
+			Source:    object,
+			Value:     value,
+			Target:    NewObjectID(manifest.KindSLO, sloName, getReferencedProject(object, ref, manifest.KindSLO)),
+			Objective: value.Value,
+		}
+	}
+	return &Reference{
+		Source: object,
+		Value:  value,
+		Target: NewObjectID(kind, value.Value, getReferencedProject(object, ref, kind)),
+	}
+}
+
+// ResolveTarget returns the object (or SLO objective) which the given [Value] either
+// defines (object or objective name) or references.
+// If the value neither defines nor references any object, ok is false.
+func ResolveTarget(object *Object, value *Value) (id ObjectID, objective string, ok bool) {
+	switch {
+	case value == nil || value.Value == "":
+		return id, "", false
+	case value.Path == "$.metadata.name":
+		return object.ID, "", true
+	case object.IsObjectiveName(value):
+		return object.ID, value.Value, true
+	}
+	ref := ResolveReference(object, value)
+	if ref == nil {
+		return id, "", false
+	}
+	return ref.Target, ref.Objective, true
+}
+
+// References returns all references defined by the [Object].
+func (o *Object) References() []*Reference {
+	var refs []*Reference
+	for _, value := range o.Values {
+		if ref := ResolveReference(o, value); ref != nil {
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+func getReferencedProject(object *Object, ref *objectref.Reference, kind manifest.Kind) string {
+	if ref.ProjectPath == "" {
+		return ""
+	}
+	project := object.GetValueString(ref.ProjectPath)
+	if project == "" {
+		project = object.GetValueString(ref.FallbackProjectPath(kind))
+	}
+	return project
+}
+
+func getMetricSourceKind(object *Object) manifest.Kind {
+	rawKind := object.GetValueString("$.spec.indicator.metricSource.kind")
+	if rawKind == "" {
+		return manifest.KindAgent
+	}
+	kind, err := manifest.ParseKind(rawKind)
+	if err != nil {
+		return 0
+	}
+	return kind
+}
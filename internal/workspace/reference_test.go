@@ -0,0 +1,140 @@
+package workspace
+
+import (
+	"context"
+	_ "embed"
+	"testing"
+
+	"github.com/nobl9/nobl9-go/manifest"
+	v1alphaSLO "github.com/nobl9/nobl9-go/manifest/v1alpha/slo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nobl9/nobl9-language-server/internal/files"
+	"github.com/nobl9/nobl9-language-server/internal/messages"
+)
+
+//go:embed testdata/objects.yaml
+var objectsTestInput string
+
+func TestObject_References(t *testing.T) {
+	file := &files.File{URI: "objects.yaml", Version: -1}
+	file.Update(context.Background(), 1, objectsTestInput)
+	require.NoError(t, file.Err)
+
+	snapshot := NewSnapshot([]*files.File{file})
+	require.Len(t, snapshot.Objects(), 2)
+
+	type expectedRef struct {
+		Path      string
+		Target    ObjectID
+		Objective string
+		Range     messages.Range
+	}
+	summarize := func(refs []*Reference) []expectedRef {
+		result := make([]expectedRef, 0, len(refs))
+		for _, ref := range refs {
+			result = append(result, expectedRef{
+				Path:      ref.Value.Path,
+				Target:    ref.Target,
+				Objective: ref.Objective,
+				Range:     ref.Value.Range,
+			})
+		}
+		return result
+	}
+
+	slo := snapshot.Objects()[0]
+	assert.Equal(t, ObjectID{Kind: manifest.KindSLO, Name: "latency", Project: "default"}, slo.ID)
+	assert.Equal(t, []expectedRef{
+		{
+			Path:   "$.metadata.project",
+			Target: ObjectID{Kind: manifest.KindProject, Name: "default"},
+			Range:  messages.NewLineRange(5, 11, 18),
+		},
+		{
+			Path:   "$.spec.service",
+			Target: ObjectID{Kind: manifest.KindService, Name: "api", Project: "default"},
+			Range:  messages.NewLineRange(7, 11, 14),
+		},
+		{
+			Path:   "$.spec.alertPolicies[0]",
+			Target: ObjectID{Kind: manifest.KindAlertPolicy, Name: "fast-burn", Project: "default"},
+			Range:  messages.NewLineRange(8, 18, 27),
+		},
+		{
+			Path:   "$.spec.alertPolicies[1]",
+			Target: ObjectID{Kind: manifest.KindAlertPolicy, Name: "slow-burn", Project: "default"},
+			Range:  messages.NewLineRange(8, 30, 39),
+		},
+		{
+			Path:   "$.spec.indicator.metricSource.name",
+			Target: ObjectID{Kind: manifest.KindDirect, Name: "datadog", Project: "integrations"},
+			Range:  messages.NewLineRange(12, 12, 19),
+		},
+		{
+			Path:   "$.spec.indicator.metricSource.project",
+			Target: ObjectID{Kind: manifest.KindProject, Name: "integrations"},
+			Range:  messages.NewLineRange(13, 15, 27),
+		},
+		{
+			Path:   "$.spec.objectives[0].composite.components.objectives[0].project",
+			Target: ObjectID{Kind: manifest.KindProject, Name: "other"},
+			Range:  messages.NewLineRange(21, 23, 28),
+		},
+		{
+			Path:   "$.spec.objectives[0].composite.components.objectives[0].slo",
+			Target: ObjectID{Kind: manifest.KindSLO, Name: "availability", Project: "other"},
+			Range:  messages.NewLineRange(22, 19, 31),
+		},
+		{
+			Path:      "$.spec.objectives[0].composite.components.objectives[0].objective",
+			Target:    ObjectID{Kind: manifest.KindSLO, Name: "availability", Project: "other"},
+			Objective: "ok",
+			Range:     messages.NewLineRange(23, 25, 27),
+		},
+	}, summarize(slo.References()))
+
+	silence := snapshot.Objects()[1]
+	assert.Equal(t, ObjectID{Kind: manifest.KindAlertSilence, Name: "silence", Project: "default"}, silence.ID)
+	assert.Equal(t, []expectedRef{
+		{
+			Path:   "$.metadata.project",
+			Target: ObjectID{Kind: manifest.KindProject, Name: "default"},
+			Range:  messages.NewLineRange(29, 13, 20),
+		},
+		{
+			Path:   "$.spec.slo",
+			Target: ObjectID{Kind: manifest.KindSLO, Name: "latency", Project: "default"},
+			Range:  messages.NewLineRange(31, 9, 16),
+		},
+		{
+			Path:   "$.spec.alertPolicy.name",
+			Target: ObjectID{Kind: manifest.KindAlertPolicy, Name: "fast-burn", Project: "default"},
+			Range:  messages.NewLineRange(33, 12, 21),
+		},
+	}, summarize(silence.References()))
+}
+
+func TestNewRemoteObject_References(t *testing.T) {
+	object, err := NewRemoteObject(v1alphaSLO.New(
+		v1alphaSLO.Metadata{Name: "slo", Project: "default"},
+		v1alphaSLO.Spec{
+			Service:       "api",
+			AlertPolicies: []string{"fast-burn", "slow-burn"},
+		},
+	))
+	require.NoError(t, err)
+	assert.Equal(t, NewObjectID(manifest.KindSLO, "slo", "default"), object.ID)
+
+	var targets []ObjectID
+	for _, ref := range object.References() {
+		targets = append(targets, ref.Target)
+	}
+	assert.Equal(t, []ObjectID{
+		NewObjectID(manifest.KindProject, "default", ""),
+		NewObjectID(manifest.KindAlertPolicy, "fast-burn", "default"),
+		NewObjectID(manifest.KindAlertPolicy, "slow-burn", "default"),
+		NewObjectID(manifest.KindService, "api", "default"),
+	}, targets)
+}
@@ -0,0 +1,267 @@
+package workspace
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/nobl9/nobl9-go/manifest"
+
+	"github.com/nobl9/nobl9-language-server/internal/files"
+	"github.com/nobl9/nobl9-language-server/internal/messages"
+	"github.com/nobl9/nobl9-language-server/internal/nobl9repo"
+	"github.com/nobl9/nobl9-language-server/internal/objectref"
+)
+
+type workspaceFiles interface {
+	GetFiles() []*files.File
+	Generation() uint64
+}
+
+type remoteObjectsProvider interface {
+	GetAllNames(ctx context.Context, kind manifest.Kind, project string) ([]string, error)
+	GetObject(ctx context.Context, kind manifest.Kind, name, project string) (manifest.Object, error)
+}
+
+// fileScopes bind the [nobl9repo.Scope] of the file's workspace folder to the context.
+// Generation changes whenever the scope of any file might have changed.
+type fileScopes interface {
+	WithFileScope(ctx context.Context, uri files.URI) context.Context
+	Generation() uint64
+}
+
+// NewObjectsResolver creates a new [ObjectsResolver].
+func NewObjectsResolver(files workspaceFiles, remote remoteObjectsProvider, scopes fileScopes) *ObjectsResolver {
+	return &ObjectsResolver{files: files, remote: remote, scopes: scopes}
+}
+
+// ObjectsResolver finds the referenced Nobl9 objects.
+// Objects defined in the workspace files take precedence over the ones fetched from the Nobl9 API,
+// this way references to the objects which were not applied yet are resolved as well.
+// Only the workspace files which share the [nobl9repo.Scope] bound to the context are considered,
+// the objects defined in the other files are applied to a different organization or project.
+type ObjectsResolver struct {
+	files  workspaceFiles
+	remote remoteObjectsProvider
+	scopes fileScopes
+
+	indexMu sync.Mutex
+	// index is built once per [workspaceFiles.Generation] and [fileScopes.Generation].
+	index *objectsIndex
+}
+
+// objectsIndex holds the decoded workspace objects grouped by the [nobl9repo.Scope] of their files and by kind.
+type objectsIndex struct {
+	filesGeneration  uint64
+	scopesGeneration uint64
+	// files are sorted by their URI, this way the objects are always visited in the same order.
+	files      []*files.File
+	fileScopes map[files.URI]nobl9repo.Scope
+	objects    map[nobl9repo.Scope]map[manifest.Kind][]localObject
+
+	referencesOnce sync.Once
+	references     []*Reference
+}
+
+type localObject struct {
+	uri  files.URI
+	node *files.ObjectNode
+}
+
+// ResolvedObject is a [manifest.Object] along with the information where it was found.
+type ResolvedObject struct {
+	manifest.Object
+	// Location points to the object's name in the file defining it.
+	// It is nil if the object was fetched from the Nobl9 API.
+	Location *messages.Location
+}
+
+// IsLocal returns true if the object is defined in the workspace.
+func (r *ResolvedObject) IsLocal() bool {
+	return r.Location != nil
+}
+
+// ResolveObject returns the object defined in the workspace or, if there's none, the one fetched from the Nobl9 API.
+// If the project is empty, project scoped objects are matched regardless of their project.
+// It returns nil if the object was not found in either place.
+func (r *ObjectsResolver) ResolveObject(
+	ctx context.Context,
+	kind manifest.Kind,
+	name, project string,
+) (*ResolvedObject, error) {
+	if object := r.findLocalObject(ctx, kind, name, project); object != nil {
+		return object, nil
+	}
+	object, err := r.remote.GetObject(ctx, kind, name, project)
+	if err != nil || object == nil {
+		return nil, err
+	}
+	return &ResolvedObject{Object: object}, nil
+}
+
+// GetObject works like [ObjectsResolver.ResolveObject] but returns just the [manifest.Object].
+func (r *ObjectsResolver) GetObject(
+	ctx context.Context,
+	kind manifest.Kind,
+	name, project string,
+) (manifest.Object, error) {
+	object, err := r.ResolveObject(ctx, kind, name, project)
+	if err != nil || object == nil {
+		return nil, err
+	}
+	return object.Object, nil
+}
+
+// GetAllNames returns the sorted names of the objects defined in the workspace and fetched from the Nobl9 API.
+// Names of the workspace objects are returned even if the Nobl9 API request fails.
+func (r *ObjectsResolver) GetAllNames(ctx context.Context, kind manifest.Kind, project string) ([]string, error) {
+	var names []string
+	r.forEachLocalObject(ctx, kind, project, func(_ files.URI, node *files.ObjectNode) bool {
+		if name := node.Object.GetName(); name != "" {
+			names = append(names, name)
+		}
+		return true
+	})
+	remoteNames, err := r.remote.GetAllNames(ctx, kind, project)
+	names = append(names, remoteNames...)
+	slices.Sort(names)
+	names = slices.Compact(names)
+	if err != nil && len(names) == 0 {
+		return nil, err
+	}
+	return names, nil
+}
+
+// FindLocalObjects returns all the definitions of the object in the workspace files, regardless of their project.
+func (r *ObjectsResolver) FindLocalObjects(ctx context.Context, kind manifest.Kind, name string) []*ResolvedObject {
+	var objects []*ResolvedObject
+	r.forEachLocalObject(ctx, kind, "", func(uri files.URI, node *files.ObjectNode) bool {
+		if node.Object.GetName() == name {
+			objects = append(objects, newLocalObject(uri, node))
+		}
+		return true
+	})
+	return objects
+}
+
+// FindLocalReferences returns all the references to the objects of the given kind made by the workspace objects.
+func (r *ObjectsResolver) FindLocalReferences(ctx context.Context, kind manifest.Kind) []*Reference {
+	scope := nobl9repo.GetScope(ctx)
+	index := r.getIndex()
+	var refs []*Reference
+	for _, ref := range index.getReferences() {
+		if ref.Target.Kind == kind && index.fileScopes[ref.Source.URI] == scope {
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+func (r *ObjectsResolver) findLocalObject(
+	ctx context.Context,
+	kind manifest.Kind,
+	name, project string,
+) *ResolvedObject {
+	var resolved *ResolvedObject
+	r.forEachLocalObject(ctx, kind, project, func(uri files.URI, node *files.ObjectNode) bool {
+		if node.Object.GetName() != name {
+			return true
+		}
+		resolved = newLocalObject(uri, node)
+		return false
+	})
+	return resolved
+}
+
+// forEachLocalObject calls fn for every decoded workspace object of the given kind and project
+// which is defined in the files sharing the [nobl9repo.Scope] bound to the context, until fn returns false.
+// Files are visited in a deterministic order.
+func (r *ObjectsResolver) forEachLocalObject(
+	ctx context.Context,
+	kind manifest.Kind,
+	project string,
+	fn func(uri files.URI, node *files.ObjectNode) bool,
+) {
+	for _, object := range r.getIndex().objects[nobl9repo.GetScope(ctx)][kind] {
+		if project != "" && objectref.IsProjectScoped(kind) && getObjectProject(object.node.Object) != project {
+			continue
+		}
+		if !fn(object.uri, object.node) {
+			return
+		}
+	}
+}
+
+// getIndex returns the [objectsIndex] of the current workspace files and scopes.
+// The index is built only once per workspace files and scopes generation.
+func (r *ObjectsResolver) getIndex() *objectsIndex {
+	r.indexMu.Lock()
+	defer r.indexMu.Unlock()
+	// The generations are read before the files and scopes,
+	// this way these are never older than the cached generations.
+	filesGeneration, scopesGeneration := r.files.Generation(), r.scopes.Generation()
+	if r.index != nil &&
+		r.index.filesGeneration == filesGeneration &&
+		r.index.scopesGeneration == scopesGeneration {
+		return r.index
+	}
+	fileList := r.files.GetFiles()
+	slices.SortFunc(fileList, func(f1, f2 *files.File) int { return cmp.Compare(f1.URI, f2.URI) })
+	index := &objectsIndex{
+		filesGeneration:  filesGeneration,
+		scopesGeneration: scopesGeneration,
+		files:            fileList,
+		fileScopes:       make(map[files.URI]nobl9repo.Scope, len(fileList)),
+		objects:          make(map[nobl9repo.Scope]map[manifest.Kind][]localObject),
+	}
+	for _, file := range fileList {
+		// The file's scope does not depend on the scope bound to the context.
+		scope := nobl9repo.GetScope(r.scopes.WithFileScope(context.Background(), file.URI))
+		index.fileScopes[file.URI] = scope
+		if file.Skip || file.Err != nil {
+			continue
+		}
+		for _, node := range file.Objects {
+			if node.Object == nil || node.Node == nil {
+				continue
+			}
+			if index.objects[scope] == nil {
+				index.objects[scope] = make(map[manifest.Kind][]localObject)
+			}
+			index.objects[scope][node.Kind] = append(index.objects[scope][node.Kind], localObject{
+				uri:  file.URI,
+				node: node,
+			})
+		}
+	}
+	r.index = index
+	return index
+}
+
+// getReferences returns the references to the objects (not their objectives) made by all the workspace objects.
+// The references are collected lazily, only once per index.
+func (i *objectsIndex) getReferences() []*Reference {
+	i.referencesOnce.Do(func() {
+		for _, object := range NewSnapshot(i.files).Objects() {
+			for _, ref := range object.References() {
+				if ref.Objective == "" {
+					i.references = append(i.references, ref)
+				}
+			}
+		}
+	})
+	return i.references
+}
+
+func newLocalObject(uri files.URI, node *files.ObjectNode) *ResolvedObject {
+	location := newObject(uri, node).Location()
+	return &ResolvedObject{Object: node.Object, Location: &location}
+}
+
+func getObjectProject(object manifest.Object) string {
+	if projectScoped, ok := object.(manifest.ProjectScopedObject); ok {
+		return projectScoped.GetProject()
+	}
+	return ""
+}
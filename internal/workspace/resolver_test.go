@@ -0,0 +1,221 @@
+package workspace
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/nobl9/nobl9-go/manifest"
+	v1alphaService "github.com/nobl9/nobl9-go/manifest/v1alpha/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nobl9/nobl9-language-server/internal/files"
+	"github.com/nobl9/nobl9-language-server/internal/messages"
+	"github.com/nobl9/nobl9-language-server/internal/nobl9repo"
+)
+
+func TestObjectsResolver(t *testing.T) {
+	ctx := context.Background()
+	fileSystem := files.NewFS(nil)
+	require.NoError(t, fileSystem.OpenFile(ctx, "file:///services.yaml", `apiVersion: n9/v1alpha
+kind: Service
+metadata:
+  name: api
+  project: default
+---
+apiVersion: n9/v1alpha
+kind: Service
+metadata:
+  name: web
+  project: other
+`, 1))
+	_, err := fileSystem.IndexFile(ctx, "file:///project.yaml", `apiVersion: n9/v1alpha
+kind: Project
+metadata:
+  name: default
+`)
+	require.NoError(t, err)
+	_, err = fileSystem.IndexFile(ctx, "file:///other-context/services.yaml", `apiVersion: n9/v1alpha
+kind: Service
+metadata:
+  name: web
+  project: default
+`)
+	require.NoError(t, err)
+	scopes := mockFileScopes{"file:///other-context/": "other"}
+	remote := &mockRemoteObjectsProvider{
+		objects: []manifest.Object{
+			v1alphaService.New(v1alphaService.Metadata{Name: "db", Project: "default"}, v1alphaService.Spec{}),
+			v1alphaService.New(v1alphaService.Metadata{Name: "api", Project: "default"}, v1alphaService.Spec{}),
+		},
+	}
+	resolver := NewObjectsResolver(fileSystem, remote, scopes)
+
+	t.Run("resolve workspace object", func(t *testing.T) {
+		object, err := resolver.ResolveObject(ctx, manifest.KindService, "api", "default")
+		require.NoError(t, err)
+		require.NotNil(t, object)
+		assert.True(t, object.IsLocal())
+		assert.Equal(t, &messages.Location{
+			URI:   "file:///services.yaml",
+			Range: messages.NewLineRange(4, 8, 11),
+		}, object.Location)
+		assert.Equal(t, "api", object.GetName())
+	})
+	t.Run("resolve object which is not project scoped", func(t *testing.T) {
+		object, err := resolver.ResolveObject(ctx, manifest.KindProject, "default", "other")
+		require.NoError(t, err)
+		require.NotNil(t, object)
+		assert.True(t, object.IsLocal())
+	})
+	t.Run("resolve remote object", func(t *testing.T) {
+		object, err := resolver.ResolveObject(ctx, manifest.KindService, "db", "default")
+		require.NoError(t, err)
+		require.NotNil(t, object)
+		assert.False(t, object.IsLocal())
+		assert.Equal(t, "db", object.GetName())
+	})
+	t.Run("object defined in a different project", func(t *testing.T) {
+		object, err := resolver.ResolveObject(ctx, manifest.KindService, "web", "default")
+		require.NoError(t, err)
+		assert.Nil(t, object)
+	})
+	t.Run("object defined in a different scope", func(t *testing.T) {
+		scopeCtx := nobl9repo.WithScope(ctx, nobl9repo.Scope{ConfigContext: "other"})
+		object, err := resolver.ResolveObject(scopeCtx, manifest.KindService, "web", "default")
+		require.NoError(t, err)
+		require.NotNil(t, object)
+		assert.Equal(t, "file:///other-context/services.yaml", object.Location.URI)
+
+		assert.Empty(t, resolver.FindLocalObjects(scopeCtx, manifest.KindService, "api"))
+	})
+	t.Run("find local objects", func(t *testing.T) {
+		objects := resolver.FindLocalObjects(ctx, manifest.KindService, "web")
+		require.Len(t, objects, 1)
+		assert.Equal(t, "file:///services.yaml", objects[0].Location.URI)
+
+		assert.Empty(t, resolver.FindLocalObjects(ctx, manifest.KindService, "db"))
+	})
+	t.Run("find local references", func(t *testing.T) {
+		refs := resolver.FindLocalReferences(ctx, manifest.KindProject)
+		require.Len(t, refs, 2)
+		assert.Equal(t, NewObjectID(manifest.KindProject, "default", ""), refs[0].Target)
+		assert.Equal(t, NewObjectID(manifest.KindProject, "other", ""), refs[1].Target)
+
+		assert.Empty(t, resolver.FindLocalReferences(ctx, manifest.KindAlertPolicy))
+	})
+	t.Run("get object", func(t *testing.T) {
+		object, err := resolver.GetObject(ctx, manifest.KindService, "web", "")
+		require.NoError(t, err)
+		assert.IsType(t, v1alphaService.Service{}, object)
+
+		object, err = resolver.GetObject(ctx, manifest.KindService, "missing", "")
+		require.NoError(t, err)
+		assert.Nil(t, object)
+	})
+	t.Run("get all names", func(t *testing.T) {
+		names, err := resolver.GetAllNames(ctx, manifest.KindService, "default")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"api", "db"}, names)
+	})
+	t.Run("get all names when the API fails", func(t *testing.T) {
+		resolver := NewObjectsResolver(fileSystem, &mockRemoteObjectsProvider{err: errors.New("failed")}, scopes)
+		names, err := resolver.GetAllNames(ctx, manifest.KindService, "")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"api", "web"}, names)
+
+		_, err = resolver.GetAllNames(ctx, manifest.KindAgent, "")
+		require.Error(t, err)
+	})
+	t.Run("find local objects after the scopes changed", func(t *testing.T) {
+		scopes := &generationFileScopes{mockFileScopes: mockFileScopes{}}
+		resolver := NewObjectsResolver(fileSystem, remote, scopes)
+		require.Len(t, resolver.FindLocalObjects(ctx, manifest.KindService, "web"), 2)
+
+		scopes.mockFileScopes = mockFileScopes{"file:///other-context/": "other"}
+		require.Len(t, resolver.FindLocalObjects(ctx, manifest.KindService, "web"), 2)
+		scopes.generation++
+		assert.Len(t, resolver.FindLocalObjects(ctx, manifest.KindService, "web"), 1)
+	})
+	t.Run("find local references after the workspace changed", func(t *testing.T) {
+		require.Len(t, resolver.FindLocalReferences(ctx, manifest.KindProject), 2)
+
+		require.NoError(t, fileSystem.CloseFile("file:///services.yaml"))
+		assert.Empty(t, resolver.FindLocalReferences(ctx, manifest.KindProject))
+	})
+}
+
+// mockFileScopes binds the configuration context to the files with the given URI prefixes.
+type mockFileScopes map[string]string
+
+func (m mockFileScopes) WithFileScope(ctx context.Context, uri files.URI) context.Context {
+	for prefix, configContext := range m {
+		if strings.HasPrefix(uri, prefix) {
+			return nobl9repo.WithScope(ctx, nobl9repo.Scope{ConfigContext: configContext})
+		}
+	}
+	return nobl9repo.WithScope(ctx, nobl9repo.Scope{})
+}
+
+func (m mockFileScopes) Generation() uint64 { return 0 }
+
+// generationFileScopes works like [mockFileScopes], but its generation is controlled by the test.
+type generationFileScopes struct {
+	mockFileScopes
+	generation uint64
+}
+
+func (g *generationFileScopes) Generation() uint64 { return g.generation }
+
+type mockRemoteObjectsProvider struct {
+	objects []manifest.Object
+	err     error
+}
+
+func (m *mockRemoteObjectsProvider) GetAllNames(
+	_ context.Context,
+	kind manifest.Kind,
+	project string,
+) ([]string, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	var names []string
+	for _, object := range m.filter(kind, project) {
+		names = append(names, object.GetName())
+	}
+	return names, nil
+}
+
+func (m *mockRemoteObjectsProvider) GetObject(
+	_ context.Context,
+	kind manifest.Kind,
+	name, project string,
+) (manifest.Object, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	for _, object := range m.filter(kind, project) {
+		if object.GetName() == name {
+			return object, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockRemoteObjectsProvider) filter(kind manifest.Kind, project string) []manifest.Object {
+	var objects []manifest.Object
+	for _, object := range m.objects {
+		if object.GetKind() != kind {
+			continue
+		}
+		if projectScoped, ok := object.(manifest.ProjectScopedObject); ok && project != "" &&
+			projectScoped.GetProject() != project {
+			continue
+		}
+		objects = append(objects, object)
+	}
+	return objects
+}
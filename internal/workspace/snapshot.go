@@ -0,0 +1,138 @@
+package workspace
+
+import (
+	"cmp"
+	"slices"
+	"sync"
+
+	"github.com/nobl9/nobl9-language-server/internal/files"
+	"github.com/nobl9/nobl9-language-server/internal/messages"
+)
+
+// NewSnapshot creates a [Snapshot] from the provided files.
+// Skipped files and files which could not be parsed are ignored.
+// Objects are ordered by their file URI and position in the file.
+func NewSnapshot(fileList []*files.File) *Snapshot {
+	fileList = slices.SortedFunc(slices.Values(fileList), func(f1, f2 *files.File) int {
+		return cmp.Compare(f1.URI, f2.URI)
+	})
+	snapshot := &Snapshot{
+		objectsByURI: make(map[files.URI][]*Object, len(fileList)),
+		files:        make(map[files.URI]*files.File, len(fileList)),
+		mappers:      make(map[files.URI]*files.PositionMapper),
+	}
+	for _, file := range fileList {
+		snapshot.files[file.URI] = file
+		if file.Skip || file.Err != nil {
+			continue
+		}
+		for _, node := range file.Objects {
+			if node.Kind == 0 || node.Node == nil {
+				continue
+			}
+			object := newObject(file.URI, node)
+			snapshot.objects = append(snapshot.objects, object)
+			snapshot.objectsByURI[file.URI] = append(snapshot.objectsByURI[file.URI], object)
+		}
+	}
+	return snapshot
+}
+
+// Snapshot is a point-in-time view of all Nobl9 objects defined in the workspace files.
+// It is safe for concurrent use.
+type Snapshot struct {
+	objects      []*Object
+	objectsByURI map[files.URI][]*Object
+	files        map[files.URI]*files.File
+	mappers      map[files.URI]*files.PositionMapper
+	mappersMu    sync.Mutex
+	// references are resolved once, when first needed.
+	references     []*Reference
+	referencesOnce sync.Once
+}
+
+// ToClientLocation converts the location's range to the position encoding negotiated with the client.
+func (s *Snapshot) ToClientLocation(location messages.Location) messages.Location {
+	location.Range = s.ToClientRange(location.URI, location.Range)
+	return location
+}
+
+// ToClientRange converts the range within the file to the position encoding negotiated with the client.
+func (s *Snapshot) ToClientRange(uri files.URI, rng messages.Range) messages.Range {
+	if mapper := s.getPositionMapper(uri); mapper != nil {
+		return mapper.ToClientRange(rng)
+	}
+	return rng
+}
+
+func (s *Snapshot) getPositionMapper(uri files.URI) *files.PositionMapper {
+	s.mappersMu.Lock()
+	defer s.mappersMu.Unlock()
+	if mapper, ok := s.mappers[uri]; ok {
+		return mapper
+	}
+	file, ok := s.files[uri]
+	if !ok {
+		return nil
+	}
+	mapper := file.GetPositionMapper()
+	s.mappers[uri] = mapper
+	return mapper
+}
+
+// Objects returns all objects in the [Snapshot].
+func (s *Snapshot) Objects() []*Object {
+	return s.objects
+}
+
+// FileObjects returns all objects defined in the given file.
+func (s *Snapshot) FileObjects(uri files.URI) []*Object {
+	return s.objectsByURI[uri]
+}
+
+// FindObjects returns all objects matching the [ObjectID].
+func (s *Snapshot) FindObjects(id ObjectID) []*Object {
+	var objects []*Object
+	for _, object := range s.objects {
+		if object.ID == id {
+			objects = append(objects, object)
+		}
+	}
+	return objects
+}
+
+// FindReferences returns all references to the object identified by [ObjectID].
+// If objective is not empty, it returns references to this specific SLO objective instead.
+func (s *Snapshot) FindReferences(id ObjectID, objective string) []*Reference {
+	var refs []*Reference
+	for _, ref := range s.References() {
+		if ref.Target == id && ref.Objective == objective {
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+// References returns all references defined by the objects in the [Snapshot].
+func (s *Snapshot) References() []*Reference {
+	s.referencesOnce.Do(func() {
+		for _, object := range s.objects {
+			s.references = append(s.references, object.References()...)
+		}
+	})
+	return s.references
+}
+
+// FindValue returns the [Object] and its [Value] located at the given position in the file.
+// If there's no value at the position, the returned [Value] is nil.
+func (s *Snapshot) FindValue(uri files.URI, pos messages.Position) (*Object, *Value) {
+	// Objects' lines are 1-based.
+	line := pos.Line + 1
+	for _, object := range s.objectsByURI[uri] {
+		if line < object.Node.Node.StartLine || line > object.Node.Node.EndLine {
+			continue
+		}
+		return object, object.FindValue(pos)
+	}
+	return nil, nil
+}
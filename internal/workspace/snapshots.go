@@ -0,0 +1,31 @@
+package workspace
+
+import "sync"
+
+// NewSnapshots creates [Snapshots] of the workspace files.
+func NewSnapshots(files workspaceFiles) *Snapshots {
+	return &Snapshots{files: files}
+}
+
+// Snapshots shares a single [Snapshot] of the workspace files between the requests.
+// The [Snapshot] is created again only once the workspace files change.
+type Snapshots struct {
+	files      workspaceFiles
+	snapshot   *Snapshot
+	generation uint64
+	mu         sync.Mutex
+}
+
+// Get returns the [Snapshot] of the current workspace files.
+func (s *Snapshots) Get() *Snapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// The generation is read before the files, this way the files are never older than the cached generation.
+	generation := s.files.Generation()
+	if s.snapshot != nil && s.generation == generation {
+		return s.snapshot
+	}
+	s.snapshot = NewSnapshot(s.files.GetFiles())
+	s.generation = generation
+	return s.snapshot
+}
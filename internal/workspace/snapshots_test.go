@@ -0,0 +1,29 @@
+package workspace
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nobl9/nobl9-language-server/internal/files"
+)
+
+func TestSnapshots_Get(t *testing.T) {
+	ctx := context.Background()
+	fileSystem := files.NewFS(nil)
+	project := "apiVersion: n9/v1alpha\nkind: Project\nmetadata:\n  name: default\n"
+	require.NoError(t, fileSystem.OpenFile(ctx, "file:///project.yaml", project, 1))
+	snapshots := NewSnapshots(fileSystem)
+
+	snapshot := snapshots.Get()
+	require.Len(t, snapshot.Objects(), 1)
+	assert.Same(t, snapshot, snapshots.Get())
+
+	_, err := fileSystem.IndexFile(ctx, "file:///other.yaml", project)
+	require.NoError(t, err)
+	updated := snapshots.Get()
+	assert.NotSame(t, snapshot, updated)
+	assert.Len(t, updated.Objects(), 2)
+}
@@ -82,47 +82,63 @@ func (l *Line) GetColonIndex() int {
 
 // GetKeyPos returns the start and end position of the key on the given line.
 // End position is exclusive, while start is inclusive.
+// Positions are counted in Unicode code points.
 func (l *Line) GetKeyPos() (start, end int) {
 	if !l.IsType(LineTypeMapping) && !l.IsType(LineTypeUndefined) {
 		return 0, 0
 	}
-	if l.valueColonIdx != -1 {
-		return l.indent, l.indent + l.valueColonIdx
-	}
-	return l.indent, l.indent + len(l.value)
+	return l.toCharacterPos(l.getKeyOffsets())
 }
 
 // GetValuePos returns the start and end position of the value on the given line.
 // End position is exclusive, while start is inclusive.
+// Positions are counted in Unicode code points.
 func (l *Line) GetValuePos() (start, end int) {
 	if !l.HasMapValue() {
 		return 0, 0
 	}
-	return l.indent + l.valueColonIdx + 2, l.indent + len(l.value)
+	return l.toCharacterPos(l.getValueOffsets())
 }
 
 // GetMapKey returns the key of the mapping line.
 func (l *Line) GetMapKey() string {
-	start, end := l.GetKeyPos()
-	start -= l.indent
-	end -= l.indent
-	if start < 0 || end < 0 {
+	if !l.IsType(LineTypeMapping) && !l.IsType(LineTypeUndefined) {
 		return ""
 	}
+	start, end := l.getKeyOffsets()
 	return l.value[start:end]
 }
 
 // GetMapValue returns the value of the mapping line.
 func (l *Line) GetMapValue() string {
-	start, end := l.GetValuePos()
-	start -= l.indent
-	end -= l.indent
-	if start < 0 || end < 0 {
+	if !l.HasMapValue() {
 		return ""
 	}
+	start, end := l.getValueOffsets()
 	return l.value[start:end]
 }
 
+// getKeyOffsets returns the byte offsets of the key within the line's value.
+func (l *Line) getKeyOffsets() (start, end int) {
+	if l.valueColonIdx != -1 {
+		return 0, l.valueColonIdx
+	}
+	return 0, len(l.value)
+}
+
+// getValueOffsets returns the byte offsets of the value within the line's value.
+func (l *Line) getValueOffsets() (start, end int) {
+	return l.valueColonIdx + 2, len(l.value)
+}
+
+// toCharacterPos converts byte offsets within the line's value to positions within the whole line.
+// Indentation consists of ASCII characters only, so it needs no conversion.
+func (l *Line) toCharacterPos(startOffset, endOffset int) (start, end int) {
+	start = l.indent + utf8.RuneCountInString(l.value[:startOffset])
+	end = start + utf8.RuneCountInString(l.value[startOffset:endOffset])
+	return start, end
+}
+
 func (l *Line) addType(typ LineType) {
 	l.Type |= typ
 }
@@ -576,6 +576,7 @@ func TestLine_GetMapValue(t *testing.T) {
 		{"metadata:\n  name: this", 1, "this"},
 		{"metadata:\n  name:", 1, ""},
 		{"metadata:\n  name:1", 1, ""},
+		{"labels:\n  zespół: żółw", 1, "żółw"},
 	}
 	for _, tc := range tests {
 		file := ParseFile(tc.in)
@@ -593,6 +594,7 @@ func TestLine_GetMapKey(t *testing.T) {
 		{"metadata:\n  name", 1, "name"},
 		{"metadata:\n  name: this", 1, "name"},
 		{"metadata:\n  name:", 1, "name"},
+		{"labels:\n  zespół: żółw", 1, "zespół"},
 	}
 	for _, tc := range tests {
 		file := ParseFile(tc.in)
@@ -614,6 +616,7 @@ func TestLine_GetKeyPos(t *testing.T) {
 		{"metadata:\n#  name:", 1, 0, 0},
 		{"- metadata: name", 0, 2, 10},
 		{"- metadata:\n  - name: foo", 1, 4, 8},
+		{"labels:\n  zespół: żółw", 1, 2, 8},
 	}
 	for _, tc := range tests {
 		file := ParseFile(tc.in)
@@ -639,6 +642,8 @@ func TestLine_GetValuePos(t *testing.T) {
 		{"metadata:\n  name:", 1, 0, 0},
 		{"- metadata: name", 0, 12, 16},
 		{"- metadata:\n  - name: foo", 1, 10, 13},
+		{"labels:\n  zespół: żółw", 1, 10, 14},
+		{"metadata:\n  displayName: チーム 🐢", 1, 15, 20},
 	}
 	for _, tc := range tests {
 		file := ParseFile(tc.in)
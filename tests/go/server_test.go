@@ -56,6 +56,7 @@ func TestLSP(t *testing.T) {
 	t.Setenv("NOBL9_LANGUAGE_SERVER_NO_CONFIG_FILE", "true")
 	t.Setenv("NOBL9_LANGUAGE_SERVER_CLIENT_ID", "fake-id")
 	t.Setenv("NOBL9_LANGUAGE_SERVER_CLIENT_SECRET", "fake-secret")
+	t.Setenv("NOBL9_LANGUAGE_SERVER_WARM_UP_CACHE", "false")
 
 	ctx, cancel := context.WithCancel(context.Background())
 
@@ -75,7 +76,78 @@ func TestLSP(t *testing.T) {
 		}
 	})
 
+	// Diagnostics of all the opened files are published again whenever any of them changes,
+	// since they might reference each other's objects.
+	invalidServiceDiagnostics := messages.PublishDiagnosticsParams{
+		URI:     getTestFileURI("invalid-service.yaml"),
+		Version: 2,
+		Diagnostics: []messages.Diagnostic{
+			{
+				Message:  "metadata.project: property is required but was empty",
+				Severity: messages.DiagnosticSeverityError,
+				Source:   ptr("nobl9-language-server"),
+				Range: messages.Range{
+					Start: messages.Position{Line: 2, Character: 0},
+					End:   messages.Position{Line: 2, Character: 8},
+				},
+			},
+		},
+	}
+	completionDiagnostics := messages.PublishDiagnosticsParams{
+		URI:     getTestFileURI("completion.yaml"),
+		Version: 1,
+		Diagnostics: []messages.Diagnostic{
+			{
+				Message:  "property is required but was empty",
+				Severity: messages.DiagnosticSeverityError,
+				Source:   ptr("nobl9-language-server"),
+				Range: messages.Range{
+					Start: messages.Position{
+						Line:      7,
+						Character: 2,
+					},
+					End: messages.Position{
+						Line:      7,
+						Character: 17,
+					},
+				},
+			},
+			{
+				Message:  fmt.Sprintf("S is not a valid Kind, try [%s]", strings.Join(manifest.KindNames(), ", ")),
+				Severity: messages.DiagnosticSeverityError,
+				Source:   ptr("nobl9-language-server"),
+				Range: messages.Range{
+					Start: messages.Position{
+						Line:      32,
+						Character: 0,
+					},
+					End: messages.Position{
+						Line:      32,
+						Character: 0,
+					},
+				},
+			},
+		},
+	}
+
 	tests := []TestCase{
+		{
+			Scenario: "request before initialize",
+			Request: TestCaseRequest{
+				ID:     0,
+				Method: messages.DocumentSymbolMethod,
+				Params: messages.DocumentSymbolParams{
+					TextDocument: messages.TextDocumentIdentifier{URI: getTestFileURI("valid-project.yaml")},
+				},
+			},
+			Response: TestCaseResponse{
+				ID: 0,
+				Error: &jsonrpc2.Error{
+					Code:    messages.CodeServerNotInitialized,
+					Message: "server is not initialized",
+				},
+			},
+		},
 		{
 			Scenario: "initialize connection",
 			Request: TestCaseRequest{
@@ -83,22 +155,57 @@ func TestLSP(t *testing.T) {
 				Method: messages.InitializeMethod,
 				Params: messages.InitializeParams{
 					ClientInfo: &messages.ClientInfo{Name: "test"},
+					Capabilities: messages.ClientCapabilities{
+						TextDocument: &messages.TextDocumentClientCapabilities{
+							Hover: &messages.HoverClientCapabilities{
+								ContentFormat: []messages.MarkupKind{messages.Markdown, messages.PlainText},
+							},
+						},
+					},
 				},
 			},
 			Response: TestCaseResponse{
 				ID: 1,
 				Result: messages.InitializeResponse{
 					Capabilities: messages.ServerCapabilities{
-						TextDocumentSync: messages.TextDocumentSyncKindFull,
+						PositionEncoding: messages.PositionEncodingUTF16,
+						TextDocumentSync: messages.TextDocumentSyncKindIncremental,
 						CompletionProvider: &messages.CompletionProvider{
 							ResolveProvider:   false,
 							TriggerCharacters: []string{":"},
 						},
-						HoverProvider:      true,
-						CodeActionProvider: true,
+						HoverProvider:              true,
+						CodeActionProvider:         true,
+						DefinitionProvider:         true,
+						ReferencesProvider:         true,
+						DocumentSymbolProvider:     true,
+						WorkspaceSymbolProvider:    true,
+						RenameProvider:             &messages.RenameOptions{PrepareProvider: true},
+						DocumentFormattingProvider: true,
+						RangeFormattingProvider:    true,
+						FoldingRangeProvider:       true,
+						SemanticTokensProvider: &messages.SemanticTokensOptions{
+							Legend: messages.SemanticTokensLegend{
+								TokenTypes:     []string{"type", "class", "enumMember", "property"},
+								TokenModifiers: []string{"declaration", "deprecated", "secret"},
+							},
+							Range: true,
+							Full:  true,
+						},
+						InlayHintProvider: true,
+						DiagnosticProvider: &messages.DiagnosticOptions{
+							InterFileDependencies: true,
+							WorkspaceDiagnostics:  true,
+						},
 						ExecuteCommandProvider: &messages.ExecuteCommandProvider{
 							Commands: []string{"APPLY", "APPLY_DRY_RUN", "DELETE"},
 						},
+						Workspace: &messages.ServerCapabilitiesWorkspace{
+							WorkspaceFolders: messages.WorkspaceFoldersServerCapabilities{
+								Supported:           true,
+								ChangeNotifications: true,
+							},
+						},
 					},
 					ServerInfo: messages.ServerInfo{
 						Name:    "nobl9-language-server",
@@ -316,6 +423,34 @@ func TestLSP(t *testing.T) {
 				},
 			},
 		},
+		{
+			Scenario: "incremental changes - fix invalid name",
+			Request: TestCaseRequest{
+				ID:     10,
+				Method: messages.DidChangeMethod,
+				Params: messages.DidChangeParams{
+					TextDocument: messages.VersionedTextDocumentIdentifier{
+						TextDocumentIdentifier: messages.TextDocumentIdentifier{
+							URI: getTestFileURI("invalid-service.yaml"),
+						},
+						Version: 2,
+					},
+					ContentChanges: []messages.TextDocumentContentChangeEvent{
+						{Range: ptr(messages.NewLineRange(4, 8, 15)), Text: "fixed"},
+						{Range: ptr(messages.NewLineRange(4, 13, 14)), Text: "-"},
+					},
+				},
+			},
+			Response: TestCaseResponse{
+				ID: 10,
+			},
+			ServerRequests: []TestCaseRequest{
+				{
+					Method: messages.PublishDiagnosticsMethod,
+					Params: invalidServiceDiagnostics,
+				},
+			},
+		},
 		{
 			Scenario: "open file still in creation",
 			Request: TestCaseRequest{
@@ -336,42 +471,11 @@ func TestLSP(t *testing.T) {
 			ServerRequests: []TestCaseRequest{
 				{
 					Method: messages.PublishDiagnosticsMethod,
-					Params: messages.PublishDiagnosticsParams{
-						URI:     getTestFileURI("completion.yaml"),
-						Version: 1,
-						Diagnostics: []messages.Diagnostic{
-							{
-								Message:  "property is required but was empty",
-								Severity: messages.DiagnosticSeverityError,
-								Source:   ptr("nobl9-language-server"),
-								Range: messages.Range{
-									Start: messages.Position{
-										Line:      7,
-										Character: 2,
-									},
-									End: messages.Position{
-										Line:      7,
-										Character: 17,
-									},
-								},
-							},
-							{
-								Message:  fmt.Sprintf("S is not a valid Kind, try [%s]", strings.Join(manifest.KindNames(), ", ")),
-								Severity: messages.DiagnosticSeverityError,
-								Source:   ptr("nobl9-language-server"),
-								Range: messages.Range{
-									Start: messages.Position{
-										Line:      32,
-										Character: 0,
-									},
-									End: messages.Position{
-										Line:      32,
-										Character: 0,
-									},
-								},
-							},
-						},
-					},
+					Params: completionDiagnostics,
+				},
+				{
+					Method: messages.PublishDiagnosticsMethod,
+					Params: invalidServiceDiagnostics,
 				},
 			},
 		},
@@ -479,6 +583,41 @@ func TestLSP(t *testing.T) {
 						},
 					},
 				},
+				{
+					Method: messages.PublishDiagnosticsMethod,
+					Params: invalidServiceDiagnostics,
+				},
+				{
+					Method: messages.PublishDiagnosticsMethod,
+					Params: completionDiagnostics,
+				},
+			},
+		},
+		{
+			Scenario: "shutdown",
+			Request: TestCaseRequest{
+				ID:     11,
+				Method: messages.ShutdownMethod,
+			},
+			Response: TestCaseResponse{
+				ID: 11,
+			},
+		},
+		{
+			Scenario: "request after shutdown",
+			Request: TestCaseRequest{
+				ID:     12,
+				Method: messages.DocumentSymbolMethod,
+				Params: messages.DocumentSymbolParams{
+					TextDocument: messages.TextDocumentIdentifier{URI: getTestFileURI("barebones-object.yaml")},
+				},
+			},
+			Response: TestCaseResponse{
+				ID: 12,
+				Error: &jsonrpc2.Error{
+					Code:    jsonrpc2.CodeInvalidRequest,
+					Message: "server is shutting down",
+				},
 			},
 		},
 	}
@@ -10,6 +10,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"reflect"
 	"slices"
 	"testing"
 
@@ -144,22 +145,38 @@ func (c *jsonRPCClient) AssertServerRequest(t *testing.T, method string, expecte
 	t.Helper()
 	msg := fmt.Sprintf("server request for method %s", method)
 
+	jsonExpectedReq, err := json.Marshal(expected)
+	require.NoError(t, err, msg)
+	// The server requests might arrive in any order, e.g. diagnostics of different files.
+	var candidate *jsonrpc2.Request
 	for i, req := range c.serverRequests {
 		if req.Method != method {
 			continue
 		}
-
 		require.NotNil(t, req.Params, msg)
-		jsonExpectedReq, err := json.Marshal(expected)
-		require.NoError(t, err, msg)
-		require.JSONEq(t, string(jsonExpectedReq), string(*req.Params), msg)
-
+		if candidate == nil {
+			candidate = req
+		}
+		if !jsonEqual(jsonExpectedReq, *req.Params) {
+			continue
+		}
 		c.serverRequests = slices.Delete(c.serverRequests, i, i+1)
 		return
 	}
+	if candidate != nil {
+		require.JSONEq(t, string(jsonExpectedReq), string(*candidate.Params), msg)
+	}
 	t.Fatalf("not found: %s", msg)
 }
 
+func jsonEqual(expected, actual []byte) bool {
+	var expectedValue, actualValue any
+	if json.Unmarshal(expected, &expectedValue) != nil || json.Unmarshal(actual, &actualValue) != nil {
+		return false
+	}
+	return reflect.DeepEqual(expectedValue, actualValue)
+}
+
 // ReadMessages reads n JSON RPC messages from the stream.
 // Since the stream may contain asynchronous requests to the RPC client made by the server
 // we need to read from the stream until we find the first response.